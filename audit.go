@@ -0,0 +1,276 @@
+package becc
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// minAuditSecurity is the minimum rho security, in bits, that AuditCurve
+// accepts for the curve and for its quadratic twist (SafeCurves criterion).
+const minAuditSecurity = 100
+
+// CurveAudit is the security report of a short Weierstrass curve produced by
+// AuditCurve.
+type CurveAudit struct {
+	// Discriminant is -16(4a^3 + 27b^2) mod p.
+	Discriminant *big.Int
+
+	// PointCount is #E(F_p) and Trace is the Frobenius trace p + 1 - #E.
+	PointCount *big.Int
+	Trace      *big.Int
+
+	// Factors is the prime factorization of #E in ascending order, with
+	// multiplicity. When Factored is false the last factor is a composite
+	// that could not be split.
+	Factors  []*big.Int
+	Factored bool
+
+	// Order is the largest prime factor n of #E and Cofactor is #E / n.
+	Order    *big.Int
+	Cofactor *big.Int
+
+	// EmbeddingDegree is the smallest k with n | p^k - 1, or 0 if it is not
+	// smaller than 100.
+	EmbeddingDegree int
+
+	// Anomalous reports whether n = p (Smart's attack) and Supersingular
+	// whether the trace is zero modulo p.
+	Anomalous     bool
+	Supersingular bool
+
+	// Security is the approximate cost, in bits, of Pollard's rho on the
+	// subgroup of order n.
+	Security int
+
+	// TwistPointCount is the number of points of the quadratic twist,
+	// 2(p + 1) - #E. The twist fields have the same meaning as the curve
+	// ones; when TwistFactored is false TwistSecurity is an upper bound.
+	TwistPointCount *big.Int
+	TwistFactors    []*big.Int
+	TwistFactored   bool
+	TwistSecurity   int
+
+	// Issues lists every failed criterion. The curve is considered safe when
+	// it is empty.
+	Issues []string
+}
+
+// Safe reports whether the curve passed every check of the audit.
+func (a CurveAudit) Safe() bool {
+	return len(a.Issues) == 0
+}
+
+// AuditCurve reports the security properties of y^2 = x^3 + ax + b over F_p.
+// pointCount is #E(F_p); when nil it is computed with Order, which takes
+// about half a minute for 256-bit fields and tens of minutes for larger ones.
+// The parameters are rejected with ErrInvalidParameters when the curve is
+// singular.
+func AuditCurve(a, b, p, pointCount *big.Int) (CurveAudit, error) {
+	return auditCurve(a, b, p, pointCount, minAuditSecurity)
+}
+
+// auditCurve implements AuditCurve with minSecurity as the minimum rho
+// security of the curve and its twist.
+func auditCurve(a, b, p, pointCount *big.Int, minSecurity int) (CurveAudit, error) {
+	if p.Cmp(big.NewInt(3)) <= 0 || !p.ProbablyPrime(20) {
+		return CurveAudit{}, fmt.Errorf("%w: field modulus is not a prime greater than 3", ErrInvalidParameters)
+	}
+
+	ec, err := NewEllipticCurve(a, b, p)
+	if err != nil {
+		return CurveAudit{}, err
+	}
+
+	if pointCount == nil {
+		if pointCount, err = ec.Order(); err != nil {
+			return CurveAudit{}, err
+		}
+	}
+
+	report := CurveAudit{
+		Discriminant: ec.Discriminant(),
+		PointCount:   new(big.Int).Set(pointCount),
+		Trace:        new(big.Int).Sub(new(big.Int).Add(p, bi1), pointCount),
+	}
+
+	// Hasse: (p + 1 - #E)^2 <= 4p
+	if new(big.Int).Mul(report.Trace, report.Trace).Cmp(new(big.Int).Mul(p, bi4)) > 0 {
+		return CurveAudit{}, fmt.Errorf("%w: point count outside the Hasse bound", ErrInvalidParameters)
+	}
+
+	report.Factors, report.Factored = factorize(pointCount)
+	report.Order = report.Factors[len(report.Factors)-1]
+	report.Cofactor = new(big.Int).Div(pointCount, report.Order)
+	report.Security = rhoSecurity(report.Order)
+	report.EmbeddingDegree = embeddingDegree(p, report.Order, movDegreeBound)
+	report.Anomalous = report.Order.Cmp(p) == 0
+	report.Supersingular = new(big.Int).Mod(report.Trace, p).Sign() == 0
+
+	report.TwistPointCount = new(big.Int).Add(p, bi1)
+	report.TwistPointCount.Mul(report.TwistPointCount, bi2).Sub(report.TwistPointCount, pointCount)
+	report.TwistFactors, report.TwistFactored = factorize(report.TwistPointCount)
+	report.TwistSecurity = rhoSecurity(report.TwistFactors[len(report.TwistFactors)-1])
+
+	if !report.Factored {
+		report.Issues = append(report.Issues, "the point count could not be fully factored")
+	}
+
+	if report.Security < minSecurity {
+		report.Issues = append(report.Issues,
+			fmt.Sprintf("the largest prime factor of the order has %d bits: rho costs about 2^%d", report.Order.BitLen(), report.Security))
+	}
+
+	if report.Anomalous {
+		report.Issues = append(report.Issues, "the curve is anomalous (n = p): Smart's attack solves the ECDLP in linear time")
+	}
+
+	if report.Supersingular {
+		report.Issues = append(report.Issues, "the curve is supersingular: the MOV attack reduces the ECDLP to F_p^2")
+	}
+
+	if report.EmbeddingDegree > 0 {
+		report.Issues = append(report.Issues,
+			fmt.Sprintf("the embedding degree is %d: the MOV/FR attacks reduce the ECDLP to F_p^%d", report.EmbeddingDegree, report.EmbeddingDegree))
+	}
+
+	if report.TwistSecurity < minSecurity {
+		report.Issues = append(report.Issues,
+			fmt.Sprintf("the twist is weak: rho costs about 2^%d, invalid-curve attacks on x-only ladders are practical", report.TwistSecurity))
+	}
+
+	return report, nil
+}
+
+// countPointsNaive returns #E(F_p) by adding 1 + (x^3 + ax + b / p) for every
+// x in F_p, plus the point at infinity. It takes O(p) time.
+func (ec EllipticCurve) countPointsNaive() *big.Int {
+	count := big.NewInt(1)
+	x := new(big.Int)
+	rhs := new(big.Int)
+	for x.SetInt64(0); x.Cmp(ec.m) < 0; x.Add(x, bi1) {
+		rhs.Mul(x, x).Add(rhs, ec.a.n).Mul(rhs, x).Add(rhs, ec.b.n).Mod(rhs, ec.m)
+		count.Add(count, big.NewInt(int64(1+big.Jacobi(rhs, ec.m))))
+	}
+
+	return count
+}
+
+// rhoSecurity returns the approximate cost in bits of Pollard's rho in a
+// group of prime order n, computed as NewECC does.
+func rhoSecurity(n *big.Int) int {
+	return n.BitLen() / 2
+}
+
+// factorize returns the prime factors of n > 1 in ascending order, with
+// multiplicity, using trial division and Pollard's rho. The boolean is false
+// when the last factor is a composite that could not be split.
+func factorize(n *big.Int) ([]*big.Int, bool) {
+	factors := []*big.Int{}
+	rest := new(big.Int).Set(n)
+
+	for d := int64(2); d < 1<<16; d++ {
+		bd := big.NewInt(d)
+		for new(big.Int).Mod(rest, bd).Sign() == 0 {
+			factors = append(factors, bd)
+			rest.Div(rest, bd)
+		}
+
+		if rest.Cmp(bi1) == 0 {
+			return factors, true
+		}
+	}
+
+	factored := true
+	pending := []*big.Int{rest}
+	for len(pending) > 0 {
+		m := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if m.ProbablyPrime(20) {
+			factors = append(factors, m)
+			continue
+		}
+
+		d := pollardRho(m)
+		if d == nil {
+			factors = append(factors, m)
+			factored = false
+			continue
+		}
+
+		pending = append(pending, d, new(big.Int).Div(m, d))
+	}
+
+	slices.SortFunc(factors, func(a, b *big.Int) int {
+		return a.Cmp(b)
+	})
+
+	if !factored {
+		// keep the composite last so it is reported as the largest factor
+		i := slices.IndexFunc(factors, func(f *big.Int) bool { return !f.ProbablyPrime(20) })
+		composite := factors[i]
+		factors = append(slices.Delete(factors, i, i+1), composite)
+	}
+
+	return factors, factored
+}
+
+// pollardRhoIterations bounds the work of pollardRho, which usually finds
+// factors up to about 2^34 within it.
+const pollardRhoIterations = 1 << 18
+
+// pollardRho returns a non-trivial factor of the composite n using Brent's
+// variant of Pollard's rho, or nil if none is found within the bound.
+func pollardRho(n *big.Int) *big.Int {
+	const batch = 128
+
+	for c := int64(1); c < 5; c++ {
+		bc := big.NewInt(c)
+		f := func(x *big.Int) {
+			x.Mul(x, x).Add(x, bc).Mod(x, n)
+		}
+
+		x, y, ys := new(big.Int), big.NewInt(2), new(big.Int)
+		q, g := big.NewInt(1), big.NewInt(1)
+		diff := new(big.Int)
+
+		iterations := 0
+		for r := 1; g.Cmp(bi1) == 0; r *= 2 {
+			if iterations >= pollardRhoIterations {
+				return nil
+			}
+
+			x.Set(y)
+			for range r {
+				f(y)
+			}
+
+			for k := 0; k < r && g.Cmp(bi1) == 0; k += batch {
+				ys.Set(y)
+				for range min(batch, r-k) {
+					f(y)
+					q.Mul(q, diff.Sub(x, y).Abs(diff)).Mod(q, n)
+				}
+				g.GCD(nil, nil, q, n)
+				iterations += 2 * min(batch, r-k)
+			}
+		}
+
+		if g.Cmp(n) == 0 {
+			// the batch multiplied every factor in: redo it one step at a time
+			for g.SetInt64(1); g.Cmp(bi1) == 0; {
+				f(ys)
+				g.GCD(nil, nil, diff.Sub(x, ys).Abs(diff), n)
+			}
+		}
+
+		if g.Cmp(n) < 0 {
+			return g
+		}
+
+		// the cycles modulo every factor closed together: retry with another c
+	}
+
+	return nil
+}
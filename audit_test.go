@@ -0,0 +1,165 @@
+package becc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestAuditCurve(t *testing.T) {
+	tt := []struct {
+		name            string
+		a, b, p         int64
+		pointCount      int64
+		factors         []int64
+		embeddingDegree int
+		anomalous       bool
+		supersingular   bool
+		twistPointCount int64
+	}{
+		{
+			name: "anomalous", a: 1, b: 7, p: 853,
+			pointCount: 853, factors: []int64{853},
+			anomalous: true, twistPointCount: 855,
+		},
+		{
+			name: "supersingular", a: 1, b: 0, p: 103,
+			pointCount: 104, factors: []int64{2, 2, 2, 13}, embeddingDegree: 2,
+			supersingular: true, twistPointCount: 104,
+		},
+		{
+			name: "cofactor 4", a: 1, b: 6, p: 97,
+			pointCount: 116, factors: []int64{2, 2, 29}, embeddingDegree: 28,
+			twistPointCount: 80,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := AuditCurve(big.NewInt(tc.a), big.NewInt(tc.b), big.NewInt(tc.p), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if report.PointCount.Int64() != tc.pointCount {
+				t.Errorf("point count: got %s, expected %d", report.PointCount, tc.pointCount)
+			}
+
+			if len(report.Factors) != len(tc.factors) {
+				t.Fatalf("factors: got %v, expected %v", report.Factors, tc.factors)
+			}
+
+			for i, f := range tc.factors {
+				if report.Factors[i].Int64() != f {
+					t.Errorf("factors: got %v, expected %v", report.Factors, tc.factors)
+				}
+			}
+
+			if report.Cofactor.Int64()*report.Order.Int64() != tc.pointCount {
+				t.Errorf("cofactor %s and order %s do not match the point count", report.Cofactor, report.Order)
+			}
+
+			if report.EmbeddingDegree != tc.embeddingDegree {
+				t.Errorf("embedding degree: got %d, expected %d", report.EmbeddingDegree, tc.embeddingDegree)
+			}
+
+			if report.Anomalous != tc.anomalous {
+				t.Errorf("anomalous: got %v, expected %v", report.Anomalous, tc.anomalous)
+			}
+
+			if report.Supersingular != tc.supersingular {
+				t.Errorf("supersingular: got %v, expected %v", report.Supersingular, tc.supersingular)
+			}
+
+			if report.TwistPointCount.Int64() != tc.twistPointCount {
+				t.Errorf("twist point count: got %s, expected %d", report.TwistPointCount, tc.twistPointCount)
+			}
+
+			if report.Safe() {
+				t.Errorf("curve over a small field reported as safe")
+			}
+		})
+	}
+
+	// the Brainpool 256-bit curves fail only the twist security criterion,
+	// as reported by SafeCurves
+	weakTwist := map[string]bool{"brainpoolP256r1": true, "brainpoolP256t1": true}
+
+	for _, c := range Curves() {
+		t.Run(c.Name, func(t *testing.T) {
+			ecc := c.ECC()
+			pointCount := new(big.Int).Mul(ecc.Order(), ecc.Cofactor())
+
+			report, err := AuditCurve(ecc.Curve().A(), ecc.Curve().B(), ecc.Curve().P(), pointCount)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if report.Order.Cmp(ecc.Order()) != 0 {
+				t.Errorf("order: got %x, expected %x", report.Order, ecc.Order())
+			}
+
+			if report.Cofactor.Cmp(ecc.Cofactor()) != 0 {
+				t.Errorf("cofactor: got %s, expected %s", report.Cofactor, ecc.Cofactor())
+			}
+
+			if report.EmbeddingDegree != 0 || report.Anomalous || report.Supersingular {
+				t.Errorf("unexpected weakness: %v", report.Issues)
+			}
+
+			if weakTwist[c.Name] {
+				if report.TwistSecurity >= minAuditSecurity || len(report.Issues) != 1 {
+					t.Errorf("got issues %v, expected a weak twist", report.Issues)
+				}
+			} else if !report.Safe() {
+				t.Errorf("unexpected issues: %v", report.Issues)
+			}
+		})
+	}
+
+	t.Run("singular", func(t *testing.T) {
+		_, err := AuditCurve(big.NewInt(0), big.NewInt(0), big.NewInt(97), nil)
+		if !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("got %v, expected ErrInvalidParameters", err)
+		}
+	})
+
+	t.Run("outside hasse bound", func(t *testing.T) {
+		_, err := AuditCurve(big.NewInt(1), big.NewInt(6), big.NewInt(97), big.NewInt(200))
+		if !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("got %v, expected ErrInvalidParameters", err)
+		}
+	})
+}
+
+func TestFactorize(t *testing.T) {
+	tt := []struct {
+		n        string
+		expected []string
+	}{
+		{n: "360", expected: []string{"2", "2", "2", "3", "3", "5"}},
+		// product of two 32-bit primes, beyond trial division
+		{n: "18446744400127067027", expected: []string{"4294967311", "4294967357"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.n, func(t *testing.T) {
+			n, _ := new(big.Int).SetString(tc.n, 10)
+
+			factors, factored := factorize(n)
+			if !factored {
+				t.Fatalf("%s not factored", tc.n)
+			}
+
+			if len(factors) != len(tc.expected) {
+				t.Fatalf("got %v, expected %v", factors, tc.expected)
+			}
+
+			for i, f := range tc.expected {
+				if factors[i].String() != f {
+					t.Errorf("got %v, expected %v", factors, tc.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Package base58 implements the Base58 encoding of Bitcoin and Base58Check,
+// Base58 with a 4-byte double SHA-256 checksum, used by extended keys,
+// addresses and WIF private keys.
+package base58
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	ErrInvalidCharacter error = errors.New("invalid base58 character")
+	ErrInvalidChecksum  error = errors.New("invalid base58 checksum")
+)
+
+var radix = big.NewInt(58)
+
+// Encode returns the Base58 encoding of b, where every leading zero byte is
+// encoded as a leading '1'.
+func Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	digits := []byte{}
+	n := new(big.Int).SetBytes(b)
+	mod := new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		digits = append(digits, alphabet[mod.Int64()])
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat("1", zeros))
+	for i := len(digits) - 1; i >= 0; i-- {
+		sb.WriteByte(digits[i])
+	}
+
+	return sb.String()
+}
+
+// Decode decodes the Base58 string s.
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+
+	n := new(big.Int)
+	for i := zeros; i < len(s); i++ {
+		d := strings.IndexByte(alphabet, s[i])
+		if d < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCharacter, s[i])
+		}
+
+		n.Mul(n, radix).Add(n, big.NewInt(int64(d)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// CheckEncode returns the Base58Check encoding of payload: the Base58
+// encoding of payload || SHA-256(SHA-256(payload))[:4].
+func CheckEncode(payload []byte) string {
+	return Encode(append(bytes.Clone(payload), checksum(payload)...))
+}
+
+// CheckDecode decodes the Base58Check string s and returns its payload.
+func CheckDecode(s string) ([]byte, error) {
+	b, err := Decode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < 4 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidChecksum)
+	}
+
+	payload := b[:len(b)-4]
+	if !bytes.Equal(b[len(b)-4:], checksum(payload)) {
+		return nil, ErrInvalidChecksum
+	}
+
+	return payload, nil
+}
+
+func checksum(payload []byte) []byte {
+	h := sha256.Sum256(payload)
+	h = sha256.Sum256(h[:])
+
+	return h[:4]
+}
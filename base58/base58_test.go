@@ -0,0 +1,85 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	// base58_encode_decode.json of Bitcoin Core
+	tests := []struct {
+		hex     string
+		encoded string
+	}{
+		{hex: "", encoded: ""},
+		{hex: "61", encoded: "2g"},
+		{hex: "626262", encoded: "a3gV"},
+		{hex: "636363", encoded: "aPEr"},
+		{hex: "73696d706c792061206c6f6e6720737472696e67", encoded: "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{hex: "00eb15231dfceb60925886b67d065299925915aeb172c06647", encoded: "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{hex: "516b6fcd0f", encoded: "ABnLTmg"},
+		{hex: "bf4f89001e670274dd", encoded: "3SEo3LWLoPntC"},
+		{hex: "572e4794", encoded: "3EFU7m"},
+		{hex: "ecac89cad93923c02321", encoded: "EJDM8drfXA6uyA"},
+		{hex: "10c8511e", encoded: "Rt5zm"},
+		{hex: "00000000000000000000", encoded: "1111111111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoded, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatalf("invalid hex: %v", err)
+			}
+
+			if got := Encode(b); got != tt.encoded {
+				t.Errorf("got %s, want %s", got, tt.encoded)
+			}
+
+			decoded, err := Decode(tt.encoded)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(decoded, b) {
+				t.Errorf("got %x, want %x", decoded, b)
+			}
+		})
+	}
+
+	for _, s := range []string{"0", "O", "I", "l", "3mJr0"} {
+		if _, err := Decode(s); !errors.Is(err, ErrInvalidCharacter) {
+			t.Errorf("%s: expected error %v, got %v", s, ErrInvalidCharacter, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// the P2PKH address of the compressed public key 1·G
+	payload, err := hex.DecodeString("00751e76e8199196d454941c45d1b3a323f1433bd6")
+	if err != nil {
+		t.Fatalf("invalid hex: %v", err)
+	}
+
+	encoded := CheckEncode(payload)
+	if encoded != "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH" {
+		t.Errorf("got %s", encoded)
+	}
+
+	decoded, err := CheckDecode(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(decoded, payload) {
+		t.Errorf("got %x, want %x", decoded, payload)
+	}
+
+	for _, s := range []string{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ", "3mJr", ""} {
+		if _, err := CheckDecode(s); !errors.Is(err, ErrInvalidChecksum) {
+			t.Errorf("%s: expected error %v, got %v", s, ErrInvalidChecksum, err)
+		}
+	}
+}
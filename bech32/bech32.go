@@ -0,0 +1,263 @@
+// Package bech32 implements the Bech32 (BIP-173) and Bech32m (BIP-350)
+// encodings, and the segregated witness addresses built on them: Bech32 for
+// version 0 witness programs and Bech32m for versions 1 to 16, such as
+// taproot outputs.
+package bech32
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// maxLength is the maximum length of an encoded string.
+const maxLength = 90
+
+var (
+	ErrInvalidCharacter error = errors.New("invalid bech32 character")
+	ErrInvalidChecksum  error = errors.New("invalid bech32 checksum")
+	ErrInvalidLength    error = errors.New("invalid bech32 length")
+	ErrInvalidAddress   error = errors.New("invalid segwit address")
+)
+
+// Variant selects the checksum constant of the encoding.
+type Variant uint32
+
+const (
+	Bech32  Variant = 1
+	Bech32m Variant = 0x2bc830a3
+)
+
+func (v Variant) String() string {
+	switch v {
+	case Bech32:
+		return "bech32"
+	case Bech32m:
+		return "bech32m"
+	default:
+		return fmt.Sprintf("Variant(%#x)", uint32(v))
+	}
+}
+
+// Encode returns the encoding of the 5-bit values of data with the
+// human-readable part hrp, in lower case.
+func Encode(hrp string, data []byte, variant Variant) (string, error) {
+	if len(hrp) == 0 || len(hrp)+1+len(data)+6 > maxLength {
+		return "", ErrInvalidLength
+	}
+
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", fmt.Errorf("%w: %q in the human-readable part", ErrInvalidCharacter, hrp[i])
+		}
+	}
+
+	hrp = strings.ToLower(hrp)
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		if d > 31 {
+			return "", fmt.Errorf("%w: %d is not a 5-bit value", ErrInvalidCharacter, d)
+		}
+
+		sb.WriteByte(charset[d])
+	}
+
+	for _, d := range checksum(hrp, data, variant) {
+		sb.WriteByte(charset[d])
+	}
+
+	return sb.String(), nil
+}
+
+// Decode decodes s and returns its human-readable part, in lower case, its
+// 5-bit values and the variant of its checksum. Strings in mixed case are
+// rejected.
+func Decode(s string) (string, []byte, Variant, error) {
+	if len(s) > maxLength {
+		return "", nil, 0, ErrInvalidLength
+	}
+
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, 0, fmt.Errorf("%w: mixed case", ErrInvalidCharacter)
+	}
+
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, 0, ErrInvalidLength
+	}
+
+	hrp := lower[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, fmt.Errorf("%w: %q in the human-readable part", ErrInvalidCharacter, hrp[i])
+		}
+	}
+
+	data := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		d := strings.IndexByte(charset, lower[i])
+		if d < 0 {
+			return "", nil, 0, fmt.Errorf("%w: %q", ErrInvalidCharacter, lower[i])
+		}
+
+		data = append(data, byte(d))
+	}
+
+	variant := Variant(polymod(append(expandHRP(hrp), data...)))
+	if variant != Bech32 && variant != Bech32m {
+		return "", nil, 0, ErrInvalidChecksum
+	}
+
+	return hrp, data[:len(data)-6], variant, nil
+}
+
+// ConvertBits regroups the from-bit values of data into to-bit values. When
+// pad is set, the last group is padded with zero bits; otherwise, the
+// leftover bits must be fewer than from and all zero.
+func ConvertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	maxValue := uint(1)<<to - 1
+
+	out := []byte{}
+	for _, b := range data {
+		if uint(b)>>from != 0 {
+			return nil, fmt.Errorf("%w: %d is not a %d-bit value", ErrInvalidCharacter, b, from)
+		}
+
+		acc = acc<<from | uint(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxValue))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxValue != 0 {
+		return nil, fmt.Errorf("%w: invalid padding", ErrInvalidLength)
+	}
+
+	return out, nil
+}
+
+// EncodeSegWitAddress returns the address of the witness program of the
+// version, with Bech32 for version 0 and Bech32m for later versions.
+func EncodeSegWitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := checkWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+
+	variant := Bech32
+	if version > 0 {
+		variant = Bech32m
+	}
+
+	data, err := ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(hrp, append([]byte{version}, data...), variant)
+}
+
+// DecodeSegWitAddress decodes the address, which must have the
+// human-readable part hrp, and returns its witness version and program.
+func DecodeSegWitAddress(hrp, address string) (byte, []byte, error) {
+	gotHRP, data, variant, err := Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if gotHRP != strings.ToLower(hrp) {
+		return 0, nil, fmt.Errorf("%w: human-readable part %q, expected %q", ErrInvalidAddress, gotHRP, hrp)
+	}
+
+	if len(data) == 0 {
+		return 0, nil, fmt.Errorf("%w: no witness version", ErrInvalidAddress)
+	}
+
+	version := data[0]
+	if (version == 0 && variant != Bech32) || (version > 0 && variant != Bech32m) {
+		return 0, nil, fmt.Errorf("%w: witness version %d encoded with %s", ErrInvalidAddress, version, variant)
+	}
+
+	program, err := ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+
+	if err := checkWitnessProgram(version, program); err != nil {
+		return 0, nil, err
+	}
+
+	return version, program, nil
+}
+
+func checkWitnessProgram(version byte, program []byte) error {
+	if version > 16 {
+		return fmt.Errorf("%w: witness version %d", ErrInvalidAddress, version)
+	}
+
+	if len(program) < 2 || len(program) > 40 {
+		return fmt.Errorf("%w: witness program of %d bytes", ErrInvalidAddress, len(program))
+	}
+
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Errorf("%w: version 0 witness program of %d bytes", ErrInvalidAddress, len(program))
+	}
+
+	return nil
+}
+
+func polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if top>>i&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+// expandHRP returns the high bits of the characters of hrp, a zero, and
+// their low bits, the prefix of the checksummed values.
+func expandHRP(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+
+	return out
+}
+
+func checksum(hrp string, data []byte, variant Variant) []byte {
+	values := append(expandHRP(hrp), data...)
+	mod := polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ uint32(variant)
+
+	out := make([]byte, 6)
+	for i := range out {
+		out[i] = byte(mod >> (5 * (5 - i)) & 31)
+	}
+
+	return out
+}
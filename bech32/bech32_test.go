@@ -0,0 +1,173 @@
+package bech32
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	// valid strings of BIP-173 and BIP-350
+	tests := []struct {
+		s       string
+		variant Variant
+	}{
+		{s: "A12UEL5L", variant: Bech32},
+		{s: "a12uel5l", variant: Bech32},
+		{s: "an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", variant: Bech32},
+		{s: "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", variant: Bech32},
+		{s: "split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", variant: Bech32},
+		{s: "?1ezyfcl", variant: Bech32},
+		{s: "A1LQFN3A", variant: Bech32m},
+		{s: "a1lqfn3a", variant: Bech32m},
+		{s: "an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6", variant: Bech32m},
+		{s: "abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", variant: Bech32m},
+		{s: "split1checkupstagehandshakeupstreamerranterredcaperredlc445v", variant: Bech32m},
+		{s: "?1v759aa", variant: Bech32m},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			hrp, data, variant, err := Decode(tt.s)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if variant != tt.variant {
+				t.Errorf("got variant %s, want %s", variant, tt.variant)
+			}
+
+			encoded, err := Encode(hrp, data, variant)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if encoded != strings.ToLower(tt.s) {
+				t.Errorf("got %s, want %s", encoded, strings.ToLower(tt.s))
+			}
+
+			// a changed character breaks the checksum
+			i := len(tt.s) - 3
+			c := byte('q')
+			if strings.ToLower(tt.s)[i] == 'q' {
+				c = 'p'
+			}
+
+			if _, _, _, err := Decode(strings.ToLower(tt.s)[:i] + string(c) + strings.ToLower(tt.s)[i+1:]); !errors.Is(err, ErrInvalidChecksum) {
+				t.Errorf("expected error %v, got %v", ErrInvalidChecksum, err)
+			}
+		})
+	}
+
+	invalid := []struct {
+		s   string
+		err error
+	}{
+		{s: "pzry9x0s0muk", err: ErrInvalidLength},
+		{s: "1pzry9x0s0muk", err: ErrInvalidLength},
+		{s: "x1b4n0q5v", err: ErrInvalidCharacter},
+		{s: "li1dgmt3", err: ErrInvalidLength},
+		{s: "A1G7SGD8", err: ErrInvalidChecksum},
+		{s: "a12UEL5L", err: ErrInvalidCharacter},
+		{s: "\x801eym55h", err: ErrInvalidCharacter},
+	}
+
+	for _, tt := range invalid {
+		if _, _, _, err := Decode(tt.s); !errors.Is(err, tt.err) {
+			t.Errorf("%q: expected error %v, got %v", tt.s, tt.err, err)
+		}
+	}
+}
+
+func TestSegWitAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		version byte
+		program string
+	}{
+		{
+			address: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			version: 0,
+			program: "751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			address: "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+			version: 0,
+			program: "1863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262",
+		},
+		{
+			address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+			version: 1,
+			program: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		{
+			address: "tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c",
+			version: 1,
+			program: "000000c4a5cad46221b2a187905e5266362b99d5e91c6ce24d165dab93e86433",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			hrp := strings.ToLower(tt.address[:2])
+
+			version, program, err := DecodeSegWitAddress(hrp, tt.address)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want, err := hex.DecodeString(tt.program)
+			if err != nil {
+				t.Fatalf("invalid hex: %v", err)
+			}
+
+			if version != tt.version || !bytes.Equal(program, want) {
+				t.Errorf("got version %d and program %x, want %d and %s", version, program, tt.version, tt.program)
+			}
+
+			encoded, err := EncodeSegWitAddress(hrp, version, program)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if encoded != strings.ToLower(tt.address) {
+				t.Errorf("got %s, want %s", encoded, strings.ToLower(tt.address))
+			}
+		})
+	}
+
+	program, err := hex.DecodeString("751e76e8199196d454941c45d1b3a323f1433bd6")
+	if err != nil {
+		t.Fatalf("invalid hex: %v", err)
+	}
+
+	// a taproot address must use Bech32m, and a version 0 address Bech32
+	data, err := ConvertBits(program, 8, 5, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		version byte
+		variant Variant
+	}{{version: 0, variant: Bech32m}, {version: 1, variant: Bech32}} {
+		s, err := Encode("bc", append([]byte{tt.version}, data...), tt.variant)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, _, err := DecodeSegWitAddress("bc", s); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("version %d with %s: expected error %v, got %v", tt.version, tt.variant, ErrInvalidAddress, err)
+		}
+	}
+
+	if _, _, err := DecodeSegWitAddress("tb", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("expected error %v, got %v", ErrInvalidAddress, err)
+	}
+
+	if _, err := EncodeSegWitAddress("bc", 0, program[:19]); !errors.Is(err, ErrInvalidAddress) {
+		t.Errorf("expected error %v, got %v", ErrInvalidAddress, err)
+	}
+}
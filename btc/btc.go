@@ -0,0 +1,187 @@
+// Package btc derives Bitcoin addresses from secp256k1 public keys: P2PKH,
+// the Base58Check encoding of the HASH160 of the serialized key, P2WPKH, its
+// version 0 segregated witness counterpart in Bech32, and P2TR, the version 1
+// taproot output of the key in Bech32m. It also implements the Wallet Import
+// Format (WIF) of private keys.
+package btc
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/base58"
+	"github.com/artilugio0/becc/bech32"
+	"golang.org/x/crypto/ripemd160"
+)
+
+var (
+	ErrInvalidWIF     error = errors.New("invalid WIF private key")
+	ErrUnknownNetwork error = errors.New("unknown network")
+)
+
+// Network holds the address prefixes of a network.
+type Network struct {
+	name string
+
+	// pubKeyHash is the version byte of P2PKH addresses
+	pubKeyHash byte
+
+	// wif is the version byte of WIF private keys
+	wif byte
+
+	// hrp is the human-readable part of segregated witness addresses
+	hrp string
+}
+
+var (
+	// Mainnet addresses start with 1, bc1q and bc1p, and WIF keys with K or L,
+	// or 5 for uncompressed public keys.
+	Mainnet = Network{name: "mainnet", pubKeyHash: 0x00, wif: 0x80, hrp: "bc"}
+
+	// Testnet addresses start with m or n, tb1q and tb1p, and WIF keys with c,
+	// or 9 for uncompressed public keys.
+	Testnet = Network{name: "testnet", pubKeyHash: 0x6f, wif: 0xef, hrp: "tb"}
+)
+
+var networks = []Network{Mainnet, Testnet}
+
+func (n Network) Name() string {
+	return n.name
+}
+
+// Hash160 returns RIPEMD-160(SHA-256(b)).
+func Hash160(b []byte) []byte {
+	h := sha256.Sum256(b)
+
+	r := ripemd160.New()
+	r.Write(h[:])
+
+	return r.Sum(nil)
+}
+
+// P2PKH returns the pay-to-public-key-hash address of the public key: the
+// Base58Check encoding of the version byte of the network and HASH160(key),
+// where key is the compressed or the uncompressed serialization of pub. Each
+// gives a different address, the uncompressed one being that of old wallets.
+func P2PKH(pub becc.PublicKey, network Network, compressed bool) (string, error) {
+	if err := checkCurve(pub.ECC()); err != nil {
+		return "", err
+	}
+
+	key := pub.Uncompressed()
+	if compressed {
+		key = pub.Compressed()
+	}
+
+	return base58.CheckEncode(append([]byte{network.pubKeyHash}, Hash160(key)...)), nil
+}
+
+// P2WPKH returns the pay-to-witness-public-key-hash address of the public
+// key: the version 0 witness program HASH160(compressed key) in Bech32.
+func P2WPKH(pub becc.PublicKey, network Network) (string, error) {
+	if err := checkCurve(pub.ECC()); err != nil {
+		return "", err
+	}
+
+	return bech32.EncodeSegWitAddress(network.hrp, 0, Hash160(pub.Compressed()))
+}
+
+// P2TR returns the pay-to-taproot address of the public key with no script
+// path, as BIP-86 specifies for single key wallets: the version 1 witness
+// program is the x-only output key returned by TaprootOutputKey, in
+// Bech32m.
+func P2TR(pub becc.PublicKey, network Network) (string, error) {
+	output, err := TaprootOutputKey(pub)
+	if err != nil {
+		return "", err
+	}
+
+	return bech32.EncodeSegWitAddress(network.hrp, 1, output.XOnly())
+}
+
+// TaprootOutputKey returns the taproot output key of the internal key pub
+// without a script tree (BIP-341 and BIP-86): Q = P + t·G, where P is the
+// point of the x-only key of pub, the one with an even y, and
+// t = hash_TapTweak(x(P)).
+func TaprootOutputKey(pub becc.PublicKey) (becc.PublicKey, error) {
+	ecc := pub.ECC()
+	if err := checkCurve(ecc); err != nil {
+		return becc.PublicKey{}, err
+	}
+
+	internal, err := ecc.NewPublicKeyXOnly(pub.XOnly())
+	if err != nil {
+		return becc.PublicKey{}, err
+	}
+
+	t := new(big.Int).SetBytes(becc.TaggedHash("TapTweak", internal.XOnly()))
+	if t.Cmp(ecc.Order()) >= 0 {
+		return becc.PublicKey{}, fmt.Errorf("%w: the taproot tweak is out of range", becc.ErrInvalidPublicKey)
+	}
+
+	return ecc.NewPublicKey(ecc.Generator().ScalarMul(t).Add(internal.Point()))
+}
+
+// WIF returns the Wallet Import Format of the private key: the Base58Check
+// encoding of the version byte of the network and the 32-byte key, followed
+// by 0x01 when its public key is used compressed.
+func WIF(priv becc.PrivateKey, network Network, compressed bool) (string, error) {
+	if err := checkCurve(priv.ECC()); err != nil {
+		return "", err
+	}
+
+	payload := append([]byte{network.wif}, priv.Bytes()...)
+	if compressed {
+		payload = append(payload, 1)
+	}
+
+	return base58.CheckEncode(payload), nil
+}
+
+// ParseWIF parses a private key in Wallet Import Format and returns it with
+// its network and whether its public key is used compressed.
+func ParseWIF(s string) (becc.PrivateKey, Network, bool, error) {
+	payload, err := base58.CheckDecode(s)
+	if err != nil {
+		return becc.PrivateKey{}, Network{}, false, fmt.Errorf("%w: %w", ErrInvalidWIF, err)
+	}
+
+	var compressed bool
+	switch {
+	case len(payload) == 33:
+	case len(payload) == 34 && payload[33] == 1:
+		compressed = true
+	default:
+		return becc.PrivateKey{}, Network{}, false, fmt.Errorf("%w: invalid length", ErrInvalidWIF)
+	}
+
+	var network Network
+	found := false
+	for _, n := range networks {
+		if payload[0] == n.wif {
+			network, found = n, true
+		}
+	}
+
+	if !found {
+		return becc.PrivateKey{}, Network{}, false, fmt.Errorf("%w: version %#02x", ErrUnknownNetwork, payload[0])
+	}
+
+	priv, err := becc.Secp256k1ECC().NewPrivateKeyBytes(payload[1:33])
+	if err != nil {
+		return becc.PrivateKey{}, Network{}, false, fmt.Errorf("%w: %w", ErrInvalidWIF, err)
+	}
+
+	return priv, network, compressed, nil
+}
+
+func checkCurve(ecc *becc.ECC) error {
+	if ecc.Name() != "secp256k1" {
+		return fmt.Errorf("%w: Bitcoin keys are secp256k1 keys", becc.ErrUnsupportedCurve)
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package btc
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/hd"
+	"github.com/artilugio0/becc/mnemonic"
+)
+
+func TestAddresses(t *testing.T) {
+	// the BIP-44, BIP-84 and BIP-86 test vectors of the mnemonic of zero
+	// entropy and no passphrase
+	seed, err := mnemonic.Seed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	master, err := hd.NewMaster(seed, hd.Mainnet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		wif       string
+		address   string
+		addressFn func(becc.PublicKey, Network) (string, error)
+	}{
+		{
+			path:      "m/44'/0'/0'/0/0",
+			wif:       "L4p2b9VAf8k5aUahF1JCJUzZkgNEAqLfq8DDdQiyAprQAKSbu8hf",
+			address:   "1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA",
+			addressFn: compressedP2PKH,
+		},
+		{
+			path:      "m/84'/0'/0'/0/0",
+			wif:       "KyZpNDKnfs94vbrwhJneDi77V6jF64PWPF8x5cdJb8ifgg2DUc9d",
+			address:   "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu",
+			addressFn: P2WPKH,
+		},
+		{
+			path:      "m/86'/0'/0'/0/0",
+			wif:       "KyRv5iFPHG7iB5E4CqvMzH3WFJVhbfYK4VY7XAedd9Ys69mEsPLQ",
+			address:   "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr",
+			addressFn: P2TR,
+		},
+		{
+			path:      "m/86'/0'/0'/0/1",
+			wif:       "L1jhNnZZAAAppoSYQuaAQEj935VpmishMomuWXgJ3Qy5HNqkhhus",
+			address:   "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh",
+			addressFn: P2TR,
+		},
+		{
+			path:      "m/86'/0'/0'/1/0",
+			wif:       "KzsCLFtWKpeNKMHFyHKT8vGRuGQxEY8CQjgLcEj14C8xK2PyEFeN",
+			address:   "bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7",
+			addressFn: P2TR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			key, err := master.Derive(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			address, err := tt.addressFn(key.PublicKey(), Mainnet)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if address != tt.address {
+				t.Errorf("got address %s, want %s", address, tt.address)
+			}
+
+			priv, err := key.PrivateKey()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			wif, err := WIF(priv, Mainnet, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if wif != tt.wif {
+				t.Errorf("got WIF %s, want %s", wif, tt.wif)
+			}
+
+			parsed, network, compressed, err := ParseWIF(wif)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if parsed.Int().Cmp(priv.Int()) != 0 || network != Mainnet || !compressed {
+				t.Errorf("got %x on %s, compressed %t", parsed.Bytes(), network.Name(), compressed)
+			}
+		})
+	}
+}
+
+func TestTaprootOutputKey(t *testing.T) {
+	// BIP-86: m/86'/0'/0'/0/0 of the mnemonic of zero entropy
+	internal, err := becc.Secp256k1ECC().NewPublicKeyXOnly(mustHex(t, "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := TaprootOutputKey(internal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(output.XOnly()); got != "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c" {
+		t.Errorf("got output key %s", got)
+	}
+
+	// the key with an odd y has the same output key
+	odd, err := becc.Secp256k1ECC().NewPublicKey(internal.Point().Neg())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	oddOutput, err := TaprootOutputKey(odd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !oddOutput.Point().Eq(output.Point()) {
+		t.Errorf("got output key %x for the odd key", oddOutput.XOnly())
+	}
+}
+
+func TestTestnet(t *testing.T) {
+	// the private key 1, whose public key is G
+	priv, err := becc.Secp256k1ECC().NewPrivateKeyBytes(mustHex(t, "0000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pub := priv.PublicKey()
+
+	for _, tt := range []struct {
+		network Network
+		fn      func(becc.PublicKey, Network) (string, error)
+		want    string
+	}{
+		{network: Mainnet, fn: compressedP2PKH, want: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{network: Mainnet, fn: uncompressedP2PKH, want: "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm"},
+		{network: Mainnet, fn: P2WPKH, want: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{network: Testnet, fn: compressedP2PKH, want: "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+		{network: Testnet, fn: uncompressedP2PKH, want: "mtoKs9V381UAhUia3d7Vb9GNak8Qvmcsme"},
+		{network: Testnet, fn: P2WPKH, want: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+	} {
+		got, err := tt.fn(pub, tt.network)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.network.Name(), got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		network    Network
+		compressed bool
+		want       string
+	}{
+		{network: Mainnet, compressed: false, want: "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf"},
+		{network: Testnet, compressed: true, want: "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA"},
+		{network: Testnet, compressed: false, want: "91avARGdfge8E4tZfYLoxeJ5sGBdNJQH4kvjJoQFacbgwmaKkrx"},
+	} {
+		wif, err := WIF(priv, tt.network, tt.compressed)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if wif != tt.want {
+			t.Errorf("got WIF %s, want %s", wif, tt.want)
+		}
+
+		parsed, network, compressed, err := ParseWIF(wif)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if parsed.Int().Cmp(priv.Int()) != 0 || network != tt.network || compressed != tt.compressed {
+			t.Errorf("%s: got %x on %s, compressed %t", wif, parsed.Bytes(), network.Name(), compressed)
+		}
+	}
+}
+
+func TestParseWIFUncompressed(t *testing.T) {
+	// the uncompressed WIF example of the Bitcoin wiki
+	priv, network, compressed, err := ParseWIF("5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(priv.Bytes()); got != "0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d" {
+		t.Errorf("got private key %s", got)
+	}
+
+	if network != Mainnet || compressed {
+		t.Errorf("got network %s, compressed %t", network.Name(), compressed)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	p256, p256Pub, err := becc.Secp256r1ECC().GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := P2PKH(p256Pub, Mainnet, true); !errors.Is(err, becc.ErrUnsupportedCurve) {
+		t.Errorf("expected error %v, got %v", becc.ErrUnsupportedCurve, err)
+	}
+
+	if _, err := WIF(p256, Mainnet, true); !errors.Is(err, becc.ErrUnsupportedCurve) {
+		t.Errorf("expected error %v, got %v", becc.ErrUnsupportedCurve, err)
+	}
+
+	for _, s := range []string{
+		// a compressed flag other than 0x01
+		"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sfZr2ym",
+		// bad checksum
+		"L4p2b9VAf8k5aUahF1JCJUzZkgNEAqLfq8DDdQiyAprQAKSbu8hg",
+		// a P2PKH address
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+	} {
+		if _, _, _, err := ParseWIF(s); !errors.Is(err, ErrInvalidWIF) {
+			t.Errorf("%s: expected error %v, got %v", s, ErrInvalidWIF, err)
+		}
+	}
+}
+
+func compressedP2PKH(pub becc.PublicKey, network Network) (string, error) {
+	return P2PKH(pub, network, true)
+}
+
+func uncompressedP2PKH(pub becc.PublicKey, network Network) (string, error) {
+	return P2PKH(pub, network, false)
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex string %s: %v", s, err)
+	}
+
+	return b
+}
@@ -5,62 +5,57 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"slices"
+	"os"
 	"strings"
 
 	"github.com/artilugio0/becc"
 	"github.com/spf13/cobra"
 )
 
-type curveDef struct {
-	name string
-	ecc  *becc.ECC
-	len  int
-}
+func parseCurve(cmd *cobra.Command) (*becc.ECC, error) {
+	curveFile := cmd.Flags().Lookup("curve-file").Value.String()
+	if curveFile != "" {
+		params, err := os.ReadFile(curveFile)
+		if err != nil {
+			return nil, err
+		}
 
-var curves = []curveDef{
-	{"secp256k1", becc.Secp256k1ECC(), 32},
-	{"secp256r1", becc.Secp256r1ECC(), 32},
-	{"secp384r1", becc.Secp384r1ECC(), 48},
-	{"secp521r1", becc.Secp521r1ECC(), 66}, // TODO: fix bit count bugs
-}
+		return becc.LoadECParameters(params)
+	}
 
-func parseCurve(cmd *cobra.Command) (*becc.ECC, error) {
 	curveName := cmd.Flags().Lookup("curve").Value.String()
 
-	def, err := getCurveDef(curveName)
+	ecc, err := becc.LookupCurve(curveName)
 	if err != nil {
-		return nil, err
+		supportedCurves := []string{}
+		for _, c := range becc.Curves() {
+			supportedCurves = append(supportedCurves, c.Name)
+			supportedCurves = append(supportedCurves, c.Aliases...)
+		}
+		return nil, fmt.Errorf("invalid curve %q – supported values: %s", curveName, strings.Join(supportedCurves, " "))
 	}
 
-	return def.ecc, nil
+	return ecc, nil
 }
 
-func getCurveDef(name string) (curveDef, error) {
-	i, ok := slices.BinarySearchFunc(curves, name, func(cd curveDef, n string) int {
-		if cd.name < n {
-			return -1
-		} else if cd.name == n {
-			return 0
-		}
-		return 1
-	})
+// parseHash returns the hash function of the --hash flag, or the default one
+// of the curve if it is not set.
+func parseHash(cmd *cobra.Command, ecc *becc.ECC) (becc.HashFunc, error) {
+	hashName := cmd.Flags().Lookup("hash").Value.String()
+	if hashName == "" {
+		return ecc.DefaultHash(), nil
+	}
 
-	if !ok {
-		supportedCurves := make([]string, len(curves))
-		for i, c := range curves {
-			supportedCurves[i] = c.name
-		}
-		return curveDef{}, fmt.Errorf("invalid curve %q – supported values: %s", name, strings.Join(supportedCurves, " "))
+	hf, err := becc.LookupHash(hashName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hash %q – supported values: %s", hashName, strings.Join(becc.HashNames(), " "))
 	}
 
-	return curves[i], nil
+	return hf, nil
 }
 
 func parsePrivateKey(cmd *cobra.Command) (becc.PrivateKey, error) {
-	curveName := cmd.Flags().Lookup("curve").Value.String()
-
-	def, err := getCurveDef(curveName)
+	ecc, err := parseCurve(cmd)
 	if err != nil {
 		return becc.PrivateKey{}, err
 	}
@@ -70,16 +65,12 @@ func parsePrivateKey(cmd *cobra.Command) (becc.PrivateKey, error) {
 		return becc.PrivateKey{}, errors.New("private key not specified")
 	}
 
-	if len(privateKeyHex) != def.len*2 {
-		return becc.PrivateKey{}, errors.New("invalid private key format")
-	}
-
-	d, ok := new(big.Int).SetString(privateKeyHex, 16)
-	if !ok {
+	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
 		return becc.PrivateKey{}, errors.New("invalid private key format")
 	}
 
-	return def.ecc.NewPrivateKey(d), nil
+	return ecc.NewPrivateKeyBytes(privateKeyBytes)
 }
 
 func parsePublicKey(cmd *cobra.Command) (becc.PublicKey, error) {
@@ -92,9 +83,7 @@ func parsePublicKey(cmd *cobra.Command) (becc.PublicKey, error) {
 }
 
 func parsePublicKeyString(cmd *cobra.Command, publicKeyHex string) (becc.PublicKey, error) {
-	curveName := cmd.Flags().Lookup("curve").Value.String()
-
-	def, err := getCurveDef(curveName)
+	ecc, err := parseCurve(cmd)
 	if err != nil {
 		return becc.PublicKey{}, err
 	}
@@ -104,29 +93,28 @@ func parsePublicKeyString(cmd *cobra.Command, publicKeyHex string) (becc.PublicK
 		return becc.PublicKey{}, errors.New("invalid public key format")
 	}
 
-	return def.ecc.NewPublicKeyBytes(publicKeyBytes)
+	return ecc.NewPublicKeyBytes(publicKeyBytes)
 }
 
 func parseSignature(cmd *cobra.Command, sigHex string) (becc.Signature, error) {
-	curveName := cmd.Flags().Lookup("curve").Value.String()
-
-	def, err := getCurveDef(curveName)
+	ecc, err := parseCurve(cmd)
 	if err != nil {
 		return becc.Signature{}, err
 	}
 
-	if len(sigHex) != def.len*4 {
+	scalarLen := ecc.ScalarSize()
+	if len(sigHex) != scalarLen*4 {
 		return becc.Signature{}, fmt.Errorf("invalid signature format: invalid length")
 	}
 
-	r, ok := new(big.Int).SetString(sigHex[:def.len*2], 16)
+	r, ok := new(big.Int).SetString(sigHex[:scalarLen*2], 16)
 	if !ok {
 		return becc.Signature{}, fmt.Errorf("invalid signature format: r value")
 	}
 
-	s, ok := new(big.Int).SetString(sigHex[def.len*2:], 16)
+	s, ok := new(big.Int).SetString(sigHex[scalarLen*2:], 16)
 	if !ok {
-		return becc.Signature{}, fmt.Errorf("invalid signature format: r value")
+		return becc.Signature{}, fmt.Errorf("invalid signature format: s value")
 	}
 
 	sig := becc.NewSignature(r, s)
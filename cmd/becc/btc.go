@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/btc"
+	"github.com/spf13/cobra"
+)
+
+func btcCmd() *cobra.Command {
+	var (
+		wif          string
+		testnet      bool
+		uncompressed bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "btc",
+		Short: "Bitcoin addresses and WIF private keys of secp256k1 keys",
+		Args:  cobra.NoArgs,
+	}
+
+	addressCmd := &cobra.Command{
+		Use:   "address",
+		Short: "Print the P2PKH, P2WPKH and P2TR addresses of a private key (-k or --wif) or a public key (-p)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network := btc.Mainnet
+			if testnet {
+				network = btc.Testnet
+			}
+
+			compressed := !uncompressed
+
+			var (
+				privateKey *becc.PrivateKey
+				publicKey  becc.PublicKey
+			)
+
+			switch {
+			case wif != "":
+				priv, wifNetwork, wifCompressed, err := btc.ParseWIF(wif)
+				if err != nil {
+					return err
+				}
+
+				if cmd.Flags().Changed("testnet") && wifNetwork != network {
+					return fmt.Errorf("the WIF key is a %s key", wifNetwork.Name())
+				}
+
+				if cmd.Flags().Changed("uncompressed") && wifCompressed != compressed {
+					kind := "an uncompressed"
+					if wifCompressed {
+						kind = "a compressed"
+					}
+
+					return fmt.Errorf("the WIF key is a key of %s public key", kind)
+				}
+
+				network = wifNetwork
+				compressed = wifCompressed
+				privateKey = &priv
+				publicKey = priv.PublicKey()
+			case cmd.Flags().Lookup("private-key").Value.String() != "":
+				priv, err := parsePrivateKey(cmd)
+				if err != nil {
+					return err
+				}
+
+				privateKey = &priv
+				publicKey = priv.PublicKey()
+			case cmd.Flags().Lookup("public-key").Value.String() != "":
+				var err error
+				if publicKey, err = parsePublicKey(cmd); err != nil {
+					return err
+				}
+			default:
+				return errors.New("private key or public key not specified")
+			}
+
+			p2pkh, err := btc.P2PKH(publicKey, network, compressed)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("network: %s\n", network.Name())
+			if privateKey != nil {
+				encoded, err := btc.WIF(*privateKey, network, compressed)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("wif: %s\n", encoded)
+			}
+
+			// segregated witness outputs only take compressed public keys
+			if !compressed {
+				fmt.Printf("public key: %x\n", publicKey.Uncompressed())
+				fmt.Printf("p2pkh: %s\n", p2pkh)
+
+				return nil
+			}
+
+			p2wpkh, err := btc.P2WPKH(publicKey, network)
+			if err != nil {
+				return err
+			}
+
+			p2tr, err := btc.P2TR(publicKey, network)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("public key: %x\n", publicKey.Compressed())
+			fmt.Printf("p2pkh: %s\n", p2pkh)
+			fmt.Printf("p2wpkh: %s\n", p2wpkh)
+			fmt.Printf("p2tr: %s\n", p2tr)
+
+			return nil
+		},
+	}
+	addressCmd.Flags().StringVar(&wif, "wif", "", "Private key in Wallet Import Format, whose network is used")
+	addressCmd.Flags().BoolVar(&testnet, "testnet", false, "Print testnet addresses and keys")
+	addressCmd.Flags().BoolVar(&uncompressed, "uncompressed", false, "Use the uncompressed public key, as old wallets do: only the P2PKH address is printed")
+
+	cmd.AddCommand(addressCmd)
+
+	return cmd
+}
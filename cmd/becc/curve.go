@@ -0,0 +1,202 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/artilugio0/becc"
+	"github.com/spf13/cobra"
+)
+
+func curveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "curve",
+		Short: "Elliptic curve domain parameter operations",
+	}
+
+	var (
+		aHex      string
+		bHex      string
+		primeHex  string
+		pointsHex string
+	)
+
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Report the security properties of a curve",
+		Long: `Report the security properties of the curve y^2 = x^3 + ax + b over F_p.
+
+The curve is given with --a, --b and --prime, or taken from --curve or
+--curve-file when they are omitted. The number of points is taken from --points
+or from the curve order and cofactor, or else computed with the SEA algorithm,
+which takes about half a minute for 256-bit fields and tens of minutes for
+384-bit fields and above, unless a or b is zero.
+The command fails when the curve does not pass every check.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var a, b, p, points *big.Int
+
+			if aHex == "" && bHex == "" && primeHex == "" {
+				ecc, err := parseCurve(cmd)
+				if err != nil {
+					return err
+				}
+
+				ec := ecc.Curve()
+				a, b, p = ec.A(), ec.B(), ec.P()
+				points = new(big.Int).Mul(ecc.Order(), ecc.Cofactor())
+			} else {
+				values := []*big.Int{}
+				for _, v := range []string{aHex, bHex, primeHex} {
+					n, ok := new(big.Int).SetString(v, 16)
+					if !ok {
+						return errors.New("--a, --b and --prime must be hex numbers")
+					}
+					values = append(values, n)
+				}
+				a, b, p = values[0], values[1], values[2]
+			}
+
+			if pointsHex != "" {
+				n, ok := new(big.Int).SetString(pointsHex, 16)
+				if !ok {
+					return errors.New("invalid number of points format")
+				}
+				points = n
+			}
+
+			report, err := becc.AuditCurve(a, b, p, points)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("discriminant: %x\n", report.Discriminant)
+			fmt.Printf("points: %x\n", report.PointCount)
+			fmt.Printf("trace: %d\n", report.Trace)
+			fmt.Printf("factors: %s\n", formatFactors(report.Factors, report.Factored))
+			fmt.Printf("order: %x (%d bits)\n", report.Order, report.Order.BitLen())
+			fmt.Printf("cofactor: %x\n", report.Cofactor)
+			if report.EmbeddingDegree > 0 {
+				fmt.Printf("embedding degree: %d\n", report.EmbeddingDegree)
+			} else {
+				fmt.Println("embedding degree: > 100")
+			}
+			fmt.Printf("anomalous: %t\n", report.Anomalous)
+			fmt.Printf("supersingular: %t\n", report.Supersingular)
+			fmt.Printf("security: %d bits\n", report.Security)
+			fmt.Printf("twist points: %x\n", report.TwistPointCount)
+			fmt.Printf("twist factors: %s\n", formatFactors(report.TwistFactors, report.TwistFactored))
+			fmt.Printf("twist security: %d bits\n", report.TwistSecurity)
+
+			if report.Safe() {
+				fmt.Println("safe: true")
+				return nil
+			}
+
+			fmt.Println("safe: false")
+			for _, issue := range report.Issues {
+				fmt.Printf("  - %s\n", issue)
+			}
+
+			// the report already explains the failure
+			cmd.SilenceUsage = true
+			return errors.New("the curve did not pass the audit")
+		},
+	}
+
+	auditCmd.Flags().StringVar(&aHex, "a", "", "Coefficient a in hex format")
+	auditCmd.Flags().StringVar(&bHex, "b", "", "Coefficient b in hex format")
+	auditCmd.Flags().StringVar(&primeHex, "prime", "", "Field prime p in hex format")
+	auditCmd.Flags().StringVar(&pointsHex, "points", "", "Number of points of the curve in hex format")
+	auditCmd.MarkFlagsRequiredTogether("a", "b", "prime")
+
+	var (
+		bits        int
+		seedHex     string
+		maxCofactor int64
+		format      string
+	)
+
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a verifiably random curve from a seed",
+		Long: `Generate a random curve y^2 = x^3 - 3x + b with prime order (or a cofactor up
+to --max-cofactor) that passes the checks of "becc curve audit", and print its
+domain parameters in a format accepted by --curve-file. Sizes below 200 bits
+are rejected, since their curves cannot pass the audit.
+
+Every parameter is derived from the seed, which is included in the output, so
+anyone can reproduce the curve. A random seed is used when --seed is omitted.
+Each candidate curve has its points counted with the SEA algorithm, giving up
+as soon as a small factor rules it out: a 256-bit curve takes about ten minutes
+on average, depending on the seed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var seed []byte
+			if seedHex == "" {
+				seed = make([]byte, 32)
+				if _, err := rand.Read(seed); err != nil {
+					return err
+				}
+			} else {
+				var err error
+				seed, err = hex.DecodeString(seedHex)
+				if err != nil {
+					return errors.New("invalid seed format")
+				}
+			}
+
+			ecc, err := becc.GenerateCurve(bits, seed, maxCofactor)
+			if err != nil {
+				return err
+			}
+
+			var params []byte
+			switch format {
+			case "json":
+				params, err = ecc.ECParametersJSON()
+				params = append(params, '\n')
+			case "pem":
+				params, err = ecc.ECParametersPEM()
+			default:
+				return fmt.Errorf("invalid format %q – supported values: json pem", format)
+			}
+			if err != nil {
+				return err
+			}
+
+			_, err = os.Stdout.Write(params)
+			return err
+		},
+	}
+
+	generateCmd.Flags().IntVar(&bits, "bits", 256, "Size of the field prime in bits (at least 200)")
+	generateCmd.Flags().StringVar(&seedHex, "seed", "", "Seed in hex format (random if omitted)")
+	generateCmd.Flags().Int64Var(&maxCofactor, "max-cofactor", 1, "Largest accepted cofactor")
+	generateCmd.Flags().StringVar(&format, "format", "json", "Output format: json or pem")
+
+	cmd.AddCommand(auditCmd)
+	cmd.AddCommand(generateCmd)
+
+	return cmd
+}
+
+// formatFactors returns the factors in decimal joined by " * ", marking the
+// last one when it is a composite that could not be split.
+func formatFactors(factors []*big.Int, factored bool) string {
+	s := make([]string, len(factors))
+	for i, f := range factors {
+		s[i] = f.String()
+	}
+
+	if !factored {
+		s[len(s)-1] += " (composite)"
+	}
+
+	return strings.Join(s, " * ")
+}
@@ -4,10 +4,24 @@ import (
 	"encoding/hex"
 	"fmt"
 
+	"github.com/artilugio0/becc"
 	"github.com/spf13/cobra"
 )
 
+const (
+	ecdhOutputX            string = "x"
+	ecdhOutputCompressed   string = "compressed"
+	ecdhOutputUncompressed string = "uncompressed"
+	ecdhOutputHKDF         string = "hkdf"
+)
+
 func ecdhCmd() *cobra.Command {
+	var (
+		output string
+		info   string
+		length int
+	)
+
 	cmd := &cobra.Command{
 		Use:   "ecdh remote-pub-key",
 		Short: "Elliptic curve Diffie-Hellman algorithm",
@@ -23,7 +37,31 @@ func ecdhCmd() *cobra.Command {
 				return err
 			}
 
-			ecdhBytes := privateKey.ECDH(remotePubKey)
+			ecc, err := parseCurve(cmd)
+			if err != nil {
+				return err
+			}
+
+			var ecdhBytes []byte
+			switch output {
+			case ecdhOutputX:
+				ecdhBytes = privateKey.ECDHX(remotePubKey)
+			case ecdhOutputCompressed:
+				ecdhBytes = privateKey.ECDH(remotePubKey)
+			case ecdhOutputUncompressed:
+				sharedKey, err := ecc.NewPublicKey(privateKey.ECDHPoint(remotePubKey))
+				if err != nil {
+					return err
+				}
+				ecdhBytes = sharedKey.Uncompressed()
+			case ecdhOutputHKDF:
+				ecdhBytes, err = privateKey.ECDHKDF(remotePubKey, becc.HKDF(becc.SHA256), []byte(info), length)
+				if err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("invalid output %q – supported values: x compressed uncompressed hkdf", output)
+			}
 
 			fmt.Println(hex.EncodeToString(ecdhBytes))
 
@@ -31,5 +69,9 @@ func ecdhCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVarP(&output, "output", "o", ecdhOutputCompressed, "Shared secret format: x, compressed, uncompressed or hkdf")
+	cmd.Flags().StringVar(&info, "info", "", "Context info for the hkdf output")
+	cmd.Flags().IntVar(&length, "length", 32, "Number of bytes derived by the hkdf output")
+
 	return cmd
 }
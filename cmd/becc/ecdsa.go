@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/artilugio0/becc"
 	"github.com/spf13/cobra"
@@ -16,6 +17,9 @@ func ecdsaCmd() *cobra.Command {
 		Args:  cobra.NoArgs,
 	}
 
+	var hashName string
+	cmd.PersistentFlags().StringVar(&hashName, "hash", "", "Hash function of the message: "+strings.Join(becc.HashNames(), ", ")+" (default sha256, sha384 for 384-bit curves and sha512 for larger ones)")
+
 	verifyCmd := &cobra.Command{
 		Use:   "verify sig",
 		Short: "Verify a signature using ECDSA reading the message from stdin",
@@ -31,12 +35,17 @@ func ecdsaCmd() *cobra.Command {
 				return err
 			}
 
+			hf, err := parseHash(cmd, publicKey.ECC())
+			if err != nil {
+				return err
+			}
+
 			msg, err := io.ReadAll(os.Stdin)
 			if err != nil {
 				return err
 			}
 
-			verifyOk := publicKey.Verify(becc.SHA256, msg, sig)
+			verifyOk := publicKey.Verify(hf, msg, sig)
 			if verifyOk {
 				fmt.Println("valid signature")
 			} else {
@@ -60,6 +69,16 @@ func ecdsaCmd() *cobra.Command {
 				return err
 			}
 
+			ecc, err := parseCurve(cmd)
+			if err != nil {
+				return err
+			}
+
+			hf, err := parseHash(cmd, ecc)
+			if err != nil {
+				return err
+			}
+
 			msg, err := io.ReadAll(os.Stdin)
 			if err != nil {
 				return err
@@ -68,16 +87,17 @@ func ecdsaCmd() *cobra.Command {
 			var sig becc.Signature
 
 			if signDeterministic {
-				sig, err = privateKey.SignDeterministic(becc.SHA256, msg, signLowS)
+				sig, err = privateKey.SignDeterministic(hf, msg, signLowS)
 			} else {
-				sig, err = privateKey.Sign(becc.SHA256, msg, signLowS)
+				sig, err = privateKey.Sign(hf, msg, signLowS)
 			}
 
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("%064x%064x\n", sig.R(), sig.S())
+			scalarHexLen := ecc.ScalarSize() * 2
+			fmt.Printf("%0*x%0*x\n", scalarHexLen, sig.R(), scalarHexLen, sig.S())
 
 			return nil
 		},
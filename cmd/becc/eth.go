@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/eth"
+	"github.com/spf13/cobra"
+)
+
+func ethCmd() *cobra.Command {
+	var (
+		typedDataFile string
+		address       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "eth",
+		Short: "Ethereum addresses and personal_sign (EIP-191) and typed data (EIP-712) signatures",
+		Args:  cobra.NoArgs,
+	}
+
+	addressCmd := &cobra.Command{
+		Use:   "address",
+		Short: "Print the EIP-55 address of a private key (-k) or a public key (-p)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var publicKey becc.PublicKey
+			if cmd.Flags().Lookup("private-key").Value.String() != "" {
+				privateKey, err := parsePrivateKey(cmd)
+				if err != nil {
+					return err
+				}
+
+				publicKey = privateKey.PublicKey()
+			} else {
+				var err error
+				if publicKey, err = parsePublicKey(cmd); err != nil {
+					return err
+				}
+			}
+
+			a, err := eth.PublicKeyAddress(publicKey)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(a)
+
+			return nil
+		},
+	}
+
+	signCmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign the message read from stdin with personal_sign, or the typed data of --typed-data",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			privateKey, err := parsePrivateKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			var sig []byte
+			if typedDataFile != "" {
+				td, err := readTypedData(typedDataFile)
+				if err != nil {
+					return err
+				}
+
+				sig, err = eth.SignTypedData(privateKey, td)
+				if err != nil {
+					return err
+				}
+			} else {
+				msg, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return err
+				}
+
+				sig, err = eth.SignPersonal(privateKey, msg)
+				if err != nil {
+					return err
+				}
+			}
+
+			fmt.Printf("0x%x\n", sig)
+
+			return nil
+		},
+	}
+
+	recoverCmd := &cobra.Command{
+		Use:   "recover signature",
+		Short: "Recover the signer of the message read from stdin or of the typed data of --typed-data",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sig, err := hex.DecodeString(strings.TrimPrefix(args[0], "0x"))
+			if err != nil {
+				return errors.New("invalid signature format")
+			}
+
+			var publicKey becc.PublicKey
+			if typedDataFile != "" {
+				td, err := readTypedData(typedDataFile)
+				if err != nil {
+					return err
+				}
+
+				if publicKey, err = eth.RecoverTypedData(td, sig); err != nil {
+					return err
+				}
+			} else {
+				msg, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return err
+				}
+
+				if publicKey, err = eth.RecoverPersonal(msg, sig); err != nil {
+					return err
+				}
+			}
+
+			signer, err := eth.PublicKeyAddress(publicKey)
+			if err != nil {
+				return err
+			}
+
+			if address != "" {
+				expected, err := eth.ParseAddress(address)
+				if err != nil {
+					return err
+				}
+
+				if signer != expected {
+					fmt.Println("invalid signature")
+					os.Exit(1)
+				}
+
+				fmt.Println("valid signature")
+
+				return nil
+			}
+
+			fmt.Printf("address: %s\n", signer)
+			fmt.Printf("public key: %x\n", publicKey.Uncompressed())
+
+			return nil
+		},
+	}
+	recoverCmd.Flags().StringVar(&address, "address", "", "Check that the signer is this address instead of printing it")
+
+	for _, c := range []*cobra.Command{signCmd, recoverCmd} {
+		c.Flags().StringVar(&typedDataFile, "typed-data", "", "JSON file with EIP-712 typed data, as for eth_signTypedData_v4")
+	}
+
+	cmd.AddCommand(addressCmd)
+	cmd.AddCommand(signCmd)
+	cmd.AddCommand(recoverCmd)
+
+	return cmd
+}
+
+func readTypedData(path string) (*eth.TypedData, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return eth.ParseTypedData(b)
+}
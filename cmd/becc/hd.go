@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/artilugio0/becc/hd"
+	"github.com/spf13/cobra"
+)
+
+func hdCmd() *cobra.Command {
+	var (
+		seedHex     string
+		extendedKey string
+		testnet     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "hd",
+		Short: "Hierarchical deterministic keys for secp256k1 (BIP-32) and secp256r1 (SLIP-10)",
+		Args:  cobra.NoArgs,
+	}
+
+	deriveCmd := &cobra.Command{
+		Use:   "derive [path]",
+		Short: "Derive the key at a path such as m/44'/0'/0'/0/0 from a seed or an extended key",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "m"
+			if len(args) == 1 {
+				path = args[0]
+			}
+
+			var root *hd.ExtendedKey
+			switch {
+			case seedHex != "" && extendedKey != "":
+				return errors.New("specify either a seed or an extended key")
+			case seedHex != "":
+				seed, err := hex.DecodeString(seedHex)
+				if err != nil {
+					return errors.New("invalid seed format")
+				}
+
+				network := hd.Mainnet
+				if testnet {
+					network = hd.Testnet
+				}
+
+				ecc, err := parseCurve(cmd)
+				if err != nil {
+					return err
+				}
+
+				if ecc.Name() == curveSecp256k1 {
+					root, err = hd.NewMaster(seed, network)
+				} else {
+					root, err = hd.NewMasterSLIP10(ecc, seed, network)
+				}
+				if err != nil {
+					return err
+				}
+			case extendedKey != "":
+				var err error
+				if root, err = hd.ParseExtendedKey(extendedKey); err != nil {
+					return err
+				}
+			default:
+				return errors.New("seed or extended key not specified")
+			}
+
+			key, err := root.Derive(path)
+			if err != nil {
+				return err
+			}
+
+			// only secp256k1 keys have a standard serialization
+			serialize := key.ECC().Name() == curveSecp256k1
+
+			fmt.Printf("path: %s\n", path)
+			fmt.Printf("fingerprint: %08x\n", key.Fingerprint())
+
+			if key.IsPrivate() {
+				privateKey, err := key.PrivateKey()
+				if err != nil {
+					return err
+				}
+
+				if serialize {
+					s, err := key.Serialize()
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("extended private key: %s\n", s)
+				}
+				fmt.Printf("private key: %x\n", privateKey.Bytes())
+			}
+
+			if serialize {
+				s, err := key.Neuter().Serialize()
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("extended public key: %s\n", s)
+			} else {
+				fmt.Printf("chain code: %x\n", key.ChainCode())
+			}
+			fmt.Printf("public key: %x\n", key.PublicKey().Uncompressed())
+
+			return nil
+		},
+	}
+	deriveCmd.Flags().StringVar(&seedHex, "seed", "", "Seed in hex format, 16 to 64 bytes, derived with SLIP-10 for curves other than secp256k1")
+	deriveCmd.Flags().StringVar(&extendedKey, "key", "", "Extended private or public key (xprv, xpub, tprv or tpub) the path is relative to")
+	deriveCmd.Flags().BoolVar(&testnet, "testnet", false, "Serialize the keys derived from --seed as tprv/tpub")
+
+	cmd.AddCommand(deriveCmd)
+
+	return cmd
+}
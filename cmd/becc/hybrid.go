@@ -7,6 +7,8 @@ import (
 )
 
 func hybridCmd() *cobra.Command {
+	var elligator bool
+
 	cmd := &cobra.Command{
 		Use:   "hybrid",
 		Short: "Hybrid encryption/decryption using elliptic curve + AES-GSM",
@@ -23,7 +25,12 @@ func hybridCmd() *cobra.Command {
 				return err
 			}
 
-			ciphertext, err := remotePubKey.Encrypt(os.Stdin)
+			encrypt := remotePubKey.Encrypt
+			if elligator {
+				encrypt = remotePubKey.EncryptElligator
+			}
+
+			ciphertext, err := encrypt(os.Stdin)
 			if err != nil {
 				return err
 			}
@@ -46,7 +53,12 @@ func hybridCmd() *cobra.Command {
 				return err
 			}
 
-			plaintext, err := privateKey.Decrypt(os.Stdin)
+			decrypt := privateKey.Decrypt
+			if elligator {
+				decrypt = privateKey.DecryptElligator
+			}
+
+			plaintext, err := decrypt(os.Stdin)
 			if err != nil {
 				return err
 			}
@@ -59,6 +71,8 @@ func hybridCmd() *cobra.Command {
 		},
 	}
 
+	cmd.PersistentFlags().BoolVar(&elligator, "elligator", false, "Encode the ephemeral public key with Elligator so the output looks like random bytes")
+
 	cmd.AddCommand(encryptCmd)
 	cmd.AddCommand(decryptCmd)
 
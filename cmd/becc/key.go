@@ -1,12 +1,34 @@
 package main
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/hd"
+	"github.com/artilugio0/becc/mnemonic"
+	"github.com/artilugio0/becc/shamir"
 	"github.com/spf13/cobra"
 )
 
+// mnemonicPathDefault is the BIP-44 path of the first receiving key of the
+// first Bitcoin account.
+const mnemonicPathDefault string = "m/44'/0'/0'/0/0"
+
 func keyCmd() *cobra.Command {
+	var (
+		threshold      int
+		shares         int
+		commitmentsHex string
+		withMnemonic   bool
+		words          int
+		passphrase     string
+		path           string
+	)
+
 	cmd := &cobra.Command{
 		Use:   "key",
 		Short: "Elliptic curve key operations",
@@ -14,7 +36,7 @@ func keyCmd() *cobra.Command {
 
 	genCmd := &cobra.Command{
 		Use:   "gen",
-		Short: "Generate a new elliptic curve key pair",
+		Short: "Generate a new elliptic curve key pair, or with --mnemonic a BIP-39 mnemonic and the key derived from it",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ecc, err := parseCurve(cmd)
@@ -22,18 +44,73 @@ func keyCmd() *cobra.Command {
 				return err
 			}
 
+			if withMnemonic {
+				if err := checkMnemonicCurve(ecc); err != nil {
+					return err
+				}
+
+				sentence, err := mnemonic.Generate(words)
+				if err != nil {
+					return err
+				}
+
+				privateKey, err := mnemonicKey(ecc, sentence, passphrase, path)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("mnemonic: %s\n", sentence)
+				printMnemonicKey(privateKey, path)
+
+				return nil
+			}
+
 			privateKey, publicKey, err := ecc.GenKeyPair()
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("private key: %064x\n", privateKey.Int())
-			fmt.Printf("public key: 04%064x%064x\n", publicKey.X(), publicKey.Y())
+			fmt.Printf("private key: %x\n", privateKey.Bytes())
+			fmt.Printf("public key: %x\n", publicKey.Uncompressed())
+
+			return nil
+		},
+	}
+
+	genCmd.Flags().BoolVar(&withMnemonic, "mnemonic", false, "Generate a BIP-39 mnemonic and derive the key from it at --path")
+	genCmd.Flags().IntVar(&words, "words", 24, "Number of words of the mnemonic: 12, 15, 18, 21 or 24")
+
+	recoverCmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Derive the key of a BIP-39 mnemonic read from stdin",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ecc, err := parseCurve(cmd)
+			if err != nil {
+				return err
+			}
+
+			sentence, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+
+			privateKey, err := mnemonicKey(ecc, string(sentence), passphrase, path)
+			if err != nil {
+				return err
+			}
+
+			printMnemonicKey(privateKey, path)
 
 			return nil
 		},
 	}
 
+	for _, c := range []*cobra.Command{genCmd, recoverCmd} {
+		c.Flags().StringVar(&passphrase, "passphrase", "", "Optional BIP-39 passphrase")
+		c.Flags().StringVar(&path, "path", mnemonicPathDefault, "BIP-32 derivation path of the key")
+	}
+
 	publicCmd := &cobra.Command{
 		Use:   "public",
 		Short: "Get the public key of a private key",
@@ -46,14 +123,148 @@ func keyCmd() *cobra.Command {
 
 			publicKey := privateKey.PublicKey()
 
-			fmt.Printf("04%064x%064x\n", publicKey.X(), publicKey.Y())
+			fmt.Printf("%x\n", publicKey.Uncompressed())
+
+			return nil
+		},
+	}
+
+	splitCmd := &cobra.Command{
+		Use:   "split",
+		Short: "Split the private key into shares, any threshold of which recover it (Shamir secret sharing)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			privateKey, err := parsePrivateKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			keyShares, commitments, err := shamir.Split(privateKey, threshold, shares)
+			if err != nil {
+				return err
+			}
+
+			for _, share := range keyShares {
+				fmt.Printf("share %d: %x\n", share.Index(), share.Bytes())
+			}
+			fmt.Printf("commitments: %x\n", commitments.Bytes())
+
+			return nil
+		},
+	}
+	splitCmd.Flags().IntVarP(&threshold, "threshold", "t", 2, "Number of shares needed to recover the key")
+	splitCmd.Flags().IntVarP(&shares, "shares", "n", 3, "Number of shares")
+
+	combineCmd := &cobra.Command{
+		Use:   "combine share...",
+		Short: "Recover a private key from its shares, checking them first if --commitments is set",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ecc, err := parseCurve(cmd)
+			if err != nil {
+				return err
+			}
+
+			keyShares := make([]shamir.Share, len(args))
+			for i, arg := range args {
+				shareBytes, err := hex.DecodeString(arg)
+				if err != nil {
+					return errors.New("invalid share format")
+				}
+
+				if keyShares[i], err = shamir.ParseShare(ecc, shareBytes); err != nil {
+					return err
+				}
+			}
+
+			if commitmentsHex != "" {
+				commitmentsBytes, err := hex.DecodeString(commitmentsHex)
+				if err != nil {
+					return errors.New("invalid commitments format")
+				}
+
+				commitments, err := shamir.ParseCommitments(ecc, commitmentsBytes)
+				if err != nil {
+					return err
+				}
+
+				publicKey, err := commitments.PublicKey()
+				if err != nil {
+					return err
+				}
+
+				for _, share := range keyShares {
+					if !commitments.Verify(publicKey, share) {
+						return fmt.Errorf("%w: share %d does not match the commitments", shamir.ErrInvalidShare, share.Index())
+					}
+				}
+			}
+
+			privateKey, err := shamir.Combine(keyShares)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("private key: %x\n", privateKey.Bytes())
+			fmt.Printf("public key: %x\n", privateKey.PublicKey().Uncompressed())
 
 			return nil
 		},
 	}
+	combineCmd.Flags().StringVar(&commitmentsHex, "commitments", "", "Commitments printed by split, to check every share")
 
 	cmd.AddCommand(genCmd)
+	cmd.AddCommand(recoverCmd)
 	cmd.AddCommand(publicCmd)
+	cmd.AddCommand(splitCmd)
+	cmd.AddCommand(combineCmd)
 
 	return cmd
 }
+
+// checkMnemonicCurve returns an error for the curves mnemonic keys cannot be
+// derived for: BIP-32 is only defined for secp256k1, and SLIP-10 extends it
+// to secp256r1.
+func checkMnemonicCurve(ecc *becc.ECC) error {
+	if ecc.Name() != curveSecp256k1 && ecc.Name() != curveSecp256r1 {
+		return fmt.Errorf("%w: mnemonic keys are derived with BIP-32 for secp256k1 and SLIP-10 for secp256r1", becc.ErrUnsupportedCurve)
+	}
+
+	return nil
+}
+
+// mnemonicKey returns the key derived from the seed of the mnemonic at the
+// BIP-32 path, with the SLIP-10 master key for secp256r1.
+func mnemonicKey(ecc *becc.ECC, sentence, passphrase, path string) (becc.PrivateKey, error) {
+	if err := checkMnemonicCurve(ecc); err != nil {
+		return becc.PrivateKey{}, err
+	}
+
+	seed, err := mnemonic.Seed(sentence, passphrase)
+	if err != nil {
+		return becc.PrivateKey{}, err
+	}
+
+	var master *hd.ExtendedKey
+	if ecc.Name() == curveSecp256k1 {
+		master, err = hd.NewMaster(seed, hd.Mainnet)
+	} else {
+		master, err = hd.NewMasterSLIP10(ecc, seed, hd.Mainnet)
+	}
+	if err != nil {
+		return becc.PrivateKey{}, err
+	}
+
+	key, err := master.Derive(path)
+	if err != nil {
+		return becc.PrivateKey{}, err
+	}
+
+	return key.PrivateKey()
+}
+
+func printMnemonicKey(privateKey becc.PrivateKey, path string) {
+	fmt.Printf("path: %s\n", path)
+	fmt.Printf("private key: %x\n", privateKey.Bytes())
+	fmt.Printf("public key: %x\n", privateKey.PublicKey().Uncompressed())
+}
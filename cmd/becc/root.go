@@ -5,11 +5,13 @@ import "github.com/spf13/cobra"
 const (
 	curveDefault   string = curveSecp256k1
 	curveSecp256k1 string = "secp256k1"
+	curveSecp256r1 string = "secp256r1"
 )
 
 func beccCmd() *cobra.Command {
 	var (
 		curve         string
+		curveFile     string
 		privateKeyHex string
 		publicKeyHex  string
 	)
@@ -21,6 +23,7 @@ func beccCmd() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVarP(&curve, "curve", "c", curveDefault, "Elliptic curve to use")
+	cmd.PersistentFlags().StringVar(&curveFile, "curve-file", "", "JSON or PEM file with the domain parameters of a custom curve (overrides --curve)")
 	cmd.PersistentFlags().StringVarP(&privateKeyHex, "private-key", "k", "", "Private key in hex format")
 	cmd.PersistentFlags().StringVarP(&publicKeyHex, "public-key", "p", "", "Public key in hex format")
 
@@ -28,6 +31,12 @@ func beccCmd() *cobra.Command {
 	cmd.AddCommand(ecdhCmd())
 	cmd.AddCommand(keyCmd())
 	cmd.AddCommand(hybridCmd())
+	cmd.AddCommand(curveCmd())
+	cmd.AddCommand(zkCmd())
+	cmd.AddCommand(vrfCmd())
+	cmd.AddCommand(hdCmd())
+	cmd.AddCommand(btcCmd())
+	cmd.AddCommand(ethCmd())
 
 	return cmd
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/artilugio0/becc"
+	"github.com/spf13/cobra"
+)
+
+func vrfCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vrf",
+		Short: "Verifiable random functions (ECVRF, RFC 9381)",
+		Long: `Verifiable random functions (ECVRF, RFC 9381).
+
+secp256r1 uses the ECVRF-P256-SHA256-TAI suite of RFC 9381. secp256k1 uses
+NONSTANDARD-ECVRF-SECP256K1-SHA256-TAI, a suite of this tool that no
+specification defines: its proofs can only be checked by becc.`,
+		Args: cobra.NoArgs,
+	}
+
+	proveCmd := &cobra.Command{
+		Use:   "prove",
+		Short: "Compute the VRF output and its proof for the input read from stdin",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			privateKey, err := parsePrivateKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			alpha, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+
+			pi, err := privateKey.VRFProve(alpha)
+			if err != nil {
+				return err
+			}
+
+			beta, err := privateKey.ECC().VRFProofToHash(pi)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("proof: %x\n", pi)
+			fmt.Printf("output: %x\n", beta)
+
+			return nil
+		},
+	}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify proof",
+		Short: "Verify a VRF proof for the input read from stdin and print the output",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			publicKey, err := parsePublicKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			pi, err := hex.DecodeString(args[0])
+			if err != nil {
+				return errors.New("invalid proof format")
+			}
+
+			alpha, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+
+			beta, err := publicKey.VRFVerify(alpha, pi)
+			if errors.Is(err, becc.ErrInvalidVRFProof) {
+				fmt.Println("invalid proof")
+				os.Exit(1)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Println("valid proof")
+			fmt.Printf("output: %x\n", beta)
+
+			return nil
+		},
+	}
+
+	cmd.AddCommand(proveCmd)
+	cmd.AddCommand(verifyCmd)
+
+	return cmd
+}
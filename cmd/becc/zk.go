@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/artilugio0/becc/zk"
+	"github.com/spf13/cobra"
+)
+
+func zkCmd() *cobra.Command {
+	var (
+		context    string
+		baseHex    string
+		baseKeyHex string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "zk",
+		Short: "Zero-knowledge proofs of discrete logarithms (Schnorr and Chaum-Pedersen)",
+		Args:  cobra.NoArgs,
+	}
+
+	proveCmd := &cobra.Command{
+		Use:   "prove",
+		Short: "Prove knowledge of the private key, or with --base that x·H has the same discrete logarithm",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			privateKey, err := parsePrivateKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			transcript := zkTranscript(context)
+
+			if baseHex == "" {
+				proof, err := zk.ProveKnowledge(transcript, privateKey)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("proof: %x\n", proof.Bytes())
+
+				return nil
+			}
+
+			base, err := parsePublicKeyString(cmd, baseHex)
+			if err != nil {
+				return fmt.Errorf("invalid base: %w", err)
+			}
+
+			q, proof, err := zk.ProveDLEQ(transcript, privateKey, base.Point())
+			if err != nil {
+				return err
+			}
+
+			baseKey, err := privateKey.ECC().NewPublicKey(q)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("base key: %x\n", baseKey.Uncompressed())
+			fmt.Printf("proof: %x\n", proof.Bytes())
+
+			return nil
+		},
+	}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify proof",
+		Short: "Verify a proof of knowledge of the private key of the public key, or with --base a DLEQ proof",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			publicKey, err := parsePublicKey(cmd)
+			if err != nil {
+				return err
+			}
+
+			proofBytes, err := hex.DecodeString(args[0])
+			if err != nil {
+				return errors.New("invalid proof format")
+			}
+
+			proof, err := zk.ParseProof(publicKey.ECC(), proofBytes)
+			if err != nil {
+				return err
+			}
+
+			transcript := zkTranscript(context)
+
+			var valid bool
+			if baseHex == "" {
+				valid = zk.VerifyKnowledge(transcript, publicKey, proof)
+			} else {
+				if baseKeyHex == "" {
+					return errors.New("base key not specified")
+				}
+
+				base, err := parsePublicKeyString(cmd, baseHex)
+				if err != nil {
+					return fmt.Errorf("invalid base: %w", err)
+				}
+
+				baseKey, err := parsePublicKeyString(cmd, baseKeyHex)
+				if err != nil {
+					return fmt.Errorf("invalid base key: %w", err)
+				}
+
+				valid = zk.VerifyDLEQ(transcript, publicKey, base.Point(), baseKey.Point(), proof)
+			}
+
+			if valid {
+				fmt.Println("valid proof")
+			} else {
+				fmt.Println("invalid proof")
+				os.Exit(1)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&context, "context", "", "Context the proof is bound to, such as a session identifier")
+	cmd.PersistentFlags().StringVar(&baseHex, "base", "", "Second base H in hex format, for a DLEQ proof")
+	verifyCmd.Flags().StringVar(&baseKeyHex, "base-key", "", "Point x·H in hex format, for a DLEQ proof")
+
+	cmd.AddCommand(proveCmd)
+	cmd.AddCommand(verifyCmd)
+
+	return cmd
+}
+
+// zkTranscript returns the transcript the CLI proofs are made over.
+func zkTranscript(context string) *zk.Transcript {
+	t := zk.NewTranscript("becc zk cli v1")
+	t.AppendMessage("context", []byte(context))
+
+	return t
+}
@@ -39,7 +39,10 @@ func main() {
 
 	fmt.Println("\n\nDeterministic test:")
 	detD, _ := new(big.Int).SetString("0a6fb225cf7962e5f1ce83af725fdb62e611f5c5b8126433ee2a457aa2806683", 16)
-	detKey := ecc.NewPrivateKey(detD)
+	detKey, err := ecc.NewPrivateKey(detD)
+	if err != nil {
+		panic(err)
+	}
 	detPubKey := detKey.PublicKey()
 	detSig, err := detKey.SignDeterministic(becc.SHA256, message, true)
 	if err != nil {
@@ -34,6 +34,62 @@ var (
 	Secp521r1Gy, _ = new(big.Int).SetString("011839296A789A3BC0045C8A5FB42C7D1BD998F54449579B446817AFBD17273E662C97EE72995EF42640C550B9013FAD0761353C7086A272C24088BE94769FD16650", 16)
 	Secp521r1N, _  = new(big.Int).SetString("01FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFA51868783BF2F966B7FCC0148F709A5D03BB5C9B8899C47AEBB6FB71E91386409", 16)
 	Secp521r1H     = big.NewInt(1)
+
+	BrainpoolP256r1A, _  = new(big.Int).SetString("7D5A0975FC2C3057EEF67530417AFFE7FB8055C126DC5C6CE94A4B44F330B5D9", 16)
+	BrainpoolP256r1B, _  = new(big.Int).SetString("26DC5C6CE94A4B44F330B5D9BBD77CBF958416295CF7E1CE6BCCDC18FF8C07B6", 16)
+	BrainpoolP256r1P, _  = new(big.Int).SetString("A9FB57DBA1EEA9BC3E660A909D838D726E3BF623D52620282013481D1F6E5377", 16)
+	BrainpoolP256r1Gx, _ = new(big.Int).SetString("8BD2AEB9CB7E57CB2C4B482FFC81B7AFB9DE27E1E3BD23C23A4453BD9ACE3262", 16)
+	BrainpoolP256r1Gy, _ = new(big.Int).SetString("547EF835C3DAC4FD97F8461A14611DC9C27745132DED8E545C1D54C72F046997", 16)
+	BrainpoolP256r1N, _  = new(big.Int).SetString("A9FB57DBA1EEA9BC3E660A909D838D718C397AA3B561A6F7901E0E82974856A7", 16)
+	BrainpoolP256r1H     = big.NewInt(1)
+
+	BrainpoolP256t1A, _  = new(big.Int).SetString("A9FB57DBA1EEA9BC3E660A909D838D726E3BF623D52620282013481D1F6E5374", 16)
+	BrainpoolP256t1B, _  = new(big.Int).SetString("662C61C430D84EA4FE66A7733D0B76B7BF93EBC4AF2F49256AE58101FEE92B04", 16)
+	BrainpoolP256t1P, _  = new(big.Int).SetString("A9FB57DBA1EEA9BC3E660A909D838D726E3BF623D52620282013481D1F6E5377", 16)
+	BrainpoolP256t1Gx, _ = new(big.Int).SetString("A3E8EB3CC1CFE7B7732213B23A656149AFA142C47AAFBC2B79A191562E1305F4", 16)
+	BrainpoolP256t1Gy, _ = new(big.Int).SetString("2D996C823439C56D7F7B22E14644417E69BCB6DE39D027001DABE8F35B25C9BE", 16)
+	BrainpoolP256t1N, _  = new(big.Int).SetString("A9FB57DBA1EEA9BC3E660A909D838D718C397AA3B561A6F7901E0E82974856A7", 16)
+	BrainpoolP256t1H     = big.NewInt(1)
+
+	BrainpoolP384r1A, _  = new(big.Int).SetString("7BC382C63D8C150C3C72080ACE05AFA0C2BEA28E4FB22787139165EFBA91F90F8AA5814A503AD4EB04A8C7DD22CE2826", 16)
+	BrainpoolP384r1B, _  = new(big.Int).SetString("04A8C7DD22CE28268B39B55416F0447C2FB77DE107DCD2A62E880EA53EEB62D57CB4390295DBC9943AB78696FA504C11", 16)
+	BrainpoolP384r1P, _  = new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B412B1DA197FB71123ACD3A729901D1A71874700133107EC53", 16)
+	BrainpoolP384r1Gx, _ = new(big.Int).SetString("1D1C64F068CF45FFA2A63A81B7C13F6B8847A3E77EF14FE3DB7FCAFE0CBD10E8E826E03436D646AAEF87B2E247D4AF1E", 16)
+	BrainpoolP384r1Gy, _ = new(big.Int).SetString("8ABE1D7520F9C2A45CB1EB8E95CFD55262B70B29FEEC5864E19C054FF99129280E4646217791811142820341263C5315", 16)
+	BrainpoolP384r1N, _  = new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B31F166E6CAC0425A7CF3AB6AF6B7FC3103B883202E9046565", 16)
+	BrainpoolP384r1H     = big.NewInt(1)
+
+	BrainpoolP384t1A, _  = new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B412B1DA197FB71123ACD3A729901D1A71874700133107EC50", 16)
+	BrainpoolP384t1B, _  = new(big.Int).SetString("7F519EADA7BDA81BD826DBA647910F8C4B9346ED8CCDC64E4B1ABD11756DCE1D2074AA263B88805CED70355A33B471EE", 16)
+	BrainpoolP384t1P, _  = new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B412B1DA197FB71123ACD3A729901D1A71874700133107EC53", 16)
+	BrainpoolP384t1Gx, _ = new(big.Int).SetString("18DE98B02DB9A306F2AFCD7235F72A819B80AB12EBD653172476FECD462AABFFC4FF191B946A5F54D8D0AA2F418808CC", 16)
+	BrainpoolP384t1Gy, _ = new(big.Int).SetString("25AB056962D30651A114AFD2755AD336747F93475B7A1FCA3B88F2B6A208CCFE469408584DC2B2912675BF5B9E582928", 16)
+	BrainpoolP384t1N, _  = new(big.Int).SetString("8CB91E82A3386D280F5D6F7E50E641DF152F7109ED5456B31F166E6CAC0425A7CF3AB6AF6B7FC3103B883202E9046565", 16)
+	BrainpoolP384t1H     = big.NewInt(1)
+
+	BrainpoolP512r1A, _  = new(big.Int).SetString("7830A3318B603B89E2327145AC234CC594CBDD8D3DF91610A83441CAEA9863BC2DED5D5AA8253AA10A2EF1C98B9AC8B57F1117A72BF2C7B9E7C1AC4D77FC94CA", 16)
+	BrainpoolP512r1B, _  = new(big.Int).SetString("3DF91610A83441CAEA9863BC2DED5D5AA8253AA10A2EF1C98B9AC8B57F1117A72BF2C7B9E7C1AC4D77FC94CADC083E67984050B75EBAE5DD2809BD638016F723", 16)
+	BrainpoolP512r1P, _  = new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA703308717D4D9B009BC66842AECDA12AE6A380E62881FF2F2D82C68528AA6056583A48F3", 16)
+	BrainpoolP512r1Gx, _ = new(big.Int).SetString("81AEE4BDD82ED9645A21322E9C4C6A9385ED9F70B5D916C1B43B62EEF4D0098EFF3B1F78E2D0D48D50D1687B93B97D5F7C6D5047406A5E688B352209BCB9F822", 16)
+	BrainpoolP512r1Gy, _ = new(big.Int).SetString("7DDE385D566332ECC0EABFA9CF7822FDF209F70024A57B1AA000C55B881F8111B2DCDE494A5F485E5BCA4BD88A2763AED1CA2B2FA8F0540678CD1E0F3AD80892", 16)
+	BrainpoolP512r1N, _  = new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA70330870553E5C414CA92619418661197FAC10471DB1D381085DDADDB58796829CA90069", 16)
+	BrainpoolP512r1H     = big.NewInt(1)
+
+	BrainpoolP512t1A, _  = new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA703308717D4D9B009BC66842AECDA12AE6A380E62881FF2F2D82C68528AA6056583A48F0", 16)
+	BrainpoolP512t1B, _  = new(big.Int).SetString("7CBBBCF9441CFAB76E1890E46884EAE321F70C0BCB4981527897504BEC3E36A62BCDFA2304976540F6450085F2DAE145C22553B465763689180EA2571867423E", 16)
+	BrainpoolP512t1P, _  = new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA703308717D4D9B009BC66842AECDA12AE6A380E62881FF2F2D82C68528AA6056583A48F3", 16)
+	BrainpoolP512t1Gx, _ = new(big.Int).SetString("640ECE5C12788717B9C1BA06CBC2A6FEBA85842458C56DDE9DB1758D39C0313D82BA51735CDB3EA499AA77A7D6943A64F7A3F25FE26F06B51BAA2696FA9035DA", 16)
+	BrainpoolP512t1Gy, _ = new(big.Int).SetString("5B534BD595F5AF0FA2C892376C84ACE1BB4E3019B71634C01131159CAE03CEE9D9932184BEEF216BD71DF2DADF86A627306ECFF96DBB8BACE198B61E00F8B332", 16)
+	BrainpoolP512t1N, _  = new(big.Int).SetString("AADD9DB8DBE9C48B3FD4E6AE33C9FC07CB308DB3B3C9D20ED6639CCA70330870553E5C414CA92619418661197FAC10471DB1D381085DDADDB58796829CA90069", 16)
+	BrainpoolP512t1H     = big.NewInt(1)
+
+	SM2A, _  = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFC", 16)
+	SM2B, _  = new(big.Int).SetString("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93", 16)
+	SM2P, _  = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF", 16)
+	SM2Gx, _ = new(big.Int).SetString("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7", 16)
+	SM2Gy, _ = new(big.Int).SetString("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0", 16)
+	SM2N, _  = new(big.Int).SetString("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123", 16)
+	SM2H     = big.NewInt(1)
 )
 
 func Secp256k1() (EllipticCurve, Point, *big.Int) {
@@ -79,3 +135,80 @@ func Secp521r1() (EllipticCurve, Point, *big.Int) {
 
 	return ec, g, Secp521r1N
 }
+
+func BrainpoolP256r1() (EllipticCurve, Point, *big.Int) {
+	ec, err := NewEllipticCurve(BrainpoolP256r1A, BrainpoolP256r1B, BrainpoolP256r1P)
+	if err != nil {
+		panic(err)
+	}
+
+	g := ec.NewPoint(BrainpoolP256r1Gx, BrainpoolP256r1Gy)
+
+	return ec, g, BrainpoolP256r1N
+}
+
+func BrainpoolP256t1() (EllipticCurve, Point, *big.Int) {
+	ec, err := NewEllipticCurve(BrainpoolP256t1A, BrainpoolP256t1B, BrainpoolP256t1P)
+	if err != nil {
+		panic(err)
+	}
+
+	g := ec.NewPoint(BrainpoolP256t1Gx, BrainpoolP256t1Gy)
+
+	return ec, g, BrainpoolP256t1N
+}
+
+func BrainpoolP384r1() (EllipticCurve, Point, *big.Int) {
+	ec, err := NewEllipticCurve(BrainpoolP384r1A, BrainpoolP384r1B, BrainpoolP384r1P)
+	if err != nil {
+		panic(err)
+	}
+
+	g := ec.NewPoint(BrainpoolP384r1Gx, BrainpoolP384r1Gy)
+
+	return ec, g, BrainpoolP384r1N
+}
+
+func BrainpoolP384t1() (EllipticCurve, Point, *big.Int) {
+	ec, err := NewEllipticCurve(BrainpoolP384t1A, BrainpoolP384t1B, BrainpoolP384t1P)
+	if err != nil {
+		panic(err)
+	}
+
+	g := ec.NewPoint(BrainpoolP384t1Gx, BrainpoolP384t1Gy)
+
+	return ec, g, BrainpoolP384t1N
+}
+
+func BrainpoolP512r1() (EllipticCurve, Point, *big.Int) {
+	ec, err := NewEllipticCurve(BrainpoolP512r1A, BrainpoolP512r1B, BrainpoolP512r1P)
+	if err != nil {
+		panic(err)
+	}
+
+	g := ec.NewPoint(BrainpoolP512r1Gx, BrainpoolP512r1Gy)
+
+	return ec, g, BrainpoolP512r1N
+}
+
+func BrainpoolP512t1() (EllipticCurve, Point, *big.Int) {
+	ec, err := NewEllipticCurve(BrainpoolP512t1A, BrainpoolP512t1B, BrainpoolP512t1P)
+	if err != nil {
+		panic(err)
+	}
+
+	g := ec.NewPoint(BrainpoolP512t1Gx, BrainpoolP512t1Gy)
+
+	return ec, g, BrainpoolP512t1N
+}
+
+func SM2() (EllipticCurve, Point, *big.Int) {
+	ec, err := NewEllipticCurve(SM2A, SM2B, SM2P)
+	if err != nil {
+		panic(err)
+	}
+
+	g := ec.NewPoint(SM2Gx, SM2Gy)
+
+	return ec, g, SM2N
+}
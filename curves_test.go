@@ -1,6 +1,7 @@
 package becc
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"testing"
@@ -53,3 +54,112 @@ func point(t *testing.T, ec EllipticCurve, x, y string) Point {
 
 	return ec.NewPoint(px, py)
 }
+
+func TestNamedCurvesKnownAnswers(t *testing.T) {
+	// keys and ECDSA-SHA256 signatures generated with OpenSSL
+	tt := []struct {
+		name      string
+		ecc       *ECC
+		priv, pub string
+		r, s      string
+	}{
+		{
+			name: "brainpoolP256r1",
+			ecc:  BrainpoolP256r1ECC(),
+			priv: "0755b0b04f8d55474fd94107065bef099b5ced02dcb84172ce74cae7395013bd",
+			pub:  "042dfb3dbfc225c8fb305be4aa9a974151a68a5c1c2dddd0b92fce62a34ef86ecf2d7784f3027be02bc8cc4e12495702c2d5bf993f0800666311363f292a7b11f4",
+			r:    "86501EB2AA7794768C134C5BE22473BDBC164CC07387D6A92D9DBE1CE93973BF",
+			s:    "9F32D49B89F7DC1839A0343A1E099C5155CA81022FD397C9E372AF848058884C",
+		},
+		{
+			name: "brainpoolP256t1",
+			ecc:  BrainpoolP256t1ECC(),
+			priv: "1643a68f1c34a697563de5ee6c709e4b7b85d305104bde8c51e748e4303d8069",
+			pub:  "047edad33fe0ce10230f1b24ee0111a76bcc1662f1fca8ab1046a3dba8c86322e65b55371c5625a45b21690090f881810e250d09604ea09c80e7ebd3661644ba49",
+			r:    "91BAC72995E641465BF89F11A3A8F33EA384E246878D12C78C740F384CAE3A96",
+			s:    "38B859B5738193A9780AB05055BD7FDB2CB7DF9C2B9C924DF0C1BCA78A3DFAE2",
+		},
+		{
+			name: "brainpoolP384r1",
+			ecc:  BrainpoolP384r1ECC(),
+			priv: "289ea6d610873f52e4f12c3c23385401f6f8bc5fc8e21f1078b2f1843122681fd43b5965d9e53901fe4c933495f7b4dc",
+			pub:  "042371a04cba57d9392ad3e922bd39989570c55d9fd87e716081f44abef6b71b86f228efebd87a2f1c4157c1eae58a35c324f1ae013fa6053c1cd9c1f74dbafa9fccf2e654b80c35a11e5e8e396b64db9dd30fb8295f9f704c9166ca3c1488aff2",
+			r:    "5DB0D32D5B73441559BF7CE731D1EBF1BC78871E6504C49EAF0C8F4177764AF8F67C9AB5767DDB9EBB27CB1D545D28CE",
+			s:    "7C964992A76868F9D027E7A4E57E0C61BB680CE1BB89F98C68CD86A07F1BC3B336091D61908735FBBBD0D187C9632732",
+		},
+		{
+			name: "brainpoolP384t1",
+			ecc:  BrainpoolP384t1ECC(),
+			priv: "6ea73504cfa0e89f93c0f17665cb83f0c92f7bcd6ae3606b51f35b1ab095f4f74447ee6354b060902d8f8aecc450e209",
+			pub:  "0447fa7ea107ae97266dacc05c9cb7a111b54d774eb28c2be79997949411348fa5f730115e56fa721b9fa5ccebbb86546d5f2a2cecb8a6f5e210d6e6454c2186f8c10e19fda632887089e397452a3aed96788d7bc51189512377a324153a449761",
+			r:    "14594CBEE01E903F6187D4826B0B88034AC56D4BACC97CE50232DCCC487F8BAD4A54E01C75281B0F97B8DA3352F16BA0",
+			s:    "805524182FFB7DF6674E557029E87CC924930999FC59CC17927FF8B27C2111D8CA943A7DBBCB9AC12C427B26DE78B596",
+		},
+		{
+			name: "brainpoolP512r1",
+			ecc:  BrainpoolP512r1ECC(),
+			priv: "9dfe27f38aab6a9f389b060b22728a827b98d66b6d77c28063f89511b00a753a289660ad2a25da17430a3b045128c7a7c406853f4294720a80de4ae09090d4c4",
+			pub:  "047c3c7bf90125511798986ae45543ce6e10cb7510c385b06c9a392b2b7cf2a90706d26048075c0290839ed8017200ada76fd83b9dd7f151577ed89d81cddfe0bd7096888e3776b7b458a05c20974d060be61c8b793ef289dc0089fa27f27f5a1f9bafb5aae3c377a5abbc0227b879b417a79ee89a68ea2ce6c22fb0e18896cb54",
+			r:    "417E022C6F0DD53B121E69FCE10E8A42D0EF2A4911DC8B48277529D3EE054EB0C0AC7D496301ED9188CAEC6E1D9C133F19EFB7D777991E832FCA843AD9686B4E",
+			s:    "2631B190062DFC19E68AB7F808B3C8FB5F18F6C108350B22A1BD9F7F1D7E88A9C0289DF462201F1CF75AD00024B66D5B508159FC3CF1539E07038BA27FEF0887",
+		},
+		{
+			name: "brainpoolP512t1",
+			ecc:  BrainpoolP512t1ECC(),
+			priv: "3e6475480f5ac9b7159348b66bff0420320608a10e93ec9a0f91e77597fb114a4402b827f7eb118d8a921a67f8b06a01b36fc28fa31c9550deb4e661fb5060bf",
+			pub:  "04224e6b9a69a70c06215d372055f0e3121709de8d953ce00113ae023d1f783aa3b2df67c3653ef99e81df8e46aa3df4e36829d9b9e1f877e3f9ae91a174241ee26ab0d3d9899dea892ca31f14c9401c8910fb370c5556e6e8e774ea5a34471be1b078be2a47e93107f6156816d2b8f9f456484d462c826e8fea68c32f6e9ea918",
+			r:    "9FEDB3B3C19494FCB8C610A1820A963C993F14D9EFBE1229AFCA6C145CB8144E1D2474407E8D80D91DEF209C7CEDDB006F3A9027F8393147D748DA694D1BA511",
+			s:    "4B3CF40566AB51D2CA186F14BCD636507EB5A5B76E3AFEAF936C895843A4930338EEE8E2F4843478214F0D6623276D3C4E3599C868699940A2B556089BE2AC80",
+		},
+		{
+			// OpenSSL signs with the SM2 signature scheme on this curve, so
+			// the signature was made with explicit parameters whose generator
+			// is -G: the nonce k gives the same r = x(k·G) and s, so it is also
+			// the ECDSA signature of the key for the generator G.
+			name: "sm2p256v1",
+			ecc:  SM2ECC(),
+			priv: "37b383ea589a7c4e214fb9a321ae5b6b3d57ae93380e9cfe333409d9af3baba8",
+			pub:  "041a092f192844c50a827c42c68114646ee00fe727eac1f8bdea0974f853b7b8e057ea4ced2d56f148347c00511086c00140e8beb660be6f71aab8b2fd2a0c019c",
+			r:    "8405F05D7FF4DBB7708914E93ABE314DF95F88C8B47EEF515A9530922A793937",
+			s:    "014D1FACF0B4C7ED5B619FBB916F87B4A6FCF54B7003D149C861B46C0748F1AA",
+		},
+	}
+
+	msg := []byte("becc known answer test")
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.ecc.g.ScalarMul(tc.ecc.n).IsInfinity() {
+				t.Fatalf("n·G is not the point at infinity")
+			}
+
+			priv, err := tc.ecc.NewPrivateKeyBytes(hexBytes(t, tc.priv))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			pub := priv.PublicKey()
+			if got := hex.EncodeToString(pub.Uncompressed()); got != tc.pub {
+				t.Errorf("public key: got %s, expected %s", got, tc.pub)
+			}
+
+			sig := NewSignature(bigIntHex(t, tc.r), bigIntHex(t, tc.s))
+			if !pub.Verify(SHA256, msg, sig) {
+				t.Errorf("known answer signature verification failed")
+			}
+
+			if pub.Verify(SHA256, append(msg, 'x'), sig) {
+				t.Errorf("verification succeeded with invalid message")
+			}
+
+			sig, err = priv.SignDeterministic(SHA256, msg, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !pub.Verify(SHA256, msg, sig) {
+				t.Errorf("deterministic signature verification failed")
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package becc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrInvalidDomainParameters error = errors.New("invalid domain parameters")
+
+// movDegreeBound is the bound B of SEC 1 (section 3.1.1.2.1): the curve is
+// rejected when n divides p^k - 1 for some 1 <= k < movDegreeBound.
+const movDegreeBound = 100
+
+// NewECC returns an ECC instance for a user-defined curve. The domain
+// parameters are validated before building it: the field modulus and the
+// order n must be prime, the generator must be a point of the curve with
+// n·G = ∞, h·n must be within the Hasse bound, and the curve must not be
+// anomalous (h·n = p) or vulnerable to the MOV attack (small embedding degree).
+func NewECC(curve EllipticCurve, generator Point, order, cofactor *big.Int) (*ECC, error) {
+	p := curve.m
+
+	if p.Cmp(big.NewInt(3)) <= 0 || !p.ProbablyPrime(20) {
+		return nil, fmt.Errorf("%w: field modulus is not a prime greater than 3", ErrInvalidDomainParameters)
+	}
+
+	if order.Sign() <= 0 || !order.ProbablyPrime(20) {
+		return nil, fmt.Errorf("%w: order is not prime", ErrInvalidDomainParameters)
+	}
+
+	if cofactor.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: cofactor is not positive", ErrInvalidDomainParameters)
+	}
+
+	if generator.IsInfinity() {
+		return nil, fmt.Errorf("%w: generator is the point at infinity", ErrInvalidDomainParameters)
+	}
+
+	g := curve.NewPoint(generator.x.n, generator.y.n)
+	if !curve.IsOnCurve(g) {
+		return nil, fmt.Errorf("%w: generator not in curve", ErrInvalidDomainParameters)
+	}
+
+	if !g.ScalarMul(order).IsInfinity() {
+		return nil, fmt.Errorf("%w: n·G is not the point at infinity", ErrInvalidDomainParameters)
+	}
+
+	// Hasse: |#E - (p + 1)| <= 2·sqrt(p), that is (#E - p - 1)^2 <= 4p
+	pointCount := new(big.Int).Mul(order, cofactor)
+	trace := new(big.Int).Sub(new(big.Int).Add(p, bi1), pointCount)
+	if new(big.Int).Mul(trace, trace).Cmp(new(big.Int).Mul(p, bi4)) > 0 {
+		return nil, fmt.Errorf("%w: h·n outside the Hasse bound", ErrInvalidDomainParameters)
+	}
+
+	if pointCount.Cmp(p) == 0 {
+		return nil, fmt.Errorf("%w: anomalous curve (#E = p)", ErrInvalidDomainParameters)
+	}
+
+	if k := embeddingDegree(p, order, movDegreeBound); k > 0 {
+		return nil, fmt.Errorf("%w: embedding degree %d is too small (MOV attack)", ErrInvalidDomainParameters, k)
+	}
+
+	return &ECC{
+		ec:       curve,
+		g:        g,
+		n:        new(big.Int).Set(order),
+		h:        new(big.Int).Set(cofactor),
+		security: order.BitLen() / 2,
+	}, nil
+}
+
+// embeddingDegree returns the smallest k in [1, bound) such that n divides
+// p^k - 1, or 0 if there is none.
+func embeddingDegree(p, n *big.Int, bound int) int {
+	pk := new(big.Int).Mod(p, n)
+	q := new(big.Int).Set(pk)
+	for k := 1; k < bound; k++ {
+		if q.Cmp(bi1) == 0 {
+			return k
+		}
+
+		q.Mul(q, pk).Mod(q, n)
+	}
+
+	return 0
+}
+
+// Equal reports whether e and f have the same domain parameters p, a, b, G,
+// n and h. Names are not compared: custom curves have none, and a curve may
+// be known by several.
+func (e *ECC) Equal(f *ECC) bool {
+	if e == nil || f == nil {
+		return e == f
+	}
+
+	return e.ec.m.Cmp(f.ec.m) == 0 &&
+		e.ec.a.n.Cmp(f.ec.a.n) == 0 &&
+		e.ec.b.n.Cmp(f.ec.b.n) == 0 &&
+		e.g.Eq(f.g) &&
+		e.n.Cmp(f.n) == 0 &&
+		e.h.Cmp(f.h) == 0
+}
+
+// Curve returns the elliptic curve of the domain parameters.
+func (e *ECC) Curve() EllipticCurve {
+	return e.ec
+}
+
+// Generator returns the base point G.
+func (e *ECC) Generator() Point {
+	return e.g
+}
+
+// Order returns the order n of the base point.
+func (e *ECC) Order() *big.Int {
+	return new(big.Int).Set(e.n)
+}
+
+// Cofactor returns the cofactor h = #E(F_p) / n.
+func (e *ECC) Cofactor() *big.Int {
+	return new(big.Int).Set(e.h)
+}
+
+// IsInSubgroup reports whether p is a point of the curve that belongs to the
+// subgroup of order n generated by G. When the cofactor is 1 every point of
+// the curve does.
+func (e *ECC) IsInSubgroup(p Point) bool {
+	if !e.ec.IsOnCurve(p) {
+		return false
+	}
+
+	if e.h.Cmp(bi1) == 0 {
+		return true
+	}
+
+	return p.ScalarMul(e.n).IsInfinity()
+}
+
+// ClearCofactor maps p into the subgroup of order n by multiplying it by the
+// cofactor h.
+func (e *ECC) ClearCofactor(p Point) Point {
+	return p.ScalarMul(e.h)
+}
@@ -0,0 +1,200 @@
+package becc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestNewECC(t *testing.T) {
+	t.Run("secp256k1", func(t *testing.T) {
+		ec, g, n := Secp256k1()
+		ecc, err := NewECC(ec, g, n, Secp256k1H)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if ecc.Security() != 128 {
+			t.Errorf("security: got %d, expected 128", ecc.Security())
+		}
+
+		priv, pub, err := ecc.GenKeyPair()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		sig, err := priv.SignDeterministic(SHA256, []byte("custom curve"), true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !pub.Verify(SHA256, []byte("custom curve"), sig) {
+			t.Errorf("signature verification failed")
+		}
+	})
+
+	tt := []struct {
+		name     string
+		a, b, p  int64
+		gx, gy   int64
+		n, h     int64
+		bigOrder *big.Int
+	}{
+		// y^2 = x^3 + x + 7 (mod 853) has exactly 853 points
+		{name: "anomalous", a: 1, b: 7, p: 853, gx: 1, gy: 3, n: 853, h: 1},
+		// y^2 = x^3 + x (mod 103) is supersingular: embedding degree 2
+		{name: "supersingular", a: 1, b: 0, p: 103, gx: 49, gy: 81, n: 13, h: 8},
+		// y^2 = x^3 + x + 6 (mod 97) has 116 = 4 * 29 points
+		{name: "order not prime", a: 1, b: 6, p: 97, gx: 3, gy: 6, n: 58, h: 2},
+		{name: "wrong order", a: 1, b: 6, p: 97, gx: 3, gy: 6, n: 31, h: 4},
+		{name: "generator not in curve", a: 1, b: 6, p: 97, gx: 3, gy: 7, n: 29, h: 4},
+		{name: "outside hasse bound", a: 1, b: 6, p: 97, gx: 3, gy: 6, n: 29, h: 8},
+		{name: "modulus not prime", a: 1, b: 6, p: 91, gx: 3, gy: 6, n: 29, h: 4},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ec, err := NewEllipticCurve(big.NewInt(tc.a), big.NewInt(tc.b), big.NewInt(tc.p))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			g := ec.NewPoint(big.NewInt(tc.gx), big.NewInt(tc.gy))
+
+			_, err = NewECC(ec, g, big.NewInt(tc.n), big.NewInt(tc.h))
+			if !errors.Is(err, ErrInvalidDomainParameters) {
+				t.Errorf("got %v, expected ErrInvalidDomainParameters", err)
+			}
+		})
+	}
+}
+
+func TestEmbeddingDegree(t *testing.T) {
+	tt := []struct {
+		p, n     int64
+		expected int
+	}{
+		{p: 103, n: 13, expected: 2},
+		{p: 97, n: 29, expected: 28},
+		{p: 853, n: 853, expected: 0},
+	}
+
+	for _, tc := range tt {
+		got := embeddingDegree(big.NewInt(tc.p), big.NewInt(tc.n), movDegreeBound)
+		if got != tc.expected {
+			t.Errorf("p=%d, n=%d: got %d, expected %d", tc.p, tc.n, got, tc.expected)
+		}
+	}
+
+	if got := embeddingDegree(Secp256k1P, Secp256k1N, movDegreeBound); got != 0 {
+		t.Errorf("secp256k1: got %d, expected 0", got)
+	}
+}
+
+func TestCofactorOperations(t *testing.T) {
+	ecc := cofactorTestECC(t)
+	lowOrder := ecc.ec.NewPoint(big.NewInt(4361), big.NewInt(0))
+
+	t.Run("subgroup membership", func(t *testing.T) {
+		if !ecc.IsInSubgroup(ecc.g.ScalarMul(big.NewInt(1234))) {
+			t.Errorf("multiple of G not in subgroup")
+		}
+
+		if ecc.IsInSubgroup(lowOrder) {
+			t.Errorf("point of order 2 in subgroup")
+		}
+
+		if ecc.IsInSubgroup(ecc.g.Add(lowOrder)) {
+			t.Errorf("G + T in subgroup")
+		}
+	})
+
+	t.Run("clear cofactor", func(t *testing.T) {
+		if !ecc.ClearCofactor(lowOrder).IsInfinity() {
+			t.Errorf("cleared point of order 2 is not the point at infinity")
+		}
+
+		cleared := ecc.ClearCofactor(ecc.g.Add(lowOrder))
+		if !ecc.IsInSubgroup(cleared) {
+			t.Errorf("cleared point not in subgroup")
+		}
+
+		if !cleared.Eq(ecc.g.ScalarMul(big.NewInt(4))) {
+			t.Errorf("got %s, expected 4·G", cleared)
+		}
+	})
+
+	t.Run("cofactor ecdh", func(t *testing.T) {
+		if _, err := ecc.NewPublicKey(ecc.g.Add(lowOrder)); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+		}
+
+		for range 10 {
+			priv1, pub1, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			priv2, pub2, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			shared1 := priv1.ECDHPoint(pub2)
+			shared2 := priv2.ECDHPoint(pub1)
+			if !shared1.Eq(shared2) {
+				t.Fatalf("shared points differ: %s != %s", shared1, shared2)
+			}
+
+			// h·d1·d2·G
+			k := new(big.Int).Mul(priv1.Int(), priv2.Int())
+			k.Mul(k, ecc.h)
+			if expected := ecc.g.ScalarMul(k); !shared1.Eq(expected) {
+				t.Errorf("got %s, expected %s", shared1, expected)
+			}
+		}
+	})
+}
+
+func cofactorTestECC(t *testing.T) *ECC {
+	t.Helper()
+
+	// y^2 = x^3 + 2x + 20 (mod 10007) has 10084 = 4 * 2521 points
+	ec, err := NewEllipticCurve(big.NewInt(2), big.NewInt(20), big.NewInt(10007))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g := ec.NewPoint(big.NewInt(7345), big.NewInt(5479))
+	ecc, err := NewECC(ec, g, big.NewInt(2521), big.NewInt(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return ecc
+}
+
+func TestECCEqual(t *testing.T) {
+	p256 := mustLookupCurve("secp256r1")
+
+	custom, err := NewECC(p256.ec, p256.g, p256.n, p256.h)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !custom.Equal(p256) || !p256.Equal(mustLookupCurve("P-256")) {
+		t.Errorf("the same domain parameters are not equal")
+	}
+
+	otherGenerator, err := NewECC(p256.ec, p256.g.ScalarMul(big.NewInt(2)), p256.n, p256.h)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// two unnamed curves with different parameters
+	for _, other := range []*ECC{otherGenerator, cofactorTestECC(t), mustLookupCurve("secp256k1"), nil} {
+		if custom.Equal(other) {
+			t.Errorf("different domain parameters are equal: %v", other)
+		}
+	}
+}
@@ -2,42 +2,106 @@ package becc
 
 import (
 	"bytes"
+	"crypto/hkdf"
 	"crypto/hmac"
 	"crypto/rand"
-	"crypto/sha256"
+	"encoding/binary"
 	"errors"
-	"hash"
+	"fmt"
 	"math"
 	"math/big"
 	"slices"
 )
 
 type ECC struct {
+	name     string
 	ec       EllipticCurve
 	g        Point
 	n        *big.Int
+	h        *big.Int
 	security int
+
+	// seed is the seed the curve was generated from, if known
+	seed []byte
 }
 
-func (e *ECC) NewPrivateKey(d *big.Int) PrivateKey {
+var (
+	ErrInvalidPrivateKey error = errors.New("invalid private key")
+	ErrInvalidPublicKey  error = errors.New("invalid public key")
+)
+
+// NewPrivateKey returns the private key with scalar d, which must be in the
+// range [1, n-1]. d is copied, so later changes to it do not affect the key.
+func (e *ECC) NewPrivateKey(d *big.Int) (PrivateKey, error) {
+	if d.Sign() <= 0 || d.Cmp(e.n) >= 0 {
+		return PrivateKey{}, fmt.Errorf("%w: scalar out of range", ErrInvalidPrivateKey)
+	}
+
 	return PrivateKey{
-		d:   d,
+		d:   new(big.Int).Set(d),
 		ecc: e,
+	}, nil
+}
+
+// NewPrivateKeyBytes parses a private key encoded as a big-endian scalar of
+// exactly ScalarSize bytes, as returned by PrivateKey.Bytes.
+func (e *ECC) NewPrivateKeyBytes(b []byte) (PrivateKey, error) {
+	if len(b) != e.ScalarSize() {
+		return PrivateKey{}, fmt.Errorf("%w: invalid length", ErrInvalidPrivateKey)
 	}
+
+	return e.NewPrivateKey(new(big.Int).SetBytes(b))
 }
 
-func (e *ECC) NewPublicKey(p Point) PublicKey {
+// NewPublicKey returns the public key for p after validating it with
+// ValidatePublicKey.
+func (e *ECC) NewPublicKey(p Point) (PublicKey, error) {
+	if err := e.ValidatePublicKey(p); err != nil {
+		return PublicKey{}, err
+	}
+
 	return PublicKey{
-		p:   p,
+		p:   e.ec.NewPoint(p.x.n, p.y.n),
 		ecc: e,
+	}, nil
+}
+
+// NewPublicKeyXY returns the public key with affine coordinates (x, y).
+// The coordinates must be in the range [0, p-1].
+func (e *ECC) NewPublicKeyXY(x, y *big.Int) (PublicKey, error) {
+	if !e.inField(x) || !e.inField(y) {
+		return PublicKey{}, fmt.Errorf("%w: coordinate out of range", ErrInvalidPublicKey)
 	}
+
+	return e.NewPublicKey(e.ec.NewPoint(x, y))
 }
 
-func (e *ECC) NewPublicKeyXY(x, y *big.Int) PublicKey {
-	return PublicKey{
-		p:   e.ec.NewPoint(x, y),
-		ecc: e,
+// ValidatePublicKey performs the full public key validation routine of NIST
+// SP 800-56A (section 5.6.2.3.3): p must not be the point at infinity, its
+// coordinates must be field elements, it must lie on the curve and, when the
+// cofactor is greater than one, n·p must be the point at infinity.
+func (e *ECC) ValidatePublicKey(p Point) error {
+	if p.IsInfinity() {
+		return fmt.Errorf("%w: point at infinity", ErrInvalidPublicKey)
+	}
+
+	if !e.inField(p.x.n) || !e.inField(p.y.n) {
+		return fmt.Errorf("%w: coordinate out of range", ErrInvalidPublicKey)
 	}
+
+	if !e.ec.IsOnCurve(p) {
+		return fmt.Errorf("%w: point not in curve", ErrInvalidPublicKey)
+	}
+
+	if !e.IsInSubgroup(p) {
+		return fmt.Errorf("%w: point not in the subgroup of order n", ErrInvalidPublicKey)
+	}
+
+	return nil
+}
+
+func (e *ECC) inField(v *big.Int) bool {
+	return v.Sign() >= 0 && v.Cmp(e.ec.m) < 0
 }
 
 func (e *ECC) NewPublicKeyCompressed(c []byte) (PublicKey, error) {
@@ -49,15 +113,19 @@ func (e *ECC) NewPublicKeyCompressed(c []byte) (PublicKey, error) {
 		return PublicKey{}, errors.New("invalid key format: invalid header")
 	}
 
-	if len(c) != e.Security()/4+1 {
+	if len(c) != e.CoordinateSize()+1 {
 		return PublicKey{}, errors.New("invalid key format: invalid length")
 	}
 
 	x := new(big.Int).SetBytes(c[1:])
+	if !e.inField(x) {
+		return PublicKey{}, fmt.Errorf("%w: coordinate out of range", ErrInvalidPublicKey)
+	}
+
 	ys := e.ec.Y(x)
 
 	if len(ys) == 0 {
-		return PublicKey{}, errors.New("invalid key: point not in curve")
+		return PublicKey{}, fmt.Errorf("%w: point not in curve", ErrInvalidPublicKey)
 	}
 
 	evenY := c[0] == 2
@@ -66,25 +134,16 @@ func (e *ECC) NewPublicKeyCompressed(c []byte) (PublicKey, error) {
 			return PublicKey{}, errors.New("invalid y parity")
 		}
 
-		return PublicKey{
-			p:   e.ec.NewPoint(x, ys[0]),
-			ecc: e,
-		}, nil
+		return e.NewPublicKeyXY(x, ys[0])
 	}
 
 	parity := int(c[0] % 2)
 	ys0Parity := new(big.Int).Mod(ys[0], bi2).Sign()
 	if ys0Parity == parity {
-		return PublicKey{
-			p:   e.ec.NewPoint(x, ys[0]),
-			ecc: e,
-		}, nil
+		return e.NewPublicKeyXY(x, ys[0])
 	}
 
-	return PublicKey{
-		p:   e.ec.NewPoint(x, ys[1]),
-		ecc: e,
-	}, nil
+	return e.NewPublicKeyXY(x, ys[1])
 }
 
 func (e *ECC) NewPublicKeyUncompressed(c []byte) (PublicKey, error) {
@@ -96,20 +155,15 @@ func (e *ECC) NewPublicKeyUncompressed(c []byte) (PublicKey, error) {
 		return PublicKey{}, errors.New("invalid key format: invalid header")
 	}
 
-	if len(c) != e.Security()/2+1 {
+	coordLen := e.CoordinateSize()
+	if len(c) != 2*coordLen+1 {
 		return PublicKey{}, errors.New("invalid key format: invalid length")
 	}
 
-	coordLen := e.Security() / 4
 	x := new(big.Int).SetBytes(c[1 : coordLen+1])
 	y := new(big.Int).SetBytes(c[coordLen+1:])
 
-	p := e.ec.NewPoint(x, y)
-	if !e.ec.IsOnCurve(p) {
-		return PublicKey{}, errors.New("invalid key: point not in curve")
-	}
-
-	return e.NewPublicKeyXY(x, y), nil
+	return e.NewPublicKeyXY(x, y)
 }
 
 func (e *ECC) NewPublicKeyBytes(c []byte) (PublicKey, error) {
@@ -129,10 +183,12 @@ func (e *ECC) NewPublicKeyBytes(c []byte) (PublicKey, error) {
 }
 
 func (e *ECC) GenKeyPair() (PrivateKey, PublicKey, error) {
-	d, err := rand.Int(rand.Reader, e.n)
+	nSub1 := new(big.Int).Sub(e.n, bi1)
+	d, err := rand.Int(rand.Reader, nSub1)
 	if err != nil {
 		return PrivateKey{}, PublicKey{}, err
 	}
+	d.Add(d, bi1) // ensure d in [1, n-1]
 
 	priv := PrivateKey{
 		d:   d,
@@ -147,56 +203,110 @@ func (e *ECC) GenKeyPair() (PrivateKey, PublicKey, error) {
 	return priv, pub, nil
 }
 
+// Name returns the canonical name of the curve.
+func (e *ECC) Name() string {
+	return e.name
+}
+
 func (e *ECC) Security() int {
 	return e.security
 }
 
-type HashFunc = func() hash.Hash
+// Seed returns the seed the curve coefficients were derived from, or nil if
+// the curve was not generated verifiably at random.
+func (e *ECC) Seed() []byte {
+	return bytes.Clone(e.seed)
+}
 
-var SHA256 = sha256.New
+// CoordinateSize returns the length in bytes of an encoded field element,
+// derived from the size of the field prime (66 bytes for secp521r1).
+func (e *ECC) CoordinateSize() int {
+	return (e.ec.m.BitLen() + 7) / 8
+}
 
-func Secp256k1ECC() *ECC {
-	ec, g, n := Secp256k1()
+// ScalarSize returns the length in bytes of an encoded scalar modulo n.
+func (e *ECC) ScalarSize() int {
+	return (e.n.BitLen() + 7) / 8
+}
 
-	return &ECC{
-		ec:       ec,
-		g:        g,
-		n:        n,
-		security: 128,
+// KDF derives length bytes of key material from a shared secret and context
+// info.
+type KDF = func(secret, info []byte, length int) ([]byte, error)
+
+// HKDF returns a KDF that runs HKDF (RFC 5869) extract and expand with hf and
+// an empty salt.
+func HKDF(hf HashFunc) KDF {
+	return func(secret, info []byte, length int) ([]byte, error) {
+		return hkdf.Key(hf, secret, nil, string(info), length)
 	}
 }
 
-func Secp256r1ECC() *ECC {
-	ec, g, n := Secp256r1()
+// X963KDF returns the ANSI X9.63 KDF (SEC 1, section 3.6.1) built on hf.
+func X963KDF(hf HashFunc) KDF {
+	return func(secret, info []byte, length int) ([]byte, error) {
+		h := hf()
+		if uint64(length) >= uint64(h.Size())*(1<<32-1) {
+			return nil, errors.New("kdf: requested length too large")
+		}
 
-	return &ECC{
-		ec:       ec,
-		g:        g,
-		n:        n,
-		security: 128,
+		result := make([]byte, 0, length+h.Size())
+		counter := make([]byte, 4)
+		for i := uint32(1); len(result) < length; i++ {
+			binary.BigEndian.PutUint32(counter, i)
+
+			h.Reset()
+			h.Write(secret)
+			h.Write(counter)
+			h.Write(info)
+			result = h.Sum(result)
+		}
+
+		return result[:length], nil
 	}
 }
 
-func Secp384r1ECC() *ECC {
-	ec, g, n := Secp384r1()
+func Secp256k1ECC() *ECC {
+	return mustLookupCurve("secp256k1")
+}
 
-	return &ECC{
-		ec:       ec,
-		g:        g,
-		n:        n,
-		security: 192,
-	}
+func Secp256r1ECC() *ECC {
+	return mustLookupCurve("secp256r1")
+}
+
+func Secp384r1ECC() *ECC {
+	return mustLookupCurve("secp384r1")
 }
 
 func Secp521r1ECC() *ECC {
-	ec, g, n := Secp521r1()
+	return mustLookupCurve("secp521r1")
+}
 
-	return &ECC{
-		ec:       ec,
-		g:        g,
-		n:        n,
-		security: 256,
-	}
+func BrainpoolP256r1ECC() *ECC {
+	return mustLookupCurve("brainpoolP256r1")
+}
+
+func BrainpoolP256t1ECC() *ECC {
+	return mustLookupCurve("brainpoolP256t1")
+}
+
+func BrainpoolP384r1ECC() *ECC {
+	return mustLookupCurve("brainpoolP384r1")
+}
+
+func BrainpoolP384t1ECC() *ECC {
+	return mustLookupCurve("brainpoolP384t1")
+}
+
+func BrainpoolP512r1ECC() *ECC {
+	return mustLookupCurve("brainpoolP512r1")
+}
+
+func BrainpoolP512t1ECC() *ECC {
+	return mustLookupCurve("brainpoolP512t1")
+}
+
+func SM2ECC() *ECC {
+	return mustLookupCurve("sm2p256v1")
 }
 
 type PrivateKey struct {
@@ -208,13 +318,22 @@ func (priv PrivateKey) Int() *big.Int {
 	return new(big.Int).Set(priv.d)
 }
 
+// ECC returns the curve of the private key.
+func (priv PrivateKey) ECC() *ECC {
+	return priv.ecc
+}
+
+// Bytes returns the private scalar as a fixed-length big-endian byte string.
+func (priv PrivateKey) Bytes() []byte {
+	return priv.d.FillBytes(make([]byte, priv.ecc.ScalarSize()))
+}
+
 func (priv PrivateKey) Sign(hf HashFunc, message []byte, lowS bool) (Signature, error) {
 	h := hf()
 	h.Write(message)
 	hash := h.Sum(nil)
 
-	z := new(big.Int).SetBytes(hash[:])
-	z.Mod(z, priv.ecc.n)
+	z := priv.ecc.hashToInt(hash)
 
 	nSub1 := new(big.Int).Sub(priv.ecc.n, bi1)
 	nHalf := new(big.Int).Div(priv.ecc.n, bi2)
@@ -259,112 +378,145 @@ func (priv PrivateKey) Sign(hf HashFunc, message []byte, lowS bool) (Signature,
 }
 
 func (priv PrivateKey) SignDeterministic(hf HashFunc, message []byte, lowS bool) (Signature, error) {
+	sig, _, err := priv.signDeterministic(hf, hf, message, lowS)
+
+	return sig, err
+}
+
+// signDeterministic returns the RFC 6979 signature of the message hashed
+// with hf, with the nonces generated by HMAC with nonceHF, and its recovery
+// id, as described in SignRecoverable.
+func (priv PrivateKey) signDeterministic(hf, nonceHF HashFunc, message []byte, lowS bool) (Signature, byte, error) {
 	h := hf()
 	h.Write(message)
 	hash := h.Sum(nil)
 
-	z := new(big.Int).SetBytes(hash[:])
-	z.Mod(z, priv.ecc.n)
-
-	hlen := h.Size()
-	qlen := priv.ecc.n.BitLen()
-	rlen := int(math.Ceil(float64(qlen) / 8.0))
-
-	dBytes := priv.d.Bytes()
-	// pad to rlen
-	if len(dBytes) < rlen {
-		dBytes = slices.Concat(bytes.Repeat([]byte{0}, rlen-len(dBytes)), dBytes)
-	}
-
-	hInt := new(big.Int).SetBytes(hash)
-	hInt.Mod(hInt, priv.ecc.n)
-
-	mBytes := hInt.Bytes()
-	// pad to rlen
-	if len(mBytes) < rlen {
-		mBytes = slices.Concat(bytes.Repeat([]byte{0}, rlen-len(mBytes)), mBytes)
-	}
-
-	V := bytes.Repeat([]byte{0x01}, hlen)
-	K := bytes.Repeat([]byte{0x00}, hlen)
-
-	hm := hmac.New(hf, K)
-	hm.Write(slices.Concat(V, []byte{0x00}, dBytes, mBytes))
-	K = hm.Sum(nil)
-
-	hm = hmac.New(hf, K)
-	hm.Write(V)
-	V = hm.Sum(nil)
-
-	hm.Reset()
-	hm.Write(slices.Concat(V, []byte{0x01}, dBytes, mBytes))
-	K = hm.Sum(nil)
-
-	hm = hmac.New(hf, K)
-	hm.Write(V)
-	V = hm.Sum(nil)
-
-	var rx, s *big.Int
-	var r Point
-	var T []byte
+	z := priv.ecc.hashToInt(hash)
 
+	nonces := newRFC6979(nonceHF, priv.d, priv.ecc.n, hash)
 	nHalf := new(big.Int).Div(priv.ecc.n, bi2)
 
 	for {
-		T = []byte{}
-
-		for len(T) < rlen {
-			hm.Reset()
-			hm.Write(V)
-			V = hm.Sum(nil)
-			T = slices.Concat(T, V)
-		}
-		k := new(big.Int).SetBytes(T[:rlen])
-
+		k := nonces.next()
 		if k.Cmp(bi1) <= 0 || k.Cmp(priv.ecc.n) >= 0 {
-			goto retry
+			continue
 		}
 
-		r = priv.ecc.g.ScalarMul(k)
+		r := priv.ecc.g.ScalarMul(k)
 		if r.IsInfinity() {
-			goto retry
+			continue
 		}
 
-		rx = new(big.Int).Mod(r.x.n, priv.ecc.n)
+		rx := new(big.Int).Mod(r.x.n, priv.ecc.n)
 		if rx.Sign() == 0 {
-			goto retry
+			continue
 		}
 
-		s = new(big.Int).Mul(
+		recoveryID := byte(r.y.n.Bit(0))
+		if r.x.n.Cmp(priv.ecc.n) >= 0 {
+			recoveryID |= 2
+		}
+
+		s := new(big.Int).Mul(
 			modInverse(k, priv.ecc.n),
 			new(big.Int).Add(z, new(big.Int).Mul(rx, priv.d)),
 		)
 		s.Mod(s, priv.ecc.n)
 		if s.Sign() == 0 {
-			goto retry
+			continue
 		}
 
-		// low-s normalization
+		// low-s normalization, which negates R
 		if lowS && s.Cmp(nHalf) > 0 {
 			s.Sub(priv.ecc.n, s)
+			recoveryID ^= 1
 		}
 
 		return Signature{
 			r: rx,
 			s: s,
-		}, nil
+		}, recoveryID, nil
+	}
+}
+
+// rfc6979 generates the candidate nonces of RFC 6979, section 3.2, for the
+// private key d and the message hash. The caller checks each candidate and
+// asks for the next one if it is not suitable.
+type rfc6979 struct {
+	hf      HashFunc
+	k, v    []byte
+	qlen    int
+	rlen    int
+	started bool
+}
+
+func newRFC6979(hf HashFunc, d, n *big.Int, hash []byte) *rfc6979 {
+	hlen := hf().Size()
+	qlen := n.BitLen()
+	rlen := int(math.Ceil(float64(qlen) / 8.0))
+
+	dBytes := d.Bytes()
+	// pad to rlen
+	if len(dBytes) < rlen {
+		dBytes = slices.Concat(bytes.Repeat([]byte{0}, rlen-len(dBytes)), dBytes)
+	}
 
-	retry:
-		hm.Reset()
-		hm.Write(slices.Concat(V, []byte{0x00}))
-		K = hm.Sum(nil)
-		hm = hmac.New(hf, K)
-		hm.Write(V)
-		V = hm.Sum(nil)
-		continue
+	// bits2octets(hash) = int2octets(bits2int(hash) mod n)
+	hInt := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - qlen; excess > 0 {
+		hInt.Rsh(hInt, uint(excess))
 	}
+	hInt.Mod(hInt, n)
 
-	return Signature{}, nil
+	mBytes := hInt.Bytes()
+	// pad to rlen
+	if len(mBytes) < rlen {
+		mBytes = slices.Concat(bytes.Repeat([]byte{0}, rlen-len(mBytes)), mBytes)
+	}
+
+	r := &rfc6979{
+		hf:   hf,
+		v:    bytes.Repeat([]byte{0x01}, hlen),
+		k:    bytes.Repeat([]byte{0x00}, hlen),
+		qlen: qlen,
+		rlen: rlen,
+	}
+
+	r.k = r.mac(r.k, r.v, []byte{0x00}, dBytes, mBytes)
+	r.v = r.mac(r.k, r.v)
+	r.k = r.mac(r.k, r.v, []byte{0x01}, dBytes, mBytes)
+	r.v = r.mac(r.k, r.v)
+
+	return r
+}
+
+// next returns the next candidate nonce.
+func (r *rfc6979) next() *big.Int {
+	if r.started {
+		r.k = r.mac(r.k, r.v, []byte{0x00})
+		r.v = r.mac(r.k, r.v)
+	}
+	r.started = true
+
+	var t []byte
+	for len(t) < r.rlen {
+		r.v = r.mac(r.k, r.v)
+		t = slices.Concat(t, r.v)
+	}
+
+	// bits2int(T): the leftmost qlen bits
+	k := new(big.Int).SetBytes(t[:r.rlen])
+
+	return k.Rsh(k, uint(r.rlen*8-r.qlen))
+}
+
+func (r *rfc6979) mac(key []byte, data ...[]byte) []byte {
+	hm := hmac.New(r.hf, key)
+	for _, d := range data {
+		hm.Write(d)
+	}
+
+	return hm.Sum(nil)
 }
 
 func (priv PrivateKey) PublicKey() PublicKey {
@@ -376,12 +528,39 @@ func (priv PrivateKey) PublicKey() PublicKey {
 	}
 }
 
+// ECDH returns the SEC1 compressed encoding of the shared point.
 func (priv PrivateKey) ECDH(pub2 PublicKey) []byte {
-	sharedPoint := pub2.p.ScalarMul(priv.d)
-	sharedKey := priv.ecc.NewPublicKey(sharedPoint)
+	sharedKey := PublicKey{
+		p:   priv.ECDHPoint(pub2),
+		ecc: priv.ecc,
+	}
+
 	return sharedKey.Compressed()
 }
 
+// ECDHPoint returns the full shared point h·d·Q computed with the cofactor
+// Diffie-Hellman primitive of NIST SP 800-56A (section 5.7.1.2). For curves
+// with cofactor 1 this is the usual d·Q.
+func (priv PrivateKey) ECDHPoint(pub2 PublicKey) Point {
+	hd := new(big.Int).Mul(priv.ecc.h, priv.d)
+	return pub2.p.ScalarMul(hd)
+}
+
+// ECDHX returns the x-coordinate of the shared point as a fixed-length
+// big-endian byte string. This is the shared secret Z of NIST SP 800-56A and
+// the value used by TLS and JOSE ECDH-ES.
+func (priv PrivateKey) ECDHX(pub2 PublicKey) []byte {
+	sharedPoint := priv.ECDHPoint(pub2)
+
+	return sharedPoint.x.n.FillBytes(make([]byte, priv.ecc.CoordinateSize()))
+}
+
+// ECDHKDF derives length bytes of key material from the x-coordinate of the
+// shared point using kdf and the context info.
+func (priv PrivateKey) ECDHKDF(pub2 PublicKey, kdf KDF, info []byte, length int) ([]byte, error) {
+	return kdf(priv.ECDHX(pub2), info, length)
+}
+
 type PublicKey struct {
 	p   Point
 	ecc *ECC
@@ -397,8 +576,7 @@ func (pub PublicKey) Verify(hf HashFunc, message []byte, sig Signature) bool {
 	h.Write(message)
 	hash := h.Sum(nil)
 
-	z := new(big.Int).SetBytes(hash[:])
-	z.Mod(z, pub.ecc.n)
+	z := pub.ecc.hashToInt(hash)
 
 	w := modInverse(sig.s, pub.ecc.n)
 	u1 := new(big.Int).Mul(z, w)
@@ -420,15 +598,31 @@ func (pub PublicKey) Y() *big.Int {
 	return pub.p.Y()
 }
 
+// Point returns the curve point of the public key.
+func (pub PublicKey) Point() Point {
+	return pub.p
+}
+
+// ECC returns the curve of the public key.
+func (pub PublicKey) ECC() *ECC {
+	return pub.ecc
+}
+
+func (pub PublicKey) Uncompressed() []byte {
+	coordLen := pub.ecc.CoordinateSize()
+	xs := pub.p.x.n.FillBytes(make([]byte, coordLen))
+	ys := pub.p.y.n.FillBytes(make([]byte, coordLen))
+
+	return slices.Concat([]byte{4}, xs, ys)
+}
+
 func (pub PublicKey) Compressed() []byte {
 	yParity := new(big.Int).Mod(pub.p.y.n, bi2).Sign()
 
-	bs := pub.p.x.n.Bytes()
-	paddedLen := pub.ecc.Security() / 4
-	padding := bytes.Repeat([]byte{0x00}, paddedLen-len(bs))
+	xs := pub.p.x.n.FillBytes(make([]byte, pub.ecc.CoordinateSize()))
 	header := []byte{byte(2 + yParity)}
 
-	return slices.Concat(header, padding, bs)
+	return slices.Concat(header, xs)
 }
 
 type Signature struct {
@@ -1,6 +1,11 @@
 package becc
 
 import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 )
@@ -146,7 +151,10 @@ func TestSecp256k1ECDSAVerify(t *testing.T) {
 			r, _ := new(big.Int).SetString(tc.r, 16)
 			s, _ := new(big.Int).SetString(tc.s, 16)
 
-			privKey := ecc.NewPrivateKey(key)
+			privKey, err := ecc.NewPrivateKey(key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			pubKey := privKey.PublicKey()
 
 			sig := NewSignature(r, s)
@@ -303,7 +311,10 @@ func TestSecp256k1ECDSASignDeterministic(t *testing.T) {
 			expectedR, _ := new(big.Int).SetString(tc.r, 16)
 			expectedS, _ := new(big.Int).SetString(tc.s, 16)
 
-			privKey := ecc.NewPrivateKey(key)
+			privKey, err := ecc.NewPrivateKey(key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 
 			sig, err := privKey.SignDeterministic(SHA256, msg, true)
 			if err != nil {
@@ -327,29 +338,38 @@ func TestSecp256k1ECDH(t *testing.T) {
 	ecc := Secp256k1ECC()
 
 	tt := []struct {
-		priv     PrivateKey
-		pub2     PublicKey
+		priv     string
+		pub2X    string
+		pub2Y    string
 		expected string
 	}{
 		{
-			priv: ecc.NewPrivateKey(bigIntHex(t, "3ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba817")),
-			pub2: ecc.NewPublicKeyXY(
-				bigIntHex(t, "8041e097f009aaca2922ab41e47271aa867890a697c987186ca9d4b2cd49efcd"),
-				bigIntHex(t, "e05363a55e1739d6afd9018cb3e00ca83020afc2a4163d08af84e6f01ec8d60f")),
+			priv:     "3ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba817",
+			pub2X:    "8041e097f009aaca2922ab41e47271aa867890a697c987186ca9d4b2cd49efcd",
+			pub2Y:    "e05363a55e1739d6afd9018cb3e00ca83020afc2a4163d08af84e6f01ec8d60f",
 			expected: "036c1a667578265442782516859a762f733022a2af7283da3d95202c7ee0b7a736",
 		},
 		{
-			priv: ecc.NewPrivateKey(bigIntHex(t, "3ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba817")),
-			pub2: ecc.NewPublicKeyXY(
-				bigIntHex(t, "86da8b28a6b399063ebc373e2f657e8c53d3862953acbe339ce23f6d066ca8b8"),
-				bigIntHex(t, "bca9f27d41addfe81f0834a2d05aaf7a81910a982da2d54e63c46e2e04685579")),
+			priv:     "3ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba817",
+			pub2X:    "86da8b28a6b399063ebc373e2f657e8c53d3862953acbe339ce23f6d066ca8b8",
+			pub2Y:    "bca9f27d41addfe81f0834a2d05aaf7a81910a982da2d54e63c46e2e04685579",
 			expected: "0282c2795a1c400537c796daa7e74b9f23e14cb4f74a8f4364556cf6a74467d24d",
 		},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.expected, func(t *testing.T) {
-			secret := tc.priv.ECDH(tc.pub2)
+			priv, err := ecc.NewPrivateKey(bigIntHex(t, tc.priv))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			pub2, err := ecc.NewPublicKeyXY(bigIntHex(t, tc.pub2X), bigIntHex(t, tc.pub2Y))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			secret := priv.ECDH(pub2)
 			got := new(big.Int).SetBytes(secret)
 
 			if bigIntHex(t, tc.expected).Cmp(got) != 0 {
@@ -359,6 +379,265 @@ func TestSecp256k1ECDH(t *testing.T) {
 	}
 }
 
+func TestValidatePublicKey(t *testing.T) {
+	ecc := Secp256k1ECC()
+	gx, gy := ecc.g.X(), ecc.g.Y()
+
+	tt := []struct {
+		name  string
+		x, y  *big.Int
+		valid bool
+	}{
+		{name: "generator", x: gx, y: gy, valid: true},
+		{name: "not in curve", x: gx, y: new(big.Int).Add(gy, bi1), valid: false},
+		{name: "x out of range", x: new(big.Int).Add(gx, Secp256k1P), y: gy, valid: false},
+		{name: "y out of range", x: gx, y: new(big.Int).Add(gy, Secp256k1P), valid: false},
+		{name: "negative coordinate", x: new(big.Int).Neg(gx), y: gy, valid: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ecc.NewPublicKeyXY(tc.x, tc.y)
+			if tc.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if !tc.valid && !errors.Is(err, ErrInvalidPublicKey) {
+				t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+			}
+		})
+	}
+
+	t.Run("infinity", func(t *testing.T) {
+		_, err := ecc.NewPublicKey(ecc.ec.Infinity())
+		if !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+		}
+	})
+
+	t.Run("compressed x out of range", func(t *testing.T) {
+		c := append([]byte{0x02}, bytes.Repeat([]byte{0xff}, 32)...)
+		_, err := ecc.NewPublicKeyCompressed(c)
+		if !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+		}
+	})
+
+	t.Run("uncompressed not in curve", func(t *testing.T) {
+		c := append([]byte{0x04}, bytes.Repeat([]byte{0x01}, 64)...)
+		_, err := ecc.NewPublicKeyUncompressed(c)
+		if !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+		}
+	})
+}
+
+func TestValidatePublicKeyCofactor(t *testing.T) {
+	// y^2 = x^3 + x + 6 (mod 97) has 116 = 4 * 29 points
+	ec, err := NewEllipticCurve(big.NewInt(1), big.NewInt(6), big.NewInt(97))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ecc := &ECC{
+		ec:       ec,
+		g:        ec.NewPoint(big.NewInt(3), big.NewInt(6)),
+		n:        big.NewInt(29),
+		h:        big.NewInt(4),
+		security: 2,
+	}
+
+	if err := ecc.ValidatePublicKey(ecc.g.ScalarMul(big.NewInt(5))); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// (12, 0) has order 2
+	lowOrder := ec.NewPoint(big.NewInt(12), big.NewInt(0))
+	if !ec.IsOnCurve(lowOrder) {
+		t.Fatalf("test point not in curve")
+	}
+
+	if err := ecc.ValidatePublicKey(lowOrder); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+	}
+
+	if err := ecc.ValidatePublicKey(lowOrder.Add(ecc.g)); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+	}
+}
+
+func TestNewPrivateKey(t *testing.T) {
+	ecc := Secp256k1ECC()
+
+	tt := []struct {
+		name  string
+		d     *big.Int
+		valid bool
+	}{
+		{name: "one", d: big.NewInt(1), valid: true},
+		{name: "n-1", d: new(big.Int).Sub(Secp256k1N, bi1), valid: true},
+		{name: "zero", d: big.NewInt(0), valid: false},
+		{name: "negative", d: big.NewInt(-5), valid: false},
+		{name: "n", d: new(big.Int).Set(Secp256k1N), valid: false},
+		{name: "n+1", d: new(big.Int).Add(Secp256k1N, bi1), valid: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ecc.NewPrivateKey(tc.d)
+			if tc.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if !tc.valid && !errors.Is(err, ErrInvalidPrivateKey) {
+				t.Errorf("got %v, expected ErrInvalidPrivateKey", err)
+			}
+		})
+	}
+
+	t.Run("copies input", func(t *testing.T) {
+		d := big.NewInt(12345)
+		priv, err := ecc.NewPrivateKey(d)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		d.SetInt64(54321)
+		if priv.Int().Cmp(big.NewInt(12345)) != 0 {
+			t.Errorf("private key changed after mutating its input: %d", priv.Int())
+		}
+	})
+}
+
+func TestNewPrivateKeyBytes(t *testing.T) {
+	ecc := Secp256k1ECC()
+
+	tt := []struct {
+		name  string
+		key   string
+		valid bool
+	}{
+		{name: "valid", key: "3ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba817", valid: true},
+		{name: "leading zeros", key: "0000000000000000000000000000000000000000000000000000000000000001", valid: true},
+		{name: "short", key: "3ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba8", valid: false},
+		{name: "long", key: "003ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba817", valid: false},
+		{name: "zero", key: "0000000000000000000000000000000000000000000000000000000000000000", valid: false},
+		{name: "n", key: "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", valid: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			priv, err := ecc.NewPrivateKeyBytes(hexBytes(t, tc.key))
+			if !tc.valid {
+				if !errors.Is(err, ErrInvalidPrivateKey) {
+					t.Errorf("got %v, expected ErrInvalidPrivateKey", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := hex.EncodeToString(priv.Bytes()); got != tc.key {
+				t.Errorf("got %s, expected %s", got, tc.key)
+			}
+		})
+	}
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	tt := []struct {
+		name      string
+		ecc       *ECC
+		coordLen  int
+		scalarLen int
+	}{
+		{name: "secp256k1", ecc: Secp256k1ECC(), coordLen: 32, scalarLen: 32},
+		{name: "secp256r1", ecc: Secp256r1ECC(), coordLen: 32, scalarLen: 32},
+		{name: "secp384r1", ecc: Secp384r1ECC(), coordLen: 48, scalarLen: 48},
+		{name: "secp521r1", ecc: Secp521r1ECC(), coordLen: 66, scalarLen: 66},
+	}
+
+	msg := []byte("round trip")
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.ecc.CoordinateSize(); got != tc.coordLen {
+				t.Errorf("coordinate size: got %d, expected %d", got, tc.coordLen)
+			}
+
+			if got := tc.ecc.ScalarSize(); got != tc.scalarLen {
+				t.Errorf("scalar size: got %d, expected %d", got, tc.scalarLen)
+			}
+
+			for range 3 {
+				priv, pub, err := tc.ecc.GenKeyPair()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				privBytes := priv.Bytes()
+				if len(privBytes) != tc.scalarLen {
+					t.Errorf("private key: got %d bytes, expected %d", len(privBytes), tc.scalarLen)
+				}
+
+				priv2, err := tc.ecc.NewPrivateKeyBytes(privBytes)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if priv2.Int().Cmp(priv.Int()) != 0 {
+					t.Errorf("private key: got %x, expected %x", priv2.Int(), priv.Int())
+				}
+
+				compressed := pub.Compressed()
+				if len(compressed) != tc.coordLen+1 {
+					t.Errorf("compressed: got %d bytes, expected %d", len(compressed), tc.coordLen+1)
+				}
+
+				uncompressed := pub.Uncompressed()
+				if len(uncompressed) != 2*tc.coordLen+1 {
+					t.Errorf("uncompressed: got %d bytes, expected %d", len(uncompressed), 2*tc.coordLen+1)
+				}
+
+				for _, encoded := range [][]byte{compressed, uncompressed} {
+					pub2, err := tc.ecc.NewPublicKeyBytes(encoded)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+
+					if pub2.X().Cmp(pub.X()) != 0 || pub2.Y().Cmp(pub.Y()) != 0 {
+						t.Errorf("public key: got %x, expected %x", pub2.Uncompressed(), uncompressed)
+					}
+				}
+
+				if got := len(priv.ECDHX(pub)); got != tc.coordLen {
+					t.Errorf("ecdh x: got %d bytes, expected %d", got, tc.coordLen)
+				}
+
+				sig, err := priv.SignDeterministic(SHA256, msg, true)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !pub.Verify(SHA256, msg, sig) {
+					t.Errorf("deterministic signature verification failed")
+				}
+
+				sig, err = priv.Sign(SHA256, msg, true)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !pub.Verify(SHA256, msg, sig) {
+					t.Errorf("signature verification failed")
+				}
+			}
+		})
+	}
+}
+
 func bigIntHex(t *testing.T, s string) *big.Int {
 	t.Helper()
 
@@ -369,3 +648,156 @@ func bigIntHex(t *testing.T, s string) *big.Int {
 
 	return i
 }
+
+func TestECDHVariants(t *testing.T) {
+	tt := []struct {
+		name     string
+		ecc      *ECC
+		priv     string
+		pub2     string
+		expected string
+	}{
+		{
+			name:     "secp256k1",
+			ecc:      Secp256k1ECC(),
+			priv:     "3ce3262f2fba436f7cc4ed0914a6471a2a73fb1accc5f2852951a483efeba817",
+			pub2:     "048041e097f009aaca2922ab41e47271aa867890a697c987186ca9d4b2cd49efcde05363a55e1739d6afd9018cb3e00ca83020afc2a4163d08af84e6f01ec8d60f",
+			expected: "6c1a667578265442782516859a762f733022a2af7283da3d95202c7ee0b7a736",
+		},
+		{
+			name:     "secp256r1",
+			ecc:      Secp256r1ECC(),
+			priv:     "07fadf0b5ef4654bd91c8e76eabd0b1a580f3f6bc787c8c153c860274cdffbf7",
+			pub2:     "04384589dcd0c66464f7aec82445e3c15d3feaf52838b9e82287e3be8013b27add0fe66502ec339d25702503683fd42e188d5fec8407a15e45403051055e296764",
+			expected: "38ec01fc5aed6d106d61721ecb1780610101325fa52dc864e9d74349f40184a9",
+		},
+		{
+			name:     "secp521r1",
+			ecc:      Secp521r1ECC(),
+			priv:     "002c0b807aee7bc403f995e679361b3c1ec0b60355fb67ebdddc1a633eeaabd43b703cf10ab7d72377f33bff2a98ca8c33cd6970a5fb1334797cee01e08c8918d4b1",
+			pub2:     "0401059150eb3ff54169e0e683f7efa57fbd3bb61430c72f2d1262d03a949834a9f7eec046971132d966b59e458c6ba500ec8c600bf775f8831af68791ff4659192039018b8c5485a6153dbdc22b285c2f93bb67aea760aba4270062d5ee83574f2f06fc919ccf279a1ee3dc2a4d8fee94e217671be6532c76b25a14b6ef07c3cedc207610",
+			expected: "019990c07f6459c03970540a9f5c84dbe0dfe710312c2459e1e6eb9177a4e69905c85148fec87410afc63236056c277b4154357193ce834183bc4c8a46798eab9a00",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			priv, err := tc.ecc.NewPrivateKeyBytes(hexBytes(t, tc.priv))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			pub2, err := tc.ecc.NewPublicKeyBytes(hexBytes(t, tc.pub2))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			x := priv.ECDHX(pub2)
+			if hex.EncodeToString(x) != tc.expected {
+				t.Errorf("x: got %x, expected %s", x, tc.expected)
+			}
+
+			compressed := priv.ECDH(pub2)
+			if !bytes.Equal(compressed[1:], x) {
+				t.Errorf("compressed: got %x, expected x-coordinate %x", compressed, x)
+			}
+
+			point := priv.ECDHPoint(pub2)
+			if point.X().Cmp(new(big.Int).SetBytes(x)) != 0 {
+				t.Errorf("point: got %s, expected x-coordinate %x", point, x)
+			}
+
+			info := []byte("becc test")
+			key, err := priv.ECDHKDF(pub2, HKDF(SHA256), info, 42)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expectedKey, err := hkdf.Key(sha256.New, x, nil, string(info), 42)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(key, expectedKey) {
+				t.Errorf("hkdf: got %x, expected %x", key, expectedKey)
+			}
+		})
+	}
+}
+
+func TestECDHKDFAgreement(t *testing.T) {
+	ecc := Secp256r1ECC()
+
+	kdfs := map[string]KDF{
+		"hkdf": HKDF(SHA256),
+		"x963": X963KDF(SHA256),
+	}
+
+	for name, kdf := range kdfs {
+		t.Run(name, func(t *testing.T) {
+			priv1, pub1, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			priv2, pub2, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			key1, err := priv1.ECDHKDF(pub2, kdf, []byte("info"), 80)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			key2, err := priv2.ECDHKDF(pub1, kdf, []byte("info"), 80)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(key1) != 80 {
+				t.Errorf("got %d bytes, expected 80", len(key1))
+			}
+
+			if !bytes.Equal(key1, key2) {
+				t.Errorf("keys differ: %x != %x", key1, key2)
+			}
+
+			key3, err := priv1.ECDHKDF(pub2, kdf, []byte("other info"), 80)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if bytes.Equal(key1, key3) {
+				t.Errorf("keys derived with different info are equal")
+			}
+		})
+	}
+}
+
+func TestX963KDF(t *testing.T) {
+	// NIST CAVS ANSI X9.63 KDF vector with SHA-256
+	secret := hexBytes(t, "96c05619d56c328ab95fe84b18264b08725b85e33fd34f08")
+	info := []byte{}
+	expected := "443024c3dae66b95e6f5670601558f71"
+
+	got, err := X963KDF(SHA256)(secret, info, 16)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hex.EncodeToString(got) != expected {
+		t.Errorf("got %x, expected %s", got, expected)
+	}
+}
+
+func hexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+
+	bs, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex string")
+	}
+
+	return bs
+}
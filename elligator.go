@@ -0,0 +1,344 @@
+package becc
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// elligatorSwiftC is sqrt(-3) mod p for secp256k1, the constant c of BIP-324.
+var elligatorSwiftC, _ = new(big.Int).SetString("0a2d2ba93507f1df233770c2a797962cc61f6d15da14ecd47d8d27ae1cd5f852", 16)
+
+// Elligator returns an encoding of the public key that is indistinguishable
+// from uniform random bytes, for protocols that must not reveal that they
+// exchange elliptic curve points. Every call returns a different encoding.
+//
+// secp256k1 uses the 64-byte ElligatorSwift encoding of BIP-324, which only
+// keeps the x-coordinate: NewPublicKeyElligator returns the point with that
+// x and an even y, so the encoding is meant for x-only ECDH (ECDHX).
+// secp256r1, secp384r1 and secp521r1 use Elligator Squared (Tibouchi, 2014)
+// over the simplified SWU map of their RFC 9380 suite, which keeps the whole
+// point: the encoding is a pair of field elements u1, u2 with
+// map(u1) + map(u2) = P, each written as a random integer congruent to it in
+// ElligatorSize()/2 bytes so that the bytes are uniform as well.
+func (pub PublicKey) Elligator() ([]byte, error) {
+	e := pub.ecc
+
+	suite, err := e.hashToCurveSuite()
+	if err != nil {
+		return nil, err
+	}
+
+	if suite.iso != nil {
+		return e.ec.elligatorSwift(pub.p.x.n)
+	}
+
+	u1, u2, err := e.elligatorSquared(suite, pub.p)
+	if err != nil {
+		return nil, err
+	}
+
+	size := e.ElligatorSize() / 2
+	result := make([]byte, 2*size)
+	for i, u := range []*big.Int{u1, u2} {
+		r, err := randomRepresentative(u, e.ec.m, size)
+		if err != nil {
+			return nil, err
+		}
+
+		r.FillBytes(result[i*size : (i+1)*size])
+	}
+
+	return result, nil
+}
+
+// NewPublicKeyElligator decodes a public key encoded by PublicKey.Elligator.
+// Every string of ElligatorSize bytes decodes to a point, which is then
+// validated with ValidatePublicKey.
+func (e *ECC) NewPublicKeyElligator(b []byte) (PublicKey, error) {
+	suite, err := e.hashToCurveSuite()
+	if err != nil {
+		return PublicKey{}, err
+	}
+
+	size := e.ElligatorSize()
+	if len(b) != size {
+		return PublicKey{}, fmt.Errorf("%w: invalid Elligator encoding length", ErrInvalidPublicKey)
+	}
+
+	u1 := new(big.Int).SetBytes(b[:size/2])
+	u2 := new(big.Int).SetBytes(b[size/2:])
+	u1.Mod(u1, e.ec.m)
+	u2.Mod(u2, e.ec.m)
+
+	if suite.iso != nil {
+		x := e.ec.xSwiftEC(u1, u2)
+		ys := e.ec.Y(x)
+		y := ys[0]
+		if y.Bit(0) == 1 {
+			y = ys[len(ys)-1]
+		}
+
+		return e.NewPublicKey(e.ec.NewPoint(x, y))
+	}
+
+	return e.NewPublicKey(e.mapToCurve(suite, u1).Add(e.mapToCurve(suite, u2)))
+}
+
+// ElligatorSize returns the length of the encoding of PublicKey.Elligator, or
+// 0 if the curve does not have one.
+func (e *ECC) ElligatorSize() int {
+	suite, err := e.hashToCurveSuite()
+	if err != nil {
+		return 0
+	}
+
+	// secp256k1, the curve with a = 0 whose suite maps through an isogeny,
+	// uses ElligatorSwift
+	if suite.iso != nil {
+		return 2 * e.CoordinateSize()
+	}
+
+	// the byte length that hash_to_field reduces, which leaves a bias of at
+	// most 2^-k
+	return 2 * ((e.ec.m.BitLen() + suite.k + 7) / 8)
+}
+
+// elligatorSquared returns a random pair (u1, u2) with map(u1) + map(u2) = p,
+// chosen uniformly among all such pairs: u1 is random and u2 is one of the
+// up to four preimages of p - map(u1), each picked with probability 1/4, so
+// pairs for points with fewer preimages are rejected more often.
+func (e *ECC) elligatorSquared(suite *hashToCurveSuite, p Point) (*big.Int, *big.Int, error) {
+	for {
+		u1, err := rand.Int(rand.Reader, e.ec.m)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		q := p.Add(e.mapToCurve(suite, u1).Neg())
+		if q.IsInfinity() {
+			continue
+		}
+
+		j, err := rand.Int(rand.Reader, big.NewInt(4))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		preimages := e.swuPreimages(suite, q)
+		if j.Int64() < int64(len(preimages)) {
+			return u1, preimages[j.Int64()], nil
+		}
+	}
+}
+
+// swuPreimages returns the field elements u that the simplified SWU map of
+// the suite maps to q.
+//
+// With w = z·u^2 and k = -a·x/b, the map returns x = x1 when
+// w^2 + w = 1/(k - 1), and x = w·x1 when w^2 + (1 - k)w + 1 - k = 0. Each root
+// w gives the u = ±sqrt(w/z) whose sign matches the one of y, and the
+// candidates that the map does not send to q are dropped.
+func (e *ECC) swuPreimages(suite *hashToCurveSuite, q Point) []*big.Int {
+	p := e.ec.m
+	fe := func(n *big.Int) *FieldElement { return NewFieldElement(n, p) }
+
+	one := fe(bi1)
+	half := fe(big.NewInt(2)).ModInverse()
+	zInv := fe(big.NewInt(suite.z)).ModInverse()
+	k := fe(suite.a).Mul(q.x).Mul(fe(suite.b).ModInverse()).Neg()
+
+	// roots of w^2 + βw + γ
+	var ws []*FieldElement
+	roots := func(beta, gamma *FieldElement) {
+		d := new(big.Int).ModSqrt(beta.Mul(beta).Sub(gamma.MulInt(4)).n, p)
+		if d == nil {
+			return
+		}
+
+		sqrtD := fe(d)
+		ws = append(ws, beta.Neg().Add(sqrtD).Mul(half), beta.Neg().Sub(sqrtD).Mul(half))
+	}
+
+	if kMinus1 := k.Sub(one); !kMinus1.IsZero() {
+		roots(one, kMinus1.ModInverse().Neg())
+	}
+	roots(one.Sub(k), one.Sub(k))
+
+	var preimages []*big.Int
+	for _, w := range ws {
+		u := new(big.Int).ModSqrt(w.Mul(zInv).n, p)
+		if u == nil {
+			continue
+		}
+
+		if u.Bit(0) != q.y.n.Bit(0) {
+			u.Sub(p, u).Mod(u, p)
+		}
+
+		if x, y := simplifiedSWU(suite, p, u); x.Cmp(q.x.n) != 0 || y.Cmp(q.y.n) != 0 {
+			continue
+		}
+
+		duplicate := false
+		for _, v := range preimages {
+			duplicate = duplicate || v.Cmp(u) == 0
+		}
+
+		if !duplicate {
+			preimages = append(preimages, u)
+		}
+	}
+
+	return preimages
+}
+
+// randomRepresentative returns a random integer r < 2^(8·size) with
+// r = u mod p, chosen uniformly among all of them, so that r is uniform when
+// u is.
+func randomRepresentative(u, p *big.Int, size int) (*big.Int, error) {
+	// the number of integers u + i·p below 2^(8·size)
+	count := new(big.Int).Lsh(bi1, uint(8*size))
+	count.Sub(count, u).Sub(count, bi1).Div(count, p).Add(count, bi1)
+
+	i, err := rand.Int(rand.Reader, count)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.Mul(i, p).Add(i, u), nil
+}
+
+// elligatorSwift returns the 64-byte ElligatorSwift encoding u || t of the
+// x-coordinate x (BIP-324), with a random u and a random one of the eight
+// cases of XSwiftECInv.
+func (ec EllipticCurve) elligatorSwift(x *big.Int) ([]byte, error) {
+	for {
+		u, err := rand.Int(rand.Reader, ec.m)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := rand.Int(rand.Reader, big.NewInt(8))
+		if err != nil {
+			return nil, err
+		}
+
+		t := ec.xSwiftECInv(u, x, int(c.Int64()))
+		if t == nil {
+			continue
+		}
+
+		result := make([]byte, 64)
+		u.FillBytes(result[:32])
+		t.FillBytes(result[32:])
+
+		return result, nil
+	}
+}
+
+// xSwiftEC maps the field elements (u, t) to the x-coordinate of a point of
+// y^2 = x^3 + b with the XSwiftEC function of BIP-324.
+func (ec EllipticCurve) xSwiftEC(u, t *big.Int) *big.Int {
+	fe := func(n *big.Int) *FieldElement { return NewFieldElement(n, ec.m) }
+
+	fu, ft := fe(u), fe(t)
+	if fu.IsZero() {
+		fu = fe(bi1)
+	}
+
+	if ft.IsZero() {
+		ft = fe(bi1)
+	}
+
+	g := fu.Mul(fu).Mul(fu).Add(ec.b)
+	if g.Add(ft.Mul(ft)).IsZero() {
+		ft = ft.MulInt(2)
+	}
+
+	// X = (u^3 + b - t^2) / 2t, Y = (X + t) / (c·u)
+	bigX := g.Sub(ft.Mul(ft)).Mul(ft.MulInt(2).ModInverse())
+	bigY := bigX.Add(ft).Mul(fu.Mul(fe(elligatorSwiftC)).ModInverse())
+
+	half := fe(big.NewInt(2)).ModInverse()
+	xDivY := bigX.Mul(bigY.ModInverse())
+
+	// one of them is always on the curve
+	candidates := []*FieldElement{
+		fu.Add(bigY.Mul(bigY).MulInt(4)),
+		xDivY.Neg().Sub(fu).Mul(half),
+		xDivY.Sub(fu).Mul(half),
+	}
+
+	for _, x := range candidates[:2] {
+		if len(ec.Y(x.n)) > 0 {
+			return x.n
+		}
+	}
+
+	return candidates[2].n
+}
+
+// xSwiftECInv returns a t with xSwiftEC(u, t) = x, or nil if case c, from 0
+// to 7, has none (BIP-324).
+func (ec EllipticCurve) xSwiftECInv(u, x *big.Int, c int) *big.Int {
+	fe := func(n *big.Int) *FieldElement { return NewFieldElement(n, ec.m) }
+	sqrt := func(n *FieldElement) *FieldElement {
+		r := new(big.Int).ModSqrt(n.n, ec.m)
+		if r == nil {
+			return nil
+		}
+
+		return fe(r)
+	}
+
+	fu, fx := fe(u), fe(x)
+	half := fe(big.NewInt(2)).ModInverse()
+	g := fu.Mul(fu).Mul(fu).Add(ec.b)
+
+	var s, v *FieldElement
+	if c&2 == 0 {
+		if len(ec.Y(fx.Add(fu).Neg().n)) > 0 {
+			return nil
+		}
+
+		// s = -(u^3 + b) / (u^2 + uv + v^2)
+		v = fx
+		s = g.Mul(fu.Mul(fu).Add(fu.Mul(v)).Add(v.Mul(v)).ModInverse()).Neg()
+	} else {
+		s = fx.Sub(fu)
+		if s.IsZero() {
+			return nil
+		}
+
+		// r = sqrt(-s(4(u^3 + b) + 3u^2·s)), v = (r/s - u) / 2
+		r := sqrt(s.Mul(g.MulInt(4).Add(fu.Mul(fu).MulInt(3).Mul(s))).Neg())
+		if r == nil || (c&1 == 1 && r.IsZero()) {
+			return nil
+		}
+
+		v = r.Mul(s.ModInverse()).Sub(fu).Mul(half)
+	}
+
+	w := sqrt(s)
+	if w == nil {
+		return nil
+	}
+
+	one, sqrtMinus3 := fe(bi1), fe(elligatorSwiftC)
+
+	// u(1 - c)/2 + v for the cases with bit 0 clear, u(1 + c)/2 + v otherwise,
+	// with the sign of w flipped for cases 0 and 5
+	var t *FieldElement
+	if c&1 == 0 {
+		t = fu.Mul(one.Sub(sqrtMinus3)).Mul(half).Add(v).Mul(w)
+	} else {
+		t = fu.Mul(one.Add(sqrtMinus3)).Mul(half).Add(v).Mul(w)
+	}
+
+	if c&5 == 0 || c&5 == 5 {
+		t = t.Neg()
+	}
+
+	return t.n
+}
@@ -0,0 +1,261 @@
+package becc
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestXSwiftEC(t *testing.T) {
+	// BIP-324 test vectors
+	tt := []struct {
+		u, t string
+		x    string
+	}{
+		{
+			u: "0000000000000000000000000000000000000000000000000000000000000000",
+			t: "0000000000000000000000000000000000000000000000000000000000000000",
+			x: "edd1fd3e327ce90cc7a3542614289aee9682003e9cf7dcc9cf2ca9743be5aa0c",
+		},
+		{
+			u: "0000000000000000000000000000000000000000000000000000000000000000",
+			t: "01d3475bf7655b0fb2d852921035b2ef607f49069b97454e6795251062741771",
+			x: "b5da00b73cd6560520e7c364086e7cd23a34bf60d0e707be9fc34d4cd5fdfa2c",
+		},
+		{
+			u: "0000000000000000000000000000000000000000000000000000000000000000",
+			t: "bde70df51939b94c9c24979fa7dd04ebd9b3572da7802290438af2a681895441",
+			x: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa9fffffd6b",
+		},
+		{
+			u: "0000000000000000000000000000000000000000000000000000000000000000",
+			t: "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+			x: "edd1fd3e327ce90cc7a3542614289aee9682003e9cf7dcc9cf2ca9743be5aa0c",
+		},
+		{
+			u: "0a2d2ba93507f1df233770c2a797962cc61f6d15da14ecd47d8d27ae1cd5f853",
+			t: "0000000000000000000000000000000000000000000000000000000000000000",
+			x: "532167c11200b08c0e84a354e74dcc40f8b25f4fe686e30869526366278a0688",
+		},
+		{
+			u: "0ffde9ca81d751e9cdaffc1a50779245320b28996dbaf32f822f20117c22fbd6",
+			t: "c74d99efceaa550f1ad1c0f43f46e7ff1ee3bd0162b7bf55f2965da9c3450646",
+			x: "74e880b3ffd18fe3cddf7902522551ddf97fa4a35a3cfda8197f947081a57b8f",
+		},
+		{
+			u: "4056a34a210eec7892e8820675c860099f857b26aad85470ee6d3cf1304a9dcf",
+			t: "375e70374271f20b13c9986ed7d3c17799698cfc435dbed3a9f34b38c823c2b4",
+			x: "868aac2003b29dbcad1a3e803855e078a89d16543ac64392d122417298cec76e",
+		},
+		{
+			u: "5eb9696a2336fe2c3c666b02c755db4c0cfd62825c7b589a7b7bb442e141c1d6",
+			t: "93413f0052d49e64abec6d5831d66c43612830a17df1fe4383db896468100221",
+			x: "ef6e1da6d6c7627e80f7a7234cb08a022c1ee1cf29e4d0f9642ae924cef9eb38",
+		},
+		{
+			u: "a1ed0a0bd79d8a23cfe4ec5fef5ba5cccfd844e4ff5cb4b0f2e71627341f1c5b",
+			t: "17c499249e0ac08d5d11ea1c2c8ca7001616559a7994eadec9ca10fb4b8516dc",
+			x: "65a89640744192cdac64b2d21ddf989cdac7500725b645bef8e2200ae39691f2",
+		},
+		{
+			u: "bcaf7219f2f6fbf55fe5e062dce0e48c18f68103f10b8198e974c184750e1be3",
+			t: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffff6507d09a",
+			x: "e7008afe6e8cbd5055df120bd748757c686dadb41cce75e4addcc5e02ec02b44",
+		},
+	}
+
+	ecc := Secp256k1ECC()
+	for _, tc := range tt {
+		t.Run(tc.u[:8]+tc.t[:8], func(t *testing.T) {
+			pub, err := ecc.NewPublicKeyElligator(hexBytes(t, tc.u+tc.t))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if pub.p.X().Cmp(bigIntHex(t, tc.x)) != 0 {
+				t.Errorf("got x = %x, expected %s", pub.p.X(), tc.x)
+			}
+
+			if pub.p.Y().Bit(0) != 0 {
+				t.Errorf("got odd y = %x", pub.p.Y())
+			}
+		})
+	}
+}
+
+func TestXSwiftECInv(t *testing.T) {
+	// BIP-324 test vectors, with "" for the cases without a result
+	tt := []struct {
+		u, x  string
+		cases [8]string
+	}{
+		{
+			u: "05ff6bdad900fc3261bc7fe34e2fb0f569f06e091ae437d3a52e9da0cbfb9590",
+			x: "80cdf63774ec7022c89a5a8558e373a279170285e0ab27412dbce510bdfe23fc",
+			cases: [8]string{
+				"",
+				"",
+				"45654798ece071ba79286d04f7f3eb1c3f1d17dd883610f2ad2efd82a287466b",
+				"0aeaa886f6b76c7158452418cbf5033adc5747e9e9b5d3b2303db96936528557",
+				"",
+				"",
+				"ba9ab867131f8e4586d792fb080c14e3c0e2e82277c9ef0d52d1027c5d78b5c4",
+				"f51557790948938ea7badbe7340afcc523a8b816164a2c4dcfc24695c9ad76d8",
+			},
+		},
+		{
+			u: "1737a85f4c8d146cec96e3ffdca76d9903dcf3bd53061868d478c78c63c2aa9e",
+			x: "39e48dd150d2f429be088dfd5b61882e7e8407483702ae9a5ab35927b15f85ea",
+			cases: [8]string{
+				"1be8cc0b04be0c681d0c6a68f733f82c6c896e0c8a262fcd392918e303a7abf4",
+				"605b5814bf9b8cb066667c9e5480d22dc5b6c92f14b4af3ee0a9eb83b03685e3",
+				"",
+				"",
+				"e41733f4fb41f397e2f3959708cc07d3937691f375d9d032c6d6e71bfc58503b",
+				"9fa4a7eb4064734f99998361ab7f2dd23a4936d0eb4b50c11f56147b4fc9764c",
+				"",
+				"",
+			},
+		},
+		{
+			u: "1aaa1ccebf9c724191033df366b36f691c4d902c228033ff4516d122b2564f68",
+			x: "c75541259d3ba98f207eaa30c69634d187d0b6da594e719e420f4898638fc5b0",
+		},
+		{
+			u: "2323a1d079b0fd72fc8bb62ec34230a815cb0596c2bfac998bd6b84260f5dc26",
+			x: "239342dfb675500a34a196310b8d87d54f49dcac9da50c1743ceab41a7b249ff",
+			cases: [8]string{
+				"f63580b8aa49c4846de56e39e1b3e73f171e881eba8c66f614e67e5c975dfc07",
+				"b6307b332e699f1cf77841d90af25365404deb7fed5edb3090db49e642a156b6",
+				"",
+				"",
+				"09ca7f4755b63b7b921a91c61e4c18c0e8e177e145739909eb1981a268a20028",
+				"49cf84ccd19660e30887be26f50dac9abfb2148012a124cf6f24b618bd5ea579",
+				"",
+				"",
+			},
+		},
+	}
+
+	ec := Secp256k1ECC().Curve()
+	for _, tc := range tt {
+		u, x := bigIntHex(t, tc.u), bigIntHex(t, tc.x)
+
+		for c, expected := range tc.cases {
+			got := ec.xSwiftECInv(u, x, c)
+
+			switch {
+			case expected == "" && got != nil:
+				t.Errorf("u = %.8s, case %d: got %x, expected none", tc.u, c, got)
+			case expected != "" && got == nil:
+				t.Errorf("u = %.8s, case %d: got none, expected %s", tc.u, c, expected)
+			case expected != "" && got.Cmp(bigIntHex(t, expected)) != 0:
+				t.Errorf("u = %.8s, case %d: got %x, expected %s", tc.u, c, got, expected)
+			}
+		}
+	}
+}
+
+func TestElligator(t *testing.T) {
+	tt := []struct {
+		curve string
+		size  int
+	}{
+		{curve: "secp256k1", size: 64},
+		{curve: "secp256r1", size: 96},
+		{curve: "secp384r1", size: 144},
+		{curve: "secp521r1", size: 196},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.curve, func(t *testing.T) {
+			ecc := mustLookupCurve(tc.curve)
+			if got := ecc.ElligatorSize(); got != tc.size {
+				t.Errorf("got size %d, expected %d", got, tc.size)
+			}
+
+			_, pub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			encoded := make([][]byte, 2)
+			for i := range encoded {
+				encoded[i], err = pub.Elligator()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if len(encoded[i]) != tc.size {
+					t.Fatalf("got %d bytes, expected %d", len(encoded[i]), tc.size)
+				}
+
+				decoded, err := ecc.NewPublicKeyElligator(encoded[i])
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				// ElligatorSwift only keeps x
+				if tc.curve == "secp256k1" {
+					if decoded.p.X().Cmp(pub.p.X()) != 0 {
+						t.Errorf("got x = %x, expected %x", decoded.p.X(), pub.p.X())
+					}
+				} else if !decoded.p.Eq(pub.p) {
+					t.Errorf("got %v, expected %v", decoded.p, pub.p)
+				}
+			}
+
+			if bytes.Equal(encoded[0], encoded[1]) {
+				t.Errorf("two encodings of the same key are equal")
+			}
+
+			// any bytes decode to a key
+			if _, err := ecc.NewPublicKeyElligator(bytes.Repeat([]byte{0xa5}, tc.size)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if _, err := ecc.NewPublicKeyElligator(encoded[0][1:]); !errors.Is(err, ErrInvalidPublicKey) {
+				t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		ecc := mustLookupCurve("brainpoolP256r1")
+		_, pub, err := ecc.GenKeyPair()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := pub.Elligator(); !errors.Is(err, ErrUnsupportedCurve) {
+			t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+		}
+	})
+}
+
+func TestSWUPreimages(t *testing.T) {
+	ecc := Secp256r1ECC()
+	suite, err := ecc.hashToCurveSuite()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := range int64(20) {
+		u := new(big.Int).Exp(big.NewInt(i+2), big.NewInt(100), ecc.ec.m)
+
+		preimages := ecc.swuPreimages(suite, ecc.mapToCurve(suite, u))
+		if len(preimages) == 0 || len(preimages) > 4 {
+			t.Fatalf("u = %x: got %d preimages", u, len(preimages))
+		}
+
+		found := false
+		for _, v := range preimages {
+			found = found || v.Cmp(u) == 0
+		}
+
+		if !found {
+			t.Errorf("u = %x is not among the preimages %v", u, preimages)
+		}
+	}
+}
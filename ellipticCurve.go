@@ -49,10 +49,7 @@ func NewEllipticCurve(a, b, m *big.Int) (EllipticCurve, error) {
 	fa := NewFieldElement(a, m)
 	fb := NewFieldElement(b, m)
 
-	disc := fa.Mul(fa).Mul(fa).Mul(NewFieldElement(bi4, m)).
-		Add(fb.Mul(fb).Mul(NewFieldElement(bi27, m)))
-
-	if disc.Eq(NewFieldElement(bi0, m)) {
+	if discriminant(fa, fb).IsZero() {
 		return EllipticCurve{}, ErrInvalidParameters
 	}
 
@@ -63,6 +60,35 @@ func NewEllipticCurve(a, b, m *big.Int) (EllipticCurve, error) {
 	}, nil
 }
 
+// discriminant returns -16(4a^3 + 27b^2), which is zero for singular curves.
+func discriminant(a, b *FieldElement) *FieldElement {
+	m := a.m
+
+	return a.Mul(a).Mul(a).Mul(NewFieldElement(bi4, m)).
+		Add(b.Mul(b).Mul(NewFieldElement(bi27, m))).
+		MulInt(-16)
+}
+
+// Discriminant returns the discriminant -16(4a^3 + 27b^2) mod p of the curve.
+func (ec EllipticCurve) Discriminant() *big.Int {
+	return discriminant(ec.a, ec.b).n
+}
+
+// A returns the coefficient a of y^2 = x^3 + ax + b.
+func (ec EllipticCurve) A() *big.Int {
+	return new(big.Int).Set(ec.a.n)
+}
+
+// B returns the coefficient b of y^2 = x^3 + ax + b.
+func (ec EllipticCurve) B() *big.Int {
+	return new(big.Int).Set(ec.b.n)
+}
+
+// P returns the prime modulus of the field.
+func (ec EllipticCurve) P() *big.Int {
+	return new(big.Int).Set(ec.m)
+}
+
 func (ec EllipticCurve) Infinity() Point {
 	return Point{
 		inf: true,
@@ -0,0 +1,187 @@
+// Package eth implements Ethereum accounts on secp256k1 keys: addresses with
+// EIP-55 mixed-case checksums, and the recoverable signatures of wallets in
+// the 65-byte r || s || v format, over personal_sign messages (EIP-191) and
+// typed structured data (EIP-712).
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/artilugio0/becc"
+)
+
+// SignatureSize is the length of an encoded signature: r and s as 32-byte
+// scalars, and v.
+const SignatureSize = 65
+
+var (
+	ErrInvalidAddress   error = errors.New("invalid address")
+	ErrInvalidChecksum  error = errors.New("invalid address checksum")
+	ErrInvalidSignature error = errors.New("invalid signature")
+)
+
+// Address is the 20-byte address of an account.
+type Address [20]byte
+
+// PublicKeyAddress returns the address of the public key: the last 20 bytes
+// of the Keccak-256 hash of its uncompressed encoding, without the 0x04
+// header.
+func PublicKeyAddress(pub becc.PublicKey) (Address, error) {
+	if err := checkCurve(pub.ECC()); err != nil {
+		return Address{}, err
+	}
+
+	var a Address
+	copy(a[:], keccak256(pub.Uncompressed()[1:])[12:])
+
+	return a, nil
+}
+
+// ParseAddress parses a hex address with the 0x prefix. The EIP-55 checksum
+// is checked when the letters are in mixed case.
+func ParseAddress(s string) (Address, error) {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok || len(digits) != 40 {
+		return Address{}, fmt.Errorf("%w: %q is not 0x and 40 hex digits", ErrInvalidAddress, s)
+	}
+
+	var a Address
+	if _, err := hex.Decode(a[:], []byte(digits)); err != nil {
+		return Address{}, fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && a.String() != s {
+		return Address{}, ErrInvalidChecksum
+	}
+
+	return a, nil
+}
+
+// String returns the EIP-55 encoding of the address: 0x and its hex digits,
+// where each letter is upper case if the matching nibble of the Keccak-256
+// hash of the lower case hex digits is 8 or more.
+func (a Address) String() string {
+	digits := []byte(hex.EncodeToString(a[:]))
+	h := keccak256(digits)
+
+	for i, c := range digits {
+		nibble := h[i/2] >> 4
+		if i%2 == 1 {
+			nibble = h[i/2] & 0xf
+		}
+
+		if c >= 'a' && nibble >= 8 {
+			digits[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(digits)
+}
+
+// PersonalMessage returns the EIP-191 version 0x45 encoding of the message
+// signed by personal_sign: "\x19Ethereum Signed Message:\n", the decimal
+// length of the message and the message.
+func PersonalMessage(message []byte) []byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message))
+
+	return append([]byte(prefix), message...)
+}
+
+// HashPersonalMessage returns the Keccak-256 hash of the personal_sign
+// encoding of the message, the hash its signatures sign.
+func HashPersonalMessage(message []byte) []byte {
+	return keccak256(PersonalMessage(message))
+}
+
+// SignPersonal returns the personal_sign signature of the message.
+func SignPersonal(priv becc.PrivateKey, message []byte) ([]byte, error) {
+	return sign(priv, PersonalMessage(message))
+}
+
+// RecoverPersonal returns the public key of the personal_sign signature of
+// the message.
+func RecoverPersonal(message, sig []byte) (becc.PublicKey, error) {
+	return recoverPublicKey(PersonalMessage(message), sig)
+}
+
+// sign returns the signature of the Keccak-256 hash of the encoded message:
+// the deterministic low-s ECDSA signature r || s with v = 27 + recovery id.
+func sign(priv becc.PrivateKey, encoded []byte) ([]byte, error) {
+	if err := checkCurve(priv.ECC()); err != nil {
+		return nil, err
+	}
+
+	sig, recoveryID, err := priv.SignRecoverable(becc.Keccak256, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	// v only holds the parity of R, and its x-coordinate is n or more with
+	// negligible probability
+	if recoveryID > 1 {
+		return nil, fmt.Errorf("%w: recovery id %d cannot be encoded", ErrInvalidSignature, recoveryID)
+	}
+
+	b := make([]byte, SignatureSize)
+	sig.R().FillBytes(b[:32])
+	sig.S().FillBytes(b[32:64])
+	b[64] = 27 + recoveryID
+
+	return b, nil
+}
+
+// recoverPublicKey returns the public key of the signature of the Keccak-256
+// hash of the encoded message. v may be 27 or 28, or 0 or 1 as some signers
+// produce. Signatures with a high s are rejected, as in Ethereum
+// transactions since EIP-2.
+func recoverPublicKey(encoded, sig []byte) (becc.PublicKey, error) {
+	if len(sig) != SignatureSize {
+		return becc.PublicKey{}, fmt.Errorf("%w: the signature must be %d bytes", ErrInvalidSignature, SignatureSize)
+	}
+
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+
+	if v > 1 {
+		return becc.PublicKey{}, fmt.Errorf("%w: invalid v %d", ErrInvalidSignature, sig[64])
+	}
+
+	ecc := becc.Secp256k1ECC()
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+
+	if s.Cmp(new(big.Int).Rsh(ecc.Order(), 1)) > 0 {
+		return becc.PublicKey{}, fmt.Errorf("%w: high s", ErrInvalidSignature)
+	}
+
+	pub, err := ecc.RecoverPublicKey(becc.Keccak256, encoded, becc.NewSignature(r, s), v)
+	if err != nil {
+		return becc.PublicKey{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return pub, nil
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := becc.Keccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+
+	return h.Sum(nil)
+}
+
+func checkCurve(ecc *becc.ECC) error {
+	if ecc.Name() != "secp256k1" {
+		return fmt.Errorf("%w: Ethereum keys are secp256k1 keys", becc.ErrUnsupportedCurve)
+	}
+
+	return nil
+}
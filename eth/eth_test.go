@@ -0,0 +1,366 @@
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/artilugio0/becc"
+)
+
+func TestAddress(t *testing.T) {
+	// EIP-55 test vectors
+	for _, s := range []string{
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		a, err := ParseAddress(s)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s, err)
+		}
+
+		// the all upper and all lower case vectors are also their checksum
+		// encodings
+		if got := a.String(); got != s {
+			t.Errorf("got %s, want %s", got, s)
+		}
+	}
+
+	if _, err := ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"); !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("expected error %v, got %v", ErrInvalidChecksum, err)
+	}
+
+	for _, s := range []string{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg"} {
+		if _, err := ParseAddress(s); !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("%s: expected error %v, got %v", s, ErrInvalidAddress, err)
+		}
+	}
+
+	// the address of the private key 1
+	priv, err := becc.Secp256k1ECC().NewPrivateKey(big.NewInt(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, err := PublicKeyAddress(priv.PublicKey())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a.String() != "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf" {
+		t.Errorf("got %s", a)
+	}
+
+	_, p256Pub, err := becc.Secp256r1ECC().GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := PublicKeyAddress(p256Pub); !errors.Is(err, becc.ErrUnsupportedCurve) {
+		t.Errorf("expected error %v, got %v", becc.ErrUnsupportedCurve, err)
+	}
+}
+
+func TestPersonalSign(t *testing.T) {
+	// the web3.js documentation example of eth.accounts.sign
+	priv, err := becc.Secp256k1ECC().NewPrivateKeyBytes(mustHex(t, "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	message := []byte("Some data")
+
+	if got := hex.EncodeToString(HashPersonalMessage(message)); got != "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655" {
+		t.Errorf("got hash %s", got)
+	}
+
+	sig, err := SignPersonal(priv, message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+	if got := hex.EncodeToString(sig); got != want {
+		t.Errorf("got signature %s, want %s", got, want)
+	}
+
+	pub, err := RecoverPersonal(message, sig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, err := PublicKeyAddress(pub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a.String() != "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+		t.Errorf("recovered %s", a)
+	}
+
+	// v = 0 or 1 is accepted too
+	zeroV := append([]byte(nil), sig...)
+	zeroV[64] -= 27
+	if pub, err := RecoverPersonal(message, zeroV); err != nil || !pub.Point().Eq(priv.PublicKey().Point()) {
+		t.Errorf("v = %d: recovered %x, error %v", zeroV[64], pub.Compressed(), err)
+	}
+
+	// another message recovers another key
+	if pub, err := RecoverPersonal([]byte("Other data"), sig); err == nil && pub.Point().Eq(priv.PublicKey().Point()) {
+		t.Errorf("recovered the signer for another message")
+	}
+
+	highS := append([]byte(nil), sig...)
+	s := new(big.Int).Sub(becc.Secp256k1ECC().Order(), new(big.Int).SetBytes(sig[32:64]))
+	s.FillBytes(highS[32:64])
+	highS[64] ^= 1
+
+	for _, tt := range []struct {
+		name string
+		sig  []byte
+	}{
+		{name: "high s", sig: highS},
+		{name: "v", sig: append(append([]byte(nil), sig[:64]...), 29)},
+		{name: "length", sig: sig[:64]},
+	} {
+		if _, err := RecoverPersonal(message, tt.sig); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: expected error %v, got %v", tt.name, ErrInvalidSignature, err)
+		}
+	}
+}
+
+// mail is the example of EIP-712
+const mail = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedData(t *testing.T) {
+	td, err := ParseTypedData([]byte(mail))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encodedType, err := td.EncodeType("Mail")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if encodedType != "Mail(Person from,Person to,string contents)Person(string name,address wallet)" {
+		t.Errorf("got type %s", encodedType)
+	}
+
+	if got := hex.EncodeToString(keccak256([]byte(encodedType))); got != "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2" {
+		t.Errorf("got type hash %s", got)
+	}
+
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(domainSeparator); got != "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f" {
+		t.Errorf("got domain separator %s", got)
+	}
+
+	messageHash, err := td.HashStruct("Mail", td.Message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(messageHash); got != "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e" {
+		t.Errorf("got message hash %s", got)
+	}
+
+	hash, err := td.Hash()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(hash); got != "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Errorf("got hash %s", got)
+	}
+
+	// the private key of Cow is Keccak-256("cow")
+	priv, err := becc.Secp256k1ECC().NewPrivateKeyBytes(keccak256([]byte("cow")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sig, err := SignTypedData(priv, td)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+	if got := hex.EncodeToString(sig); got != want {
+		t.Errorf("got signature %s, want %s", got, want)
+	}
+
+	pub, err := RecoverTypedData(td, sig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, err := PublicKeyAddress(pub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a.String() != "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826" {
+		t.Errorf("recovered %s", a)
+	}
+
+	// without EIP712Domain in the types, it is derived from the domain
+	delete(td.Types, "EIP712Domain")
+
+	derived, err := td.DomainSeparator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hex.EncodeToString(derived) != hex.EncodeToString(domainSeparator) {
+		t.Errorf("got derived domain separator %x", derived)
+	}
+
+	// the hash of the domain alone
+	td.PrimaryType = "EIP712Domain"
+
+	domainHash, err := td.Hash()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hex.EncodeToString(domainHash) != hex.EncodeToString(keccak256([]byte{0x19, 0x01}, domainSeparator)) {
+		t.Errorf("got domain hash %x", domainHash)
+	}
+}
+
+func TestTypedDataValues(t *testing.T) {
+	td := &TypedData{
+		Types: map[string][]TypedDataField{
+			"Item": {
+				{Name: "id", Type: "uint8"},
+				{Name: "delta", Type: "int16"},
+				{Name: "tags", Type: "bytes4[2]"},
+				{Name: "data", Type: "bytes"},
+				{Name: "done", Type: "bool"},
+			},
+			"List": {
+				{Name: "items", Type: "Item[]"},
+			},
+		},
+		PrimaryType: "List",
+	}
+
+	encodedType, err := td.EncodeType("List")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if encodedType != "List(Item[] items)Item(uint8 id,int16 delta,bytes4[2] tags,bytes data,bool done)" {
+		t.Errorf("got type %s", encodedType)
+	}
+
+	item := map[string]any{"id": "0xff", "delta": -1, "tags": []any{"0x01020304", "0x05060708"}, "data": "0x", "done": true}
+
+	encoded, err := td.encodeValue("int16", -1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hex.EncodeToString(encoded) != strings.Repeat("ff", 32) {
+		t.Errorf("got -1 as %x", encoded)
+	}
+
+	encoded, err = td.encodeValue("bytes4", "0x01020304")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hex.EncodeToString(encoded) != "01020304"+strings.Repeat("00", 28) {
+		t.Errorf("got bytes4 as %x", encoded)
+	}
+
+	if _, err := td.HashStruct("List", map[string]any{"items": []any{item, item}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		field string
+		value any
+	}{
+		{name: "uint8 overflow", field: "id", value: 256},
+		{name: "int16 underflow", field: "delta", value: "-32769"},
+		{name: "fixed array length", field: "tags", value: []any{"0x01020304"}},
+		{name: "bytes4 length", field: "tags", value: []any{"0x010203", "0x05060708"}},
+		{name: "bytes without 0x", field: "data", value: "00"},
+		{name: "bool", field: "done", value: "true"},
+	} {
+		invalid := map[string]any{}
+		for k, v := range item {
+			invalid[k] = v
+		}
+		invalid[tt.field] = tt.value
+
+		if _, err := td.HashStruct("Item", invalid); !errors.Is(err, ErrInvalidTypedData) {
+			t.Errorf("%s: expected error %v, got %v", tt.name, ErrInvalidTypedData, err)
+		}
+	}
+
+	if _, err := td.HashStruct("Item", map[string]any{"id": 1}); !errors.Is(err, ErrInvalidTypedData) {
+		t.Errorf("missing field: expected error %v, got %v", ErrInvalidTypedData, err)
+	}
+
+	if _, err := td.EncodeType("Missing"); !errors.Is(err, ErrInvalidTypedData) {
+		t.Errorf("unknown type: expected error %v, got %v", ErrInvalidTypedData, err)
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex string %s: %v", s, err)
+	}
+
+	return b
+}
@@ -0,0 +1,438 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/artilugio0/becc"
+)
+
+// domainTypeName is the name of the struct type of the domain.
+const domainTypeName = "EIP712Domain"
+
+var ErrInvalidTypedData error = errors.New("invalid typed data")
+
+// TypedData is the typed structured data of EIP-712, in the JSON format of
+// eth_signTypedData_v4: the struct types, and the domain and the message as
+// JSON objects of the EIP712Domain and the primary type.
+//
+// Integers are JSON numbers or decimal or 0x hex strings, and addresses and
+// byte strings are 0x hex strings.
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]any              `json:"domain"`
+	Message     map[string]any              `json:"message"`
+}
+
+// TypedDataField is a member of a struct type.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// domainFields are the fields of the EIP712Domain type in their order, of
+// which a domain uses a subset.
+var domainFields = []TypedDataField{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+	{Name: "salt", Type: "bytes32"},
+}
+
+// ParseTypedData parses typed data in JSON, keeping the JSON numbers exact.
+func ParseTypedData(b []byte) (*TypedData, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var td TypedData
+	if err := d.Decode(&td); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTypedData, err)
+	}
+
+	if td.PrimaryType == "" {
+		return nil, fmt.Errorf("%w: no primary type", ErrInvalidTypedData)
+	}
+
+	return &td, nil
+}
+
+// Hash returns the EIP-712 hash that the signatures of td sign:
+// Keccak-256(0x19 || 0x01 || domainSeparator || hashStruct(message)), where
+// the message hash is left out when the primary type is EIP712Domain.
+func (td *TypedData) Hash() ([]byte, error) {
+	encoded, err := td.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256(encoded), nil
+}
+
+// DomainSeparator returns hashStruct(domain). When the types do not define
+// EIP712Domain, its fields are those of the domain, in the order of EIP-712.
+func (td *TypedData) DomainSeparator() ([]byte, error) {
+	return td.HashStruct(domainTypeName, td.Domain)
+}
+
+// HashStruct returns Keccak-256(typeHash || encodeData(data)) for the struct
+// type, where typeHash is the Keccak-256 hash of EncodeType.
+func (td *TypedData) HashStruct(typeName string, data map[string]any) ([]byte, error) {
+	encodedType, err := td.EncodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, _ := td.fields(typeName)
+
+	encoded := keccak256([]byte(encodedType))
+	for _, f := range fields {
+		v, ok := data[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no field %s", ErrInvalidTypedData, typeName, f.Name)
+		}
+
+		value, err := td.encodeValue(f.Type, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, f.Name, err)
+		}
+
+		encoded = append(encoded, value...)
+	}
+
+	return keccak256(encoded), nil
+}
+
+// EncodeType returns the encoding of the struct type, such as
+// "Mail(Person from,Person to,string contents)Person(string name,address wallet)":
+// the type and its fields, followed by the struct types it references
+// directly or indirectly, in alphabetical order.
+func (td *TypedData) EncodeType(typeName string) (string, error) {
+	deps := map[string]bool{}
+	if err := td.dependencies(typeName, deps); err != nil {
+		return "", err
+	}
+	delete(deps, typeName)
+
+	names := []string{typeName}
+	for name := range deps {
+		names = append(names, name)
+	}
+	slices.Sort(names[1:])
+
+	var sb strings.Builder
+	for _, name := range names {
+		fields, _ := td.fields(name)
+
+		sb.WriteString(name)
+		sb.WriteByte('(')
+		for i, f := range fields {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(f.Type)
+			sb.WriteByte(' ')
+			sb.WriteString(f.Name)
+		}
+		sb.WriteByte(')')
+	}
+
+	return sb.String(), nil
+}
+
+// SignTypedData returns the eth_signTypedData_v4 signature of td.
+func SignTypedData(priv becc.PrivateKey, td *TypedData) ([]byte, error) {
+	encoded, err := td.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return sign(priv, encoded)
+}
+
+// RecoverTypedData returns the public key of the signature of td.
+func RecoverTypedData(td *TypedData, sig []byte) (becc.PublicKey, error) {
+	encoded, err := td.encode()
+	if err != nil {
+		return becc.PublicKey{}, err
+	}
+
+	return recoverPublicKey(encoded, sig)
+}
+
+// encode returns 0x19 || 0x01 || domainSeparator || hashStruct(message), the
+// input of the hash of td.
+func (td *TypedData) encode() ([]byte, error) {
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := append([]byte{0x19, 0x01}, domainSeparator...)
+	if td.PrimaryType == domainTypeName {
+		return encoded, nil
+	}
+
+	messageHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(encoded, messageHash...), nil
+}
+
+// fields returns the fields of the struct type, deriving those of
+// EIP712Domain from the domain if the types do not define it.
+func (td *TypedData) fields(typeName string) ([]TypedDataField, bool) {
+	if fields, ok := td.Types[typeName]; ok {
+		return fields, true
+	}
+
+	if typeName != domainTypeName {
+		return nil, false
+	}
+
+	fields := []TypedDataField{}
+	for _, f := range domainFields {
+		if _, ok := td.Domain[f.Name]; ok {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields, true
+}
+
+// dependencies adds the struct type and the struct types it references to
+// deps.
+func (td *TypedData) dependencies(typeName string, deps map[string]bool) error {
+	if deps[typeName] {
+		return nil
+	}
+
+	fields, ok := td.fields(typeName)
+	if !ok {
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidTypedData, typeName)
+	}
+	deps[typeName] = true
+
+	for _, f := range fields {
+		base := f.Type
+		if i := strings.IndexByte(base, '['); i >= 0 {
+			base = base[:i]
+		}
+
+		if _, ok := td.fields(base); ok {
+			if err := td.dependencies(base, deps); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// encodeValue returns the 32-byte encoding of the value of the type in
+// encodeData: atomic values as a 32-byte word, strings and byte strings as
+// their hash, structs as their hashStruct and arrays as the hash of the
+// concatenated encodings of their elements.
+func (td *TypedData) encodeValue(typ string, v any) ([]byte, error) {
+	if strings.HasSuffix(typ, "]") {
+		i := strings.LastIndexByte(typ, '[')
+		if i < 0 {
+			return nil, fmt.Errorf("%w: invalid type %s", ErrInvalidTypedData, typ)
+		}
+
+		elements, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an array", ErrInvalidTypedData, typ)
+		}
+
+		if length := typ[i+1 : len(typ)-1]; length != "" {
+			n, err := strconv.Atoi(length)
+			if err != nil || n != len(elements) {
+				return nil, fmt.Errorf("%w: %s with %d elements", ErrInvalidTypedData, typ, len(elements))
+			}
+		}
+
+		encoded := []byte{}
+		for _, e := range elements {
+			value, err := td.encodeValue(typ[:i], e)
+			if err != nil {
+				return nil, err
+			}
+
+			encoded = append(encoded, value...)
+		}
+
+		return keccak256(encoded), nil
+	}
+
+	if _, ok := td.fields(typ); ok {
+		data, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an object", ErrInvalidTypedData, typ)
+		}
+
+		return td.HashStruct(typ, data)
+	}
+
+	switch {
+	case typ == "string":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not a string", ErrInvalidTypedData, v)
+		}
+
+		return keccak256([]byte(s)), nil
+	case typ == "bytes":
+		b, err := parseHexBytes(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return keccak256(b), nil
+	case typ == "bool":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not a bool", ErrInvalidTypedData, v)
+		}
+
+		word := make([]byte, 32)
+		if b {
+			word[31] = 1
+		}
+
+		return word, nil
+	case typ == "address":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not an address", ErrInvalidTypedData, v)
+		}
+
+		a, err := ParseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(make([]byte, 12), a[:]...), nil
+	case strings.HasPrefix(typ, "bytes"):
+		n, err := strconv.Atoi(typ[len("bytes"):])
+		if err != nil || n < 1 || n > 32 {
+			return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidTypedData, typ)
+		}
+
+		b, err := parseHexBytes(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b) != n {
+			return nil, fmt.Errorf("%w: %d bytes for %s", ErrInvalidTypedData, len(b), typ)
+		}
+
+		return append(b, make([]byte, 32-n)...), nil
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		return encodeInteger(typ, v)
+	}
+
+	return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidTypedData, typ)
+}
+
+// encodeInteger returns the 32-byte two's complement encoding of the value of
+// the uintN or intN type.
+func encodeInteger(typ string, v any) ([]byte, error) {
+	signed := strings.HasPrefix(typ, "int")
+	bits, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"))
+	if err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+		return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidTypedData, typ)
+	}
+
+	n, err := parseInteger(v)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	lower := new(big.Int)
+	if signed {
+		limit.Rsh(limit, 1)
+		lower.Neg(limit)
+	}
+
+	if n.Cmp(lower) < 0 || n.Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("%w: %s out of the range of %s", ErrInvalidTypedData, n, typ)
+	}
+
+	if n.Sign() < 0 {
+		n.Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+
+	return n.FillBytes(make([]byte, 32)), nil
+}
+
+// parseInteger parses an integer given as a JSON number, a decimal or 0x hex
+// string, or a Go integer.
+func parseInteger(v any) (*big.Int, error) {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("%w: %v is not an exact integer", ErrInvalidTypedData, v)
+		}
+
+		return big.NewInt(int64(v)), nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case *big.Int:
+		return new(big.Int).Set(v), nil
+	default:
+		return nil, fmt.Errorf("%w: %v is not an integer", ErrInvalidTypedData, v)
+	}
+
+	n, ok := new(big.Int), false
+	if digits, found := strings.CutPrefix(s, "0x"); found {
+		_, ok = n.SetString(digits, 16)
+	} else {
+		_, ok = n.SetString(s, 10)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidTypedData, s)
+	}
+
+	return n, nil
+}
+
+func parseHexBytes(v any) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not a hex string", ErrInvalidTypedData, v)
+	}
+
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q has no 0x prefix", ErrInvalidTypedData, s)
+	}
+
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTypedData, err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,580 @@
+// Package frost implements FROST (RFC 9591), a threshold Schnorr signature
+// scheme, over secp256k1 and P-256.
+//
+// The group signing key is split into shares with Shamir secret sharing,
+// either by a trusted dealer (TrustedDealerKeygen) or by the participants
+// themselves with a distributed key generation (NewDKG). Any minSigners of
+// the participants can then sign in two rounds:
+//
+//  1. every signer calls Commit and sends its Commitment to the others
+//  2. every signer calls Sign with the message and the commitments of all the
+//     signers, and the SignatureShares are combined with Aggregate
+//
+// The result is an ordinary Schnorr signature (R, z) with z·G = R + c·P for
+// the group public key P, which Verify checks without knowing anything about
+// the participants. The ciphersuites of RFC 9591 hash R, P and the message
+// into c with their own hash, so their signatures are checked by the Schnorr
+// verification of the RFC (Verify), not by BIP-340. The secp256k1 suite of
+// NewTaproot uses the BIP-340 challenge instead, and its signatures are
+// accepted by becc.PublicKey.VerifySchnorr.
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/shamir"
+)
+
+var (
+	ErrInvalidSignatureShare error = errors.New("invalid signature share")
+	ErrInvalidCommitments    error = errors.New("invalid commitments")
+	ErrInvalidSignature      error = errors.New("invalid signature")
+)
+
+// contextStrings are the context strings of the ciphersuites of RFC 9591,
+// keyed by curve name.
+var contextStrings = map[string]string{
+	"secp256k1": "FROST-secp256k1-SHA256-v1",
+	"secp256r1": "FROST-P256-SHA256-v1",
+}
+
+// taprootContextString is the context string of the ciphersuite of
+// NewTaproot.
+const taprootContextString = "FROST-secp256k1-SHA256-TR-v1"
+
+// scalarHashLength is the length expanded and reduced modulo n by the hashes
+// H1, H2, H3, HDKG and HID, which leaves a bias of at most 2^-128.
+const scalarHashLength = 48
+
+// Suite is a FROST ciphersuite: a curve and the hash functions derived from
+// its context string.
+type Suite struct {
+	ecc           *becc.ECC
+	contextString string
+
+	// taproot is set for the suite of NewTaproot
+	taproot bool
+}
+
+// New returns the ciphersuite of ecc. It fails with becc.ErrUnsupportedCurve
+// for curves other than secp256k1 and secp256r1.
+func New(ecc *becc.ECC) (*Suite, error) {
+	contextString, ok := contextStrings[ecc.Name()]
+	if !ok {
+		return nil, fmt.Errorf("%w: no FROST ciphersuite for %q", becc.ErrUnsupportedCurve, ecc.Name())
+	}
+
+	// the hash-to-curve suite check also rejects custom curves that reuse the
+	// name of a supported one
+	if _, err := ecc.HashToCurveSuiteID(true); err != nil {
+		return nil, err
+	}
+
+	return &Suite{ecc: ecc, contextString: contextString}, nil
+}
+
+// NewTaproot returns a secp256k1 ciphersuite whose signatures are BIP-340
+// signatures of the group key, as used by taproot: the challenge is the
+// BIP-340 one, c = hash_BIP0340/challenge(x(R) || x(P) || msg), and the
+// signers negate their nonces when R has an odd y and their key shares when P
+// has an odd y, since BIP-340 signs for the points with an even y. The other
+// hashes are those of the secp256k1 suite of RFC 9591 with the context string
+// FROST-secp256k1-SHA256-TR-v1. It fails with becc.ErrUnsupportedCurve for
+// other curves.
+func NewTaproot(ecc *becc.ECC) (*Suite, error) {
+	if ecc.Name() != "secp256k1" {
+		return nil, fmt.Errorf("%w: BIP-340 is only defined for secp256k1", becc.ErrUnsupportedCurve)
+	}
+
+	if _, err := ecc.HashToCurveSuiteID(true); err != nil {
+		return nil, err
+	}
+
+	return &Suite{ecc: ecc, contextString: taprootContextString, taproot: true}, nil
+}
+
+// ECC returns the curve of the ciphersuite.
+func (s *Suite) ECC() *becc.ECC {
+	return s.ecc
+}
+
+// ContextString returns the context string of the ciphersuite, such as
+// "FROST-P256-SHA256-v1".
+func (s *Suite) ContextString() string {
+	return s.contextString
+}
+
+// Nonces are the secret nonces of a signer for one signing operation. They
+// are erased by Sign and must never be used twice.
+type Nonces struct {
+	hiding, binding *big.Int
+}
+
+// Commitment is the public commitment to the nonces of a signer.
+type Commitment struct {
+	id              *big.Int
+	hiding, binding becc.Point
+}
+
+// NewCommitment returns the commitment of the participant id to its hiding
+// and binding nonces, as received from that participant.
+func (s *Suite) NewCommitment(id *big.Int, hiding, binding becc.Point) (Commitment, error) {
+	if err := s.checkID(id); err != nil {
+		return Commitment{}, err
+	}
+
+	if err := s.ecc.ValidatePublicKey(hiding); err != nil {
+		return Commitment{}, fmt.Errorf("%w: hiding nonce commitment: %w", ErrInvalidCommitments, err)
+	}
+
+	if err := s.ecc.ValidatePublicKey(binding); err != nil {
+		return Commitment{}, fmt.Errorf("%w: binding nonce commitment: %w", ErrInvalidCommitments, err)
+	}
+
+	return Commitment{id: new(big.Int).Set(id), hiding: hiding, binding: binding}, nil
+}
+
+// ID returns the identifier of the participant.
+func (c Commitment) ID() *big.Int {
+	return new(big.Int).Set(c.id)
+}
+
+// Hiding returns the commitment to the hiding nonce.
+func (c Commitment) Hiding() becc.Point {
+	return c.hiding
+}
+
+// Binding returns the commitment to the binding nonce.
+func (c Commitment) Binding() becc.Point {
+	return c.binding
+}
+
+// SignatureShare is the share z_i of the signature computed by one signer.
+type SignatureShare struct {
+	id, z *big.Int
+}
+
+// NewSignatureShare returns the signature share z of the participant id, as
+// received from that participant.
+func (s *Suite) NewSignatureShare(id, z *big.Int) (SignatureShare, error) {
+	if err := s.checkID(id); err != nil {
+		return SignatureShare{}, err
+	}
+
+	if z.Sign() < 0 || z.Cmp(s.ecc.Order()) >= 0 {
+		return SignatureShare{}, fmt.Errorf("%w: scalar out of range", ErrInvalidSignatureShare)
+	}
+
+	return SignatureShare{id: new(big.Int).Set(id), z: new(big.Int).Set(z)}, nil
+}
+
+// ID returns the identifier of the participant.
+func (sh SignatureShare) ID() *big.Int {
+	return new(big.Int).Set(sh.id)
+}
+
+// Z returns the share z_i.
+func (sh SignatureShare) Z() *big.Int {
+	return new(big.Int).Set(sh.z)
+}
+
+// Signature is a Schnorr signature (R, z).
+type Signature struct {
+	ecc *becc.ECC
+	r   becc.Point
+	z   *big.Int
+}
+
+// ParseSignature parses a signature encoded by Signature.Bytes.
+func (s *Suite) ParseSignature(b []byte) (Signature, error) {
+	ptLen := s.ecc.CoordinateSize() + 1
+	if len(b) != ptLen+s.ecc.ScalarSize() {
+		return Signature{}, fmt.Errorf("%w: invalid length", ErrInvalidSignature)
+	}
+
+	r, err := s.ecc.NewPublicKeyCompressed(b[:ptLen])
+	if err != nil {
+		return Signature{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	z := new(big.Int).SetBytes(b[ptLen:])
+	if z.Cmp(s.ecc.Order()) >= 0 {
+		return Signature{}, fmt.Errorf("%w: scalar out of range", ErrInvalidSignature)
+	}
+
+	return Signature{ecc: s.ecc, r: r.Point(), z: z}, nil
+}
+
+// R returns the commitment R of the signature.
+func (sig Signature) R() becc.Point {
+	return sig.r
+}
+
+// Z returns the response z of the signature.
+func (sig Signature) Z() *big.Int {
+	return new(big.Int).Set(sig.z)
+}
+
+// SchnorrSignature returns the BIP-340 signature (x(R), z) of the signatures
+// of the NewTaproot suite, whose R has an even y.
+func (sig Signature) SchnorrSignature() becc.SchnorrSignature {
+	return becc.NewSchnorrSignature(sig.r.X(), sig.z)
+}
+
+// Bytes returns the encoding of the signature: R compressed followed by z as
+// a big-endian scalar.
+func (sig Signature) Bytes() []byte {
+	return slices.Concat(encodePoint(sig.ecc, sig.r), encodeScalar(sig.ecc, sig.z))
+}
+
+// Commit returns fresh nonces for the key share and the commitment to send to
+// the other signers (round one).
+func (s *Suite) Commit(share KeyShare) (*Nonces, Commitment, error) {
+	hiding, err := s.randomNonce(share.secret)
+	if err != nil {
+		return nil, Commitment{}, err
+	}
+
+	binding, err := s.randomNonce(share.secret)
+	if err != nil {
+		return nil, Commitment{}, err
+	}
+
+	nonces, commitment := s.commitWithNonces(share, hiding, binding)
+
+	return nonces, commitment, nil
+}
+
+func (s *Suite) commitWithNonces(share KeyShare, hiding, binding *big.Int) (*Nonces, Commitment) {
+	g := s.ecc.Generator()
+
+	nonces := &Nonces{hiding: hiding, binding: binding}
+	commitment := Commitment{
+		id:      new(big.Int).Set(share.id),
+		hiding:  g.ScalarMul(hiding),
+		binding: g.ScalarMul(binding),
+	}
+
+	return nonces, commitment
+}
+
+// Sign returns the signature share of msg for the key share (round two), with
+// the nonces returned by Commit and the commitments of all the signers,
+// including this one. The nonces are erased, so that they cannot be reused.
+func (s *Suite) Sign(share KeyShare, nonces *Nonces, msg []byte, commitments []Commitment) (SignatureShare, error) {
+	if nonces == nil || nonces.hiding == nil {
+		return SignatureShare{}, errors.New("nonces already used")
+	}
+
+	defer func() {
+		nonces.hiding.SetInt64(0)
+		nonces.binding.SetInt64(0)
+		nonces.hiding, nonces.binding = nil, nil
+	}()
+
+	commitments, err := s.sortedCommitments(commitments, share.minSigners)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	own, ok := findCommitment(commitments, share.id)
+	if !ok {
+		return SignatureShare{}, fmt.Errorf("%w: no commitment of participant %s", ErrInvalidCommitments, share.id)
+	}
+
+	g := s.ecc.Generator()
+	if !own.hiding.Eq(g.ScalarMul(nonces.hiding)) || !own.binding.Eq(g.ScalarMul(nonces.binding)) {
+		return SignatureShare{}, fmt.Errorf("%w: the commitment of participant %s does not match its nonces", ErrInvalidCommitments, share.id)
+	}
+
+	bindingFactors := s.bindingFactors(share.groupKey.Point(), commitments, msg)
+	r, err := s.groupCommitment(commitments, bindingFactors)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	lambda := s.lagrangeCoefficient(commitments, share.id)
+	c := s.challenge(r, share.groupKey.Point(), msg)
+	negateNonces, negateKey := s.negations(r, share.groupKey.Point())
+
+	// z_i = ±(d_i + e_i·ρ_i) ± λ_i·s_i·c
+	n := s.ecc.Order()
+	z := new(big.Int).Mul(nonces.binding, bindingFactors[share.id.String()])
+	z.Add(z, nonces.hiding)
+	if negateNonces {
+		z.Neg(z)
+	}
+
+	lambda.Mul(lambda, share.secret).Mul(lambda, c)
+	if negateKey {
+		lambda.Neg(lambda)
+	}
+
+	z.Add(z, lambda).Mod(z, n)
+
+	return SignatureShare{id: new(big.Int).Set(share.id), z: z}, nil
+}
+
+// VerifySignatureShare reports whether share is the valid signature share of
+// msg of the participant with the public key publicKey, for the signers of
+// commitments. It lets the aggregator find out which signer misbehaved when
+// Aggregate returns an invalid signature.
+func (s *Suite) VerifySignatureShare(publicKey becc.Point, groupKey becc.PublicKey, msg []byte, commitments []Commitment, share SignatureShare) bool {
+	commitments, err := s.sortedCommitments(commitments, 1)
+	if err != nil {
+		return false
+	}
+
+	own, ok := findCommitment(commitments, share.id)
+	if !ok {
+		return false
+	}
+
+	bindingFactors := s.bindingFactors(groupKey.Point(), commitments, msg)
+	r, err := s.groupCommitment(commitments, bindingFactors)
+	if err != nil {
+		return false
+	}
+
+	lambda := s.lagrangeCoefficient(commitments, share.id)
+	c := s.challenge(r, groupKey.Point(), msg)
+
+	// z_i·G = ±(D_i + ρ_i·E_i) ± (c·λ_i)·P_i
+	commitmentShare := own.hiding.Add(own.binding.ScalarMul(bindingFactors[share.id.String()]))
+	negateNonces, negateKey := s.negations(r, groupKey.Point())
+	if negateNonces {
+		commitmentShare = commitmentShare.Neg()
+	}
+
+	if negateKey {
+		publicKey = publicKey.Neg()
+	}
+
+	expected := commitmentShare.Add(publicKey.ScalarMul(new(big.Int).Mul(c, lambda)))
+
+	return s.ecc.Generator().ScalarMul(share.z).Eq(expected)
+}
+
+// Aggregate combines the signature shares of all the signers of commitments
+// into the signature of msg. It does not verify the shares: the result should
+// be checked with Verify, and the shares with VerifySignatureShare if it is
+// not valid.
+func (s *Suite) Aggregate(groupKey becc.PublicKey, msg []byte, commitments []Commitment, shares []SignatureShare) (Signature, error) {
+	commitments, err := s.sortedCommitments(commitments, 1)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	if len(shares) != len(commitments) {
+		return Signature{}, fmt.Errorf("%w: got %d shares for %d signers", ErrInvalidSignatureShare, len(shares), len(commitments))
+	}
+
+	z := new(big.Int)
+	seen := map[string]bool{}
+	for _, share := range shares {
+		if _, ok := findCommitment(commitments, share.id); !ok || seen[share.id.String()] {
+			return Signature{}, fmt.Errorf("%w: unexpected share of participant %s", ErrInvalidSignatureShare, share.id)
+		}
+		seen[share.id.String()] = true
+
+		z.Add(z, share.z)
+	}
+	z.Mod(z, s.ecc.Order())
+
+	r, err := s.groupCommitment(commitments, s.bindingFactors(groupKey.Point(), commitments, msg))
+	if err != nil {
+		return Signature{}, err
+	}
+
+	// the shares sign for the R with an even y
+	if negateNonces, _ := s.negations(r, groupKey.Point()); negateNonces {
+		r = r.Neg()
+	}
+
+	return Signature{ecc: s.ecc, r: r, z: z}, nil
+}
+
+// Verify reports whether sig is a valid Schnorr signature of msg for the
+// group public key: z·G = R + c·P with c = H2(R || P || msg), or for the
+// NewTaproot suite a valid BIP-340 signature of the x-only group key.
+func (s *Suite) Verify(groupKey becc.PublicKey, msg []byte, sig Signature) bool {
+	if sig.z == nil || sig.r.IsInfinity() || sig.z.Sign() < 0 || sig.z.Cmp(s.ecc.Order()) >= 0 {
+		return false
+	}
+
+	if s.taproot {
+		return sig.r.Y().Bit(0) == 0 && groupKey.VerifySchnorr(msg, sig.SchnorrSignature())
+	}
+
+	c := s.challenge(sig.r, groupKey.Point(), msg)
+	expected := sig.r.Add(groupKey.Point().ScalarMul(c))
+
+	return s.ecc.Generator().ScalarMul(sig.z).Eq(expected)
+}
+
+// sortedCommitments returns a copy of the commitments sorted by identifier,
+// after checking that there are at least minSigners of them and that no
+// participant appears twice.
+func (s *Suite) sortedCommitments(commitments []Commitment, minSigners int) ([]Commitment, error) {
+	if len(commitments) < minSigners {
+		return nil, fmt.Errorf("%w: got %d signers, at least %d are needed", ErrInvalidCommitments, len(commitments), minSigners)
+	}
+
+	sorted := slices.Clone(commitments)
+	slices.SortFunc(sorted, func(a, b Commitment) int { return a.id.Cmp(b.id) })
+
+	for i, c := range sorted {
+		if c.id == nil {
+			return nil, fmt.Errorf("%w: missing identifier", ErrInvalidCommitments)
+		}
+
+		if i > 0 && sorted[i-1].id.Cmp(c.id) == 0 {
+			return nil, fmt.Errorf("%w: duplicate participant %s", ErrInvalidCommitments, c.id)
+		}
+	}
+
+	return sorted, nil
+}
+
+func findCommitment(commitments []Commitment, id *big.Int) (Commitment, bool) {
+	for _, c := range commitments {
+		if c.id.Cmp(id) == 0 {
+			return c, true
+		}
+	}
+
+	return Commitment{}, false
+}
+
+// bindingFactors returns the binding factor ρ_i of every signer, keyed by
+// the decimal representation of its identifier:
+// ρ_i = H1(P || H4(msg) || H5(encoded commitments) || i).
+func (s *Suite) bindingFactors(groupKey becc.Point, commitments []Commitment, msg []byte) map[string]*big.Int {
+	var encoded []byte
+	for _, c := range commitments {
+		encoded = slices.Concat(encoded, encodeScalar(s.ecc, c.id), encodePoint(s.ecc, c.hiding), encodePoint(s.ecc, c.binding))
+	}
+
+	prefix := slices.Concat(encodePoint(s.ecc, groupKey), s.hash("msg", msg), s.hash("com", encoded))
+
+	factors := make(map[string]*big.Int, len(commitments))
+	for _, c := range commitments {
+		factors[c.id.String()] = s.hashToScalar("rho", prefix, encodeScalar(s.ecc, c.id))
+	}
+
+	return factors
+}
+
+// groupCommitment returns R = Σ D_i + ρ_i·E_i.
+func (s *Suite) groupCommitment(commitments []Commitment, bindingFactors map[string]*big.Int) (becc.Point, error) {
+	r := s.ecc.Curve().Infinity()
+	for _, c := range commitments {
+		r = r.Add(c.hiding).Add(c.binding.ScalarMul(bindingFactors[c.id.String()]))
+	}
+
+	if r.IsInfinity() {
+		return becc.Point{}, fmt.Errorf("%w: the group commitment is the point at infinity", ErrInvalidCommitments)
+	}
+
+	return r, nil
+}
+
+// challenge returns c = H2(R || P || msg), or the BIP-340 challenge for the
+// NewTaproot suite.
+func (s *Suite) challenge(r, groupKey becc.Point, msg []byte) *big.Int {
+	if s.taproot {
+		size := s.ecc.CoordinateSize()
+		c := new(big.Int).SetBytes(becc.TaggedHash("BIP0340/challenge", r.X().FillBytes(make([]byte, size)), groupKey.X().FillBytes(make([]byte, size)), msg))
+
+		return c.Mod(c, s.ecc.Order())
+	}
+
+	return s.hashToScalar("chal", encodePoint(s.ecc, r), encodePoint(s.ecc, groupKey), msg)
+}
+
+// negations reports whether the signers negate their nonces and their key
+// shares: for the NewTaproot suite, when R and the group key have an odd y.
+func (s *Suite) negations(r, groupKey becc.Point) (bool, bool) {
+	if !s.taproot {
+		return false, false
+	}
+
+	return r.Y().Bit(0) == 1, groupKey.Y().Bit(0) == 1
+}
+
+// lagrangeCoefficient returns the Lagrange coefficient at 0 of the signer id
+// for the signers of commitments: λ_i = Π x_j / (x_j - x_i) for j != i.
+func (s *Suite) lagrangeCoefficient(commitments []Commitment, id *big.Int) *big.Int {
+	ids := make([]*big.Int, len(commitments))
+	for i, c := range commitments {
+		ids[i] = c.id
+	}
+
+	return shamir.LagrangeCoefficient(s.ecc.Order(), ids, id)
+}
+
+// randomNonce returns H3(random bytes || secret), which stays unpredictable
+// even if the random number generator is weak, as long as the secret is not
+// known.
+func (s *Suite) randomNonce(secret *big.Int) (*big.Int, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	return s.nonce(random, secret), nil
+}
+
+func (s *Suite) nonce(random []byte, secret *big.Int) *big.Int {
+	return s.hashToScalar("nonce", random, encodeScalar(s.ecc, secret))
+}
+
+// hashToScalar implements the hashes H1, H2, H3, HDKG and HID of the
+// ciphersuite: hash_to_field of RFC 9380 with expand_message_xmd and SHA-256
+// modulo the order n, with the domain separation tag contextString || tag.
+func (s *Suite) hashToScalar(tag string, msg ...[]byte) *big.Int {
+	uniform, err := becc.ExpandMessageXMD(becc.SHA256, slices.Concat(msg...), []byte(s.contextString+tag), scalarHashLength)
+	if err != nil {
+		// the DST and the length are always valid
+		panic(err)
+	}
+
+	h := new(big.Int).SetBytes(uniform)
+
+	return h.Mod(h, s.ecc.Order())
+}
+
+// hash implements the hashes H4 and H5 of the ciphersuite:
+// SHA-256(contextString || tag || msg).
+func (s *Suite) hash(tag string, msg []byte) []byte {
+	h := becc.SHA256()
+	h.Write([]byte(s.contextString + tag))
+	h.Write(msg)
+
+	return h.Sum(nil)
+}
+
+func (s *Suite) checkID(id *big.Int) error {
+	if id == nil || id.Sign() <= 0 || id.Cmp(s.ecc.Order()) >= 0 {
+		return fmt.Errorf("%w: participant identifiers must be in [1, n-1]", becc.ErrInvalidParameters)
+	}
+
+	return nil
+}
+
+func encodePoint(ecc *becc.ECC, p becc.Point) []byte {
+	encoded := make([]byte, 1+ecc.CoordinateSize())
+	encoded[0] = byte(2 + p.Y().Bit(0))
+	p.X().FillBytes(encoded[1:])
+
+	return encoded
+}
+
+func encodeScalar(ecc *becc.ECC, k *big.Int) []byte {
+	return k.FillBytes(make([]byte, ecc.ScalarSize()))
+}
@@ -0,0 +1,545 @@
+package frost
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/shamir"
+)
+
+func TestSignRFC9591(t *testing.T) {
+	// RFC 9591, appendix E.4, FROST(P-256, SHA-256), signers 1 and 3 of 3
+	suite := mustNew(t, becc.Secp256r1ECC())
+	ecc := suite.ECC()
+
+	coefficients := []*big.Int{
+		bigIntHex(t, "8ba9bba2e0fd8c4767154d35a0b7562244a4aaf6f36c8fb8735fa48b301bd8de"),
+		bigIntHex(t, "80f25e6c0709353e46bfbe882a11bdbb1f8097e46340eb8673b7e14556e6c3a4"),
+	}
+	f, err := shamir.NewPolynomial(ecc, coefficients)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commitment := VSSCommitment{commitments: f.Commitments()}
+
+	groupKey, err := commitment.GroupKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(groupKey.Compressed()); got != "023a309ad94e9fe8a7ba45dfc58f38bf091959d3c99cfbd02b4dc00585ec45ab70" {
+		t.Errorf("got group public key %s", got)
+	}
+
+	msg := hexBytes(t, "74657374")
+
+	signers := []struct {
+		id                    int64
+		share                 string
+		hidingRand            string
+		bindingRand           string
+		hidingNonce           string
+		bindingNonce          string
+		hidingNonceCommitment string
+		bindingFactor         string
+		sigShare              string
+	}{
+		{
+			id:                    1,
+			share:                 "0c9c1a0fe806c184add50bbdcac913dda73e482daf95dcb9f35dbb0d8a9f7731",
+			hidingRand:            "ec4c891c85fee802a9d757a67d1252e7f4e5efb8a538991ac18fbd0e06fb6fd3",
+			bindingRand:           "9334e29d09061223f69a09421715a347e4e6deba77444c8f42b0c833f80f4ef9",
+			hidingNonce:           "9f0542a5ba879a58f255c09f06da7102ef6a2dec6279700c656d58394d8facd4",
+			bindingNonce:          "6513dfe7429aa2fc972c69bb495b27118c45bbc6e654bb9dc9be55385b55c0d7",
+			hidingNonceCommitment: "0213b3e6298bf8ad46fd5e9389519a8665d63d98f4ec6a1fcca434e809d2d8070e",
+			bindingFactor:         "7925f0d4693f204e6e59233e92227c7124664a99739d2c06b81cf64ddf90559e",
+			sigShare:              "400308eaed7a2ddee02a265abe6a1cfe04d946ee8720768899619cfabe7a3aeb",
+		},
+		{
+			id:            3,
+			share:         "0e80d6e8f6192c003b5488ce1eec8f5429587d48cf001541e713b2d53c09d928",
+			hidingRand:    "c0451c5a0a5480d6c1f860e5db7d655233dca2669fd90ff048454b8ce983367b",
+			bindingRand:   "2ba5f7793ae700e40e78937a82f407dd35e847e33d1e607b5c7eb6ed2a8ed799",
+			hidingNonce:   "f73444a8972bcda9e506bbca3d2b1c083c10facdf4bb5d47fef7c2dc1d9f2a0d",
+			bindingNonce:  "44c6a29075d6e7e4f8b97796205f9e22062e7835141470afe9417fd317c1c303",
+			bindingFactor: "e10d24a8a403723bcb6f9bb4c537f316593683b472f7a89f166630dde11822c4",
+			sigShare:      "561da3c179edbb0502d941bb3e3ace3c37d122aaa46fb54499f15f3a3331de44",
+		},
+	}
+
+	shares := make([]KeyShare, len(signers))
+	nonces := make([]*Nonces, len(signers))
+	commitments := make([]Commitment, len(signers))
+
+	for i, s := range signers {
+		shares[i], err = suite.NewKeyShare(big.NewInt(s.id), bigIntHex(t, s.share), commitment, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		hiding := suite.nonce(hexBytes(t, s.hidingRand), shares[i].secret)
+		binding := suite.nonce(hexBytes(t, s.bindingRand), shares[i].secret)
+		if hiding.Cmp(bigIntHex(t, s.hidingNonce)) != 0 || binding.Cmp(bigIntHex(t, s.bindingNonce)) != 0 {
+			t.Errorf("P%d: got nonces %x and %x", s.id, hiding, binding)
+		}
+
+		nonces[i], commitments[i] = suite.commitWithNonces(shares[i], hiding, binding)
+
+		if s.hidingNonceCommitment != "" {
+			if got := hex.EncodeToString(encodePoint(ecc, commitments[i].hiding)); got != s.hidingNonceCommitment {
+				t.Errorf("P%d: got hiding nonce commitment %s", s.id, got)
+			}
+		}
+	}
+
+	bindingFactors := suite.bindingFactors(groupKey.Point(), commitments, msg)
+
+	sigShares := make([]SignatureShare, len(signers))
+	for i, s := range signers {
+		if got := bindingFactors[fmt.Sprint(s.id)]; got.Cmp(bigIntHex(t, s.bindingFactor)) != 0 {
+			t.Errorf("P%d: got binding factor %x", s.id, got)
+		}
+
+		sigShares[i], err = suite.Sign(shares[i], nonces[i], msg, commitments)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if sigShares[i].z.Cmp(bigIntHex(t, s.sigShare)) != 0 {
+			t.Errorf("P%d: got signature share %x", s.id, sigShares[i].z)
+		}
+
+		if !suite.VerifySignatureShare(shares[i].PublicKey(), groupKey, msg, commitments, sigShares[i]) {
+			t.Errorf("P%d: valid signature share rejected", s.id)
+		}
+	}
+
+	sig, err := suite.Aggregate(groupKey, msg, commitments, sigShares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "026d8d434874f87bdb7bc0dfd239b2c00639044f9dcb195e9a04426f70bfa4b70d9620acac6767e8e3e3036815fca4eb3a3caa69992b902bcd3352fc34f1ac192f"
+	if got := hex.EncodeToString(sig.Bytes()); got != expected {
+		t.Errorf("got signature %s, expected %s", got, expected)
+	}
+
+	if !suite.Verify(groupKey, msg, sig) {
+		t.Errorf("valid signature rejected")
+	}
+}
+
+func TestSignRFC9591Secp256k1(t *testing.T) {
+	// RFC 9591, appendix E.5, FROST(secp256k1, SHA-256), signers 1 and 3 of
+	// 3. The nonces of P3 are left out, so the signature is not recomputed:
+	// the nonces, binding factor and signature share of P1 are checked
+	// against the signature of the vector, and the signature against the
+	// group key.
+	suite := mustNew(t, becc.Secp256k1ECC())
+	ecc := suite.ECC()
+
+	coefficients := []*big.Int{
+		bigIntHex(t, "0d004150d27c3bf2a42f312683d35fac7394b1e9e318249c1bfe7f0795a83114"),
+		bigIntHex(t, "fbf85eadae3058ea14f19148bb72b45e4399c0b16028acaf0395c9b03c823579"),
+	}
+	f, err := shamir.NewPolynomial(ecc, coefficients)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commitment := VSSCommitment{commitments: f.Commitments()}
+
+	groupKey, err := commitment.GroupKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := hex.EncodeToString(groupKey.Compressed()); got != "02f37c34b66ced1fb51c34a90bdae006901f10625cc06c4f64663b0eae87d87b4f" {
+		t.Errorf("got group public key %s", got)
+	}
+
+	shares := make([]KeyShare, 3)
+	for i, share := range []string{
+		"08f89ffe80ac94dcb920c26f3f46140bfc7f95b493f8310f5fc1ea2b01f4254c",
+		"04f0feac2edcedc6ce1253b7fab8c86b856a797f44d83d82a385554e6e401984",
+		"00e95d59dd0d46b0e303e500b62b7ccb0e555d49f5b849f5e748c071da8c0dbc",
+	} {
+		shares[i], err = suite.NewKeyShare(big.NewInt(int64(i+1)), bigIntHex(t, share), commitment, 2)
+		if err != nil {
+			t.Fatalf("P%d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	msg := hexBytes(t, "74657374")
+
+	hiding := suite.nonce(hexBytes(t, "7ea5ed09af19f6ff21040c07ec2d2adbd35b759da5a401d4c99dd26b82391cb2"), shares[0].secret)
+	binding := suite.nonce(hexBytes(t, "47acab018f116020c10cb9b9abdc7ac10aae1b48ca6e36dc15acb6ec9be5cdc5"), shares[0].secret)
+	if hiding.Cmp(bigIntHex(t, "841d3a6450d7580b4da83c8e618414d0f024391f2aeb511d7579224420aa81f0")) != 0 ||
+		binding.Cmp(bigIntHex(t, "8d2624f532af631377f33cf44b5ac5f849067cae2eacb88680a31e77c79b5a80")) != 0 {
+		t.Errorf("P1: got nonces %x and %x", hiding, binding)
+	}
+
+	_, commitment1 := suite.commitWithNonces(shares[0], hiding, binding)
+	if got := hex.EncodeToString(encodePoint(ecc, commitment1.hiding)); got != "03c699af97d26bb4d3f05232ec5e1938c12f1e6ae97643c8f8f11c9820303f1904" {
+		t.Errorf("P1: got hiding nonce commitment %s", got)
+	}
+
+	if got := hex.EncodeToString(encodePoint(ecc, commitment1.binding)); got != "02fa2aaccd51b948c9dc1a325d77226e98a5a3fe65fe9ba213761a60123040a45e" {
+		t.Errorf("P1: got binding nonce commitment %s", got)
+	}
+
+	sig, err := suite.ParseSignature(hexBytes(t, "0205b6d04d3774c8929413e3c76024d54149c372d57aae62574ed74319b5ea14d0c65dde8492a7471437e6c2fe3da49b90d23f642b5c6dbe7e36089f096dd97324"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !suite.Verify(groupKey, msg, sig) {
+		t.Errorf("valid signature rejected")
+	}
+
+	// z1 = d1 + e1·ρ1 + λ1·s1·c
+	n := ecc.Order()
+	bindingFactor := bigIntHex(t, "3e08fe561e075c653cbfd46908a10e7637c70c74f0a77d5fd45d1a750c739ec6")
+	lambda := shamir.LagrangeCoefficient(n, []*big.Int{big.NewInt(1), big.NewInt(3)}, big.NewInt(1))
+	c := suite.challenge(sig.r, groupKey.Point(), msg)
+
+	z1 := new(big.Int).Mul(binding, bindingFactor)
+	z1.Add(z1, hiding)
+	z1.Add(z1, new(big.Int).Mul(new(big.Int).Mul(lambda, shares[0].secret), c))
+	z1.Mod(z1, n)
+
+	if z1.Cmp(bigIntHex(t, "c4fce1775a1e141fb579944166eab0d65eefe7b98d480a569bbbfcb14f91c197")) != 0 {
+		t.Errorf("P1: got signature share %x", z1)
+	}
+
+	z := new(big.Int).Add(z1, bigIntHex(t, "0160fd0d388932f4826d2ebcd6b9eaba734f7c71cf25b4279a4ca2581e47b18d"))
+	if z.Mod(z, n).Cmp(sig.z) != 0 {
+		t.Errorf("the signature shares add up to %x, expected %x", z, sig.z)
+	}
+}
+
+func TestSign(t *testing.T) {
+	for _, ecc := range []*becc.ECC{becc.Secp256k1ECC(), becc.Secp256r1ECC()} {
+		t.Run(ecc.Name(), func(t *testing.T) {
+			suite := mustNew(t, ecc)
+
+			groupPriv, groupPub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			shares, _, err := suite.TrustedDealerKeygen(groupPriv, 5, 3)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			msg := []byte("transfer 10 coins")
+
+			for _, signers := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 2, 3, 4}} {
+				t.Run(fmt.Sprint(signers), func(t *testing.T) {
+					sig, err := simulateSigning(suite, shares, signers, msg)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+
+					if !suite.Verify(groupPub, msg, sig) {
+						t.Fatalf("valid signature rejected")
+					}
+
+					parsed, err := suite.ParseSignature(sig.Bytes())
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+
+					if !suite.Verify(groupPub, msg, parsed) {
+						t.Errorf("parsed signature rejected")
+					}
+
+					if suite.Verify(groupPub, []byte("transfer 11 coins"), sig) {
+						t.Errorf("signature accepted for another message")
+					}
+				})
+			}
+
+			t.Run("below threshold", func(t *testing.T) {
+				if _, err := simulateSigning(suite, shares, []int{0, 1}, msg); !errors.Is(err, ErrInvalidCommitments) {
+					t.Errorf("got %v, expected ErrInvalidCommitments", err)
+				}
+			})
+		})
+	}
+}
+
+func TestSignTaproot(t *testing.T) {
+	suite, err := NewTaproot(becc.Secp256k1ECC())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := []byte("transfer 10 coins")
+
+	priv, _, err := suite.ECC().GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	negated, err := suite.ECC().NewPrivateKey(new(big.Int).Sub(suite.ECC().Order(), new(big.Int).SetBytes(priv.Bytes())))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a key and its negation give group keys with both parities of y, and a
+	// few signings commitments R with both parities
+	for _, groupPriv := range []becc.PrivateKey{priv, negated} {
+		groupPub := groupPriv.PublicKey()
+
+		t.Run(fmt.Sprintf("y parity %d", groupPub.Point().Y().Bit(0)), func(t *testing.T) {
+			shares, _, err := suite.TrustedDealerKeygen(groupPriv, 3, 2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, signers := range [][]int{{0, 1}, {2, 0}, {1, 2}} {
+				sig, err := simulateSigning(suite, shares, signers, msg)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !suite.Verify(groupPub, msg, sig) {
+					t.Fatalf("%v: valid signature rejected", signers)
+				}
+
+				// the ordinary BIP-340 verifier accepts the aggregated signature
+				schnorrSig, err := becc.ParseSchnorrSignature(sig.SchnorrSignature().Bytes())
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !groupPub.VerifySchnorr(msg, schnorrSig) {
+					t.Errorf("%v: BIP-340 verification failed", signers)
+				}
+
+				if groupPub.VerifySchnorr([]byte("transfer 11 coins"), schnorrSig) {
+					t.Errorf("%v: signature accepted for another message", signers)
+				}
+			}
+		})
+	}
+
+	t.Run("signature shares", func(t *testing.T) {
+		groupPriv, groupPub, err := suite.ECC().GenKeyPair()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		shares, _, err := suite.TrustedDealerKeygen(groupPriv, 2, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for range 2 {
+			nonces := make([]*Nonces, 2)
+			commitments := make([]Commitment, 2)
+			for i := range shares {
+				if nonces[i], commitments[i], err = suite.Commit(shares[i]); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			for i := range shares {
+				share, err := suite.Sign(shares[i], nonces[i], msg, commitments)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !suite.VerifySignatureShare(shares[i].PublicKey(), groupPub, msg, commitments, share) {
+					t.Errorf("valid signature share rejected")
+				}
+			}
+		}
+	})
+
+	t.Run("RFC 9591 suite", func(t *testing.T) {
+		// the challenge of FROST-secp256k1-SHA256-v1 is not the BIP-340 one
+		rfcSuite := mustNew(t, becc.Secp256k1ECC())
+
+		groupPriv, groupPub, err := rfcSuite.ECC().GenKeyPair()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		shares, _, err := rfcSuite.TrustedDealerKeygen(groupPriv, 2, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		sig, err := simulateSigning(rfcSuite, shares, []int{0, 1}, msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if groupPub.VerifySchnorr(msg, sig.SchnorrSignature()) {
+			t.Errorf("RFC 9591 signature accepted by BIP-340")
+		}
+	})
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		if _, err := NewTaproot(becc.Secp256r1ECC()); !errors.Is(err, becc.ErrUnsupportedCurve) {
+			t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+		}
+	})
+}
+
+func TestSignMisbehavior(t *testing.T) {
+	suite := mustNew(t, becc.Secp256k1ECC())
+	ecc := suite.ECC()
+
+	groupPriv, groupPub, err := ecc.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, _, err := suite.TrustedDealerKeygen(groupPriv, 3, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msg := []byte("msg")
+
+	nonces1, commitment1, err := suite.Commit(shares[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nonces2, commitment2, err := suite.Commit(shares[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	commitments := []Commitment{commitment1, commitment2}
+
+	share1, err := suite.Sign(shares[0], nonces1, msg, commitments)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("nonce reuse", func(t *testing.T) {
+		if _, err := suite.Sign(shares[0], nonces1, []byte("other"), commitments); err == nil {
+			t.Errorf("nonces used twice")
+		}
+	})
+
+	t.Run("bad share", func(t *testing.T) {
+		share2, err := suite.Sign(shares[1], nonces2, msg, commitments)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		bad, err := suite.NewSignatureShare(share2.ID(), new(big.Int).Add(share2.Z(), big.NewInt(1)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if suite.VerifySignatureShare(shares[1].PublicKey(), groupPub, msg, commitments, bad) {
+			t.Errorf("bad signature share accepted")
+		}
+
+		if !suite.VerifySignatureShare(shares[0].PublicKey(), groupPub, msg, commitments, share1) {
+			t.Errorf("valid signature share rejected")
+		}
+
+		sig, err := suite.Aggregate(groupPub, msg, commitments, []SignatureShare{share1, bad})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if suite.Verify(groupPub, msg, sig) {
+			t.Errorf("signature with a bad share accepted")
+		}
+	})
+
+	t.Run("wrong commitment", func(t *testing.T) {
+		nonces, _, err := suite.Commit(shares[2])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// participant 3 signs with nonces that do not match its commitment
+		_, other, err := suite.Commit(shares[2])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := suite.Sign(shares[2], nonces, msg, []Commitment{commitment1, other}); !errors.Is(err, ErrInvalidCommitments) {
+			t.Errorf("got %v, expected ErrInvalidCommitments", err)
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(becc.Secp384r1ECC()); !errors.Is(err, becc.ErrUnsupportedCurve) {
+		t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+	}
+}
+
+// simulateSigning runs the two rounds of signing in-process for the
+// participants with the given indexes into shares.
+func simulateSigning(suite *Suite, shares []KeyShare, signers []int, msg []byte) (Signature, error) {
+	nonces := make([]*Nonces, len(signers))
+	commitments := make([]Commitment, len(signers))
+
+	for i, j := range signers {
+		var err error
+		nonces[i], commitments[i], err = suite.Commit(shares[j])
+		if err != nil {
+			return Signature{}, err
+		}
+	}
+
+	sigShares := make([]SignatureShare, len(signers))
+	for i, j := range signers {
+		var err error
+		sigShares[i], err = suite.Sign(shares[j], nonces[i], msg, commitments)
+		if err != nil {
+			return Signature{}, err
+		}
+	}
+
+	return suite.Aggregate(shares[signers[0]].GroupKey(), msg, commitments, sigShares)
+}
+
+func mustNew(t *testing.T, ecc *becc.ECC) *Suite {
+	t.Helper()
+
+	suite, err := New(ecc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return suite
+}
+
+func bigIntHex(t *testing.T, s string) *big.Int {
+	t.Helper()
+
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		t.Fatalf("invalid hex integer %q", s)
+	}
+
+	return n
+}
+
+func hexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex string %q: %v", s, err)
+	}
+
+	return b
+}
@@ -0,0 +1,354 @@
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/shamir"
+)
+
+var ErrInvalidShare error = errors.New("invalid share")
+
+// KeyShare is the share s_i = f(i) of the group signing key held by the
+// participant i, where f is a secret polynomial of degree minSigners - 1 with
+// f(0) the group private key.
+type KeyShare struct {
+	id, secret *big.Int
+	publicKey  becc.Point
+	groupKey   becc.PublicKey
+	minSigners int
+}
+
+// ID returns the identifier of the participant.
+func (k KeyShare) ID() *big.Int {
+	return new(big.Int).Set(k.id)
+}
+
+// Secret returns the secret share s_i.
+func (k KeyShare) Secret() *big.Int {
+	return new(big.Int).Set(k.secret)
+}
+
+// PublicKey returns the public key P_i = s_i·G of the participant, with which
+// the others check its signature shares.
+func (k KeyShare) PublicKey() becc.Point {
+	return k.publicKey
+}
+
+// GroupKey returns the group public key P = f(0)·G.
+func (k KeyShare) GroupKey() becc.PublicKey {
+	return k.groupKey
+}
+
+// MinSigners returns the number of participants needed to sign.
+func (k KeyShare) MinSigners() int {
+	return k.minSigners
+}
+
+// VSSCommitment is the Feldman commitment a_0·G, ..., a_(t-1)·G to the
+// coefficients of the polynomial f of the key shares. It is public, and lets
+// every participant check its share and compute the public key of the others.
+type VSSCommitment struct {
+	commitments shamir.Commitments
+}
+
+// Points returns the commitments to the coefficients, from a_0·G up.
+func (c VSSCommitment) Points() []becc.Point {
+	return c.commitments.Points()
+}
+
+// GroupKey returns the group public key a_0·G.
+func (c VSSCommitment) GroupKey() (becc.PublicKey, error) {
+	return c.commitments.PublicKey()
+}
+
+// ParticipantKey returns the public key f(id)·G of the participant id,
+// computed from the commitment as Σ id^j·(a_j·G).
+func (c VSSCommitment) ParticipantKey(id *big.Int) becc.Point {
+	return c.commitments.Evaluate(id)
+}
+
+// TrustedDealerKeygen splits the group private key into maxSigners shares,
+// for the participants 1 to maxSigners, any minSigners of which can sign. The
+// dealer learns the key and must be trusted to erase it and the shares.
+func (s *Suite) TrustedDealerKeygen(groupKey becc.PrivateKey, maxSigners, minSigners int) ([]KeyShare, VSSCommitment, error) {
+	if err := checkThreshold(maxSigners, minSigners); err != nil {
+		return nil, VSSCommitment{}, err
+	}
+
+	f, err := shamir.RandomPolynomial(s.ecc, groupKey.Int(), minSigners)
+	if err != nil {
+		return nil, VSSCommitment{}, err
+	}
+
+	commitment := VSSCommitment{commitments: f.Commitments()}
+
+	shares := make([]KeyShare, maxSigners)
+	for i := range shares {
+		id := big.NewInt(int64(i + 1))
+
+		shares[i], err = s.NewKeyShare(id, f.Evaluate(id), commitment, minSigners)
+		if err != nil {
+			return nil, VSSCommitment{}, err
+		}
+	}
+
+	return shares, commitment, nil
+}
+
+// NewKeyShare returns the key share of the participant id with the secret
+// share s_i, after checking it against the VSS commitment.
+func (s *Suite) NewKeyShare(id, secret *big.Int, commitment VSSCommitment, minSigners int) (KeyShare, error) {
+	if err := s.checkID(id); err != nil {
+		return KeyShare{}, err
+	}
+
+	if commitment.commitments.Threshold() != minSigners {
+		return KeyShare{}, fmt.Errorf("%w: the commitment has %d coefficients, expected %d", ErrInvalidShare, commitment.commitments.Threshold(), minSigners)
+	}
+
+	if secret.Sign() <= 0 || secret.Cmp(s.ecc.Order()) >= 0 || !commitment.commitments.VerifyValue(id, secret) {
+		return KeyShare{}, fmt.Errorf("%w: the share of participant %s does not match the commitment", ErrInvalidShare, id)
+	}
+
+	groupKey, err := commitment.GroupKey()
+	if err != nil {
+		return KeyShare{}, fmt.Errorf("%w: %w", ErrInvalidShare, err)
+	}
+
+	return KeyShare{
+		id:         new(big.Int).Set(id),
+		secret:     new(big.Int).Set(secret),
+		publicKey:  commitment.ParticipantKey(id),
+		groupKey:   groupKey,
+		minSigners: minSigners,
+	}, nil
+}
+
+// DKG is the state of one participant in the distributed key generation of
+// the FROST paper (Komlo and Goldberg, 2020), where every participant deals a
+// random secret with Feldman's VSS and the group key is the sum of them all,
+// so that nobody ever learns it:
+//
+//  1. NewDKG returns the DKGRound1Package to broadcast: the VSS commitment to
+//     the polynomial of the participant and a Schnorr proof of knowledge of
+//     its secret, against rogue-key attacks
+//  2. Round2 checks the packages of the others and returns the secret shares
+//     to send to each of them over confidential channels
+//  3. Finalize checks the received shares against the commitments and
+//     returns the key share of the participant and the group VSS commitment
+type DKG struct {
+	suite                  *Suite
+	id                     *big.Int
+	maxSigners, minSigners int
+	polynomial             shamir.Polynomial
+	commitments            map[string]VSSCommitment
+}
+
+// DKGRound1Package is the message broadcast by a participant in the first
+// round of the DKG.
+type DKGRound1Package struct {
+	id         *big.Int
+	commitment VSSCommitment
+	proofR     becc.Point
+	proofZ     *big.Int
+}
+
+// ID returns the identifier of the sender.
+func (p DKGRound1Package) ID() *big.Int {
+	return new(big.Int).Set(p.id)
+}
+
+// DKGRound2Package is the secret share f_from(to) sent by one participant to
+// another in the second round of the DKG.
+type DKGRound2Package struct {
+	from, to *big.Int
+	share    *big.Int
+}
+
+// From returns the identifier of the sender.
+func (p DKGRound2Package) From() *big.Int {
+	return new(big.Int).Set(p.from)
+}
+
+// To returns the identifier of the recipient.
+func (p DKGRound2Package) To() *big.Int {
+	return new(big.Int).Set(p.to)
+}
+
+// NewDKG starts the DKG for the participant id, out of maxSigners
+// participants with the identifiers 1 to maxSigners, and returns the package
+// it broadcasts in the first round.
+func (s *Suite) NewDKG(id *big.Int, maxSigners, minSigners int) (*DKG, DKGRound1Package, error) {
+	if err := checkThreshold(maxSigners, minSigners); err != nil {
+		return nil, DKGRound1Package{}, err
+	}
+
+	if err := s.checkID(id); err != nil {
+		return nil, DKGRound1Package{}, err
+	}
+
+	if id.Cmp(big.NewInt(int64(maxSigners))) > 0 {
+		return nil, DKGRound1Package{}, fmt.Errorf("%w: participant identifiers must be at most %d", becc.ErrInvalidParameters, maxSigners)
+	}
+
+	secret, err := s.randomScalar()
+	if err != nil {
+		return nil, DKGRound1Package{}, err
+	}
+
+	f, err := shamir.RandomPolynomial(s.ecc, secret, minSigners)
+	if err != nil {
+		return nil, DKGRound1Package{}, err
+	}
+
+	commitment := VSSCommitment{commitments: f.Commitments()}
+
+	// Schnorr proof of knowledge of a_0: R = k·G, z = k + a_0·c with
+	// c = HDKG(id || a_0·G || R)
+	k, err := s.randomScalar()
+	if err != nil {
+		return nil, DKGRound1Package{}, err
+	}
+
+	r := s.ecc.Generator().ScalarMul(k)
+	c := s.dkgChallenge(id, s.ecc.Generator().ScalarMul(secret), r)
+
+	z := new(big.Int).Mul(c, secret)
+	z.Add(z, k).Mod(z, s.ecc.Order())
+
+	dkg := &DKG{
+		suite:      s,
+		id:         new(big.Int).Set(id),
+		maxSigners: maxSigners,
+		minSigners: minSigners,
+		polynomial: f,
+	}
+
+	return dkg, DKGRound1Package{id: dkg.id, commitment: commitment, proofR: r, proofZ: z}, nil
+}
+
+// Round2 checks the first round packages of the other participants and
+// returns the secret share for each of them.
+func (d *DKG) Round2(round1 []DKGRound1Package) ([]DKGRound2Package, error) {
+	s := d.suite
+
+	if len(round1) != d.maxSigners-1 {
+		return nil, fmt.Errorf("%w: got %d packages, expected %d", ErrInvalidShare, len(round1), d.maxSigners-1)
+	}
+
+	commitments := map[string]VSSCommitment{}
+	packages := make([]DKGRound2Package, 0, len(round1))
+
+	for _, p := range round1 {
+		if s.checkID(p.id) != nil || p.id.Cmp(big.NewInt(int64(d.maxSigners))) > 0 || p.id.Cmp(d.id) == 0 {
+			return nil, fmt.Errorf("%w: unexpected package of participant %s", ErrInvalidShare, p.id)
+		}
+		if _, ok := commitments[p.id.String()]; ok {
+			return nil, fmt.Errorf("%w: duplicate package of participant %s", ErrInvalidShare, p.id)
+		}
+
+		points := p.commitment.Points()
+		if len(points) != d.minSigners {
+			return nil, fmt.Errorf("%w: the commitment of participant %s has %d coefficients, expected %d", ErrInvalidShare, p.id, len(points), d.minSigners)
+		}
+
+		for _, point := range points {
+			if err := s.ecc.ValidatePublicKey(point); err != nil {
+				return nil, fmt.Errorf("%w: the commitment of participant %s: %w", ErrInvalidShare, p.id, err)
+			}
+		}
+
+		// z·G = R + c·(a_0·G)
+		c := s.dkgChallenge(p.id, points[0], p.proofR)
+		expected := p.proofR.Add(points[0].ScalarMul(c))
+		if p.proofZ == nil || !s.ecc.Generator().ScalarMul(p.proofZ).Eq(expected) {
+			return nil, fmt.Errorf("%w: invalid proof of knowledge of participant %s", ErrInvalidShare, p.id)
+		}
+
+		commitments[p.id.String()] = p.commitment
+		packages = append(packages, DKGRound2Package{
+			from:  d.id,
+			to:    new(big.Int).Set(p.id),
+			share: d.polynomial.Evaluate(p.id),
+		})
+	}
+
+	d.commitments = commitments
+
+	return packages, nil
+}
+
+// Finalize checks the secret shares sent by the other participants and
+// returns the key share of this participant and the group VSS commitment,
+// the sum of the commitments of all the participants.
+func (d *DKG) Finalize(round2 []DKGRound2Package) (KeyShare, VSSCommitment, error) {
+	s := d.suite
+
+	if d.commitments == nil {
+		return KeyShare{}, VSSCommitment{}, errors.New("Round2 must be called before Finalize")
+	}
+
+	if len(round2) != d.maxSigners-1 {
+		return KeyShare{}, VSSCommitment{}, fmt.Errorf("%w: got %d shares, expected %d", ErrInvalidShare, len(round2), d.maxSigners-1)
+	}
+
+	secret := d.polynomial.Evaluate(d.id)
+	seen := map[string]bool{}
+
+	for _, p := range round2 {
+		if p.to.Cmp(d.id) != 0 || seen[p.from.String()] {
+			return KeyShare{}, VSSCommitment{}, fmt.Errorf("%w: unexpected share from participant %s", ErrInvalidShare, p.from)
+		}
+		seen[p.from.String()] = true
+
+		commitment, ok := d.commitments[p.from.String()]
+		if !ok || !commitment.commitments.VerifyValue(d.id, p.share) {
+			return KeyShare{}, VSSCommitment{}, fmt.Errorf("%w: the share from participant %s does not match its commitment", ErrInvalidShare, p.from)
+		}
+
+		secret.Add(secret, p.share)
+	}
+	secret.Mod(secret, s.ecc.Order())
+
+	sum := d.polynomial.Commitments()
+	for _, c := range d.commitments {
+		var err error
+		if sum, err = sum.Add(c.commitments); err != nil {
+			return KeyShare{}, VSSCommitment{}, fmt.Errorf("%w: %w", ErrInvalidShare, err)
+		}
+	}
+	groupCommitment := VSSCommitment{commitments: sum}
+
+	share, err := s.NewKeyShare(d.id, secret, groupCommitment, d.minSigners)
+	if err != nil {
+		return KeyShare{}, VSSCommitment{}, err
+	}
+
+	return share, groupCommitment, nil
+}
+
+func (s *Suite) dkgChallenge(id *big.Int, a0, r becc.Point) *big.Int {
+	return s.hashToScalar("dkg", encodeScalar(s.ecc, id), encodePoint(s.ecc, a0), encodePoint(s.ecc, r))
+}
+
+func checkThreshold(maxSigners, minSigners int) error {
+	if minSigners < 2 || maxSigners < minSigners {
+		return fmt.Errorf("%w: the threshold must satisfy 2 <= minSigners <= maxSigners", becc.ErrInvalidParameters)
+	}
+
+	return nil
+}
+
+// randomScalar returns a uniformly random scalar in [1, n-1].
+func (s *Suite) randomScalar() (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(s.ecc.Order(), big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+
+	return k.Add(k, big.NewInt(1)), nil
+}
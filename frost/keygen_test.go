@@ -0,0 +1,190 @@
+package frost
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/shamir"
+)
+
+func TestTrustedDealerKeygen(t *testing.T) {
+	suite := mustNew(t, becc.Secp256k1ECC())
+	ecc := suite.ECC()
+
+	groupPriv, groupPub, err := ecc.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, commitment, err := suite.TrustedDealerKeygen(groupPriv, 5, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(shares) != 5 || len(commitment.Points()) != 3 {
+		t.Fatalf("got %d shares and %d commitments, expected 5 and 3", len(shares), len(commitment.Points()))
+	}
+
+	for _, share := range shares {
+		if !share.GroupKey().Point().Eq(groupPub.Point()) {
+			t.Errorf("participant %s: got a different group key", share.ID())
+		}
+
+		if !commitment.ParticipantKey(share.ID()).Eq(ecc.Generator().ScalarMul(share.Secret())) {
+			t.Errorf("participant %s: public key does not match the share", share.ID())
+		}
+	}
+
+	// any three shares interpolate to the group key
+	ids := []*big.Int{shares[1].ID(), shares[3].ID(), shares[4].ID()}
+	secret := new(big.Int)
+	for _, i := range []int{1, 3, 4} {
+		lambda := shamir.LagrangeCoefficient(ecc.Order(), ids, shares[i].ID())
+		secret.Add(secret, lambda.Mul(lambda, shares[i].Secret()))
+	}
+	secret.Mod(secret, ecc.Order())
+
+	if secret.Cmp(groupPriv.Int()) != 0 {
+		t.Errorf("the shares do not interpolate to the group key")
+	}
+
+	t.Run("tampered share", func(t *testing.T) {
+		tampered := new(big.Int).Add(shares[0].Secret(), big.NewInt(1))
+		if _, err := suite.NewKeyShare(shares[0].ID(), tampered, commitment, 3); !errors.Is(err, ErrInvalidShare) {
+			t.Errorf("got %v, expected ErrInvalidShare", err)
+		}
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		for _, tc := range [][2]int{{3, 4}, {3, 1}} {
+			if _, _, err := suite.TrustedDealerKeygen(groupPriv, tc[0], tc[1]); !errors.Is(err, becc.ErrInvalidParameters) {
+				t.Errorf("%d of %d: got %v, expected ErrInvalidParameters", tc[1], tc[0], err)
+			}
+		}
+	})
+}
+
+func TestDKG(t *testing.T) {
+	for _, ecc := range []*becc.ECC{becc.Secp256k1ECC(), becc.Secp256r1ECC()} {
+		t.Run(ecc.Name(), func(t *testing.T) {
+			suite := mustNew(t, ecc)
+
+			shares, commitments, err := simulateDKG(suite, 4, 3, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			groupKey := shares[0].GroupKey()
+			for i, share := range shares {
+				if !share.GroupKey().Point().Eq(groupKey.Point()) {
+					t.Errorf("participant %d: got a different group key", i+1)
+				}
+
+				for j, other := range shares {
+					if !commitments[i].ParticipantKey(other.ID()).Eq(other.PublicKey()) {
+						t.Errorf("participant %d computes a wrong public key for participant %d", i+1, j+1)
+					}
+				}
+			}
+
+			msg := []byte("elect leader")
+
+			sig, err := simulateSigning(suite, shares, []int{3, 1, 0}, msg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !suite.Verify(groupKey, msg, sig) {
+				t.Errorf("valid signature rejected")
+			}
+		})
+	}
+
+	suite := mustNew(t, becc.Secp256k1ECC())
+
+	t.Run("tampered share", func(t *testing.T) {
+		_, _, err := simulateDKG(suite, 3, 2, func(round1 []DKGRound1Package, round2 [][]DKGRound2Package) {
+			if round2 != nil {
+				round2[0][1].share = new(big.Int).Add(round2[0][1].share, big.NewInt(1))
+			}
+		})
+		if !errors.Is(err, ErrInvalidShare) {
+			t.Errorf("got %v, expected ErrInvalidShare", err)
+		}
+	})
+
+	t.Run("invalid proof of knowledge", func(t *testing.T) {
+		_, _, err := simulateDKG(suite, 3, 2, func(round1 []DKGRound1Package, round2 [][]DKGRound2Package) {
+			if round2 == nil {
+				round1[2].proofZ = new(big.Int).Add(round1[2].proofZ, big.NewInt(1))
+			}
+		})
+		if !errors.Is(err, ErrInvalidShare) {
+			t.Errorf("got %v, expected ErrInvalidShare", err)
+		}
+	})
+}
+
+// simulateDKG runs the DKG in-process for the participants 1 to maxSigners.
+// tamper, if not nil, is called after each round with the packages sent so
+// far: round2 is nil after the first round, and round2[i] holds the packages
+// sent by participant i + 1 after the second.
+func simulateDKG(suite *Suite, maxSigners, minSigners int, tamper func([]DKGRound1Package, [][]DKGRound2Package)) ([]KeyShare, []VSSCommitment, error) {
+	participants := make([]*DKG, maxSigners)
+	round1 := make([]DKGRound1Package, maxSigners)
+
+	for i := range participants {
+		var err error
+		participants[i], round1[i], err = suite.NewDKG(big.NewInt(int64(i+1)), maxSigners, minSigners)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if tamper != nil {
+		tamper(round1, nil)
+	}
+
+	round2 := make([][]DKGRound2Package, maxSigners)
+	for i, p := range participants {
+		var others []DKGRound1Package
+		for j, pkg := range round1 {
+			if j != i {
+				others = append(others, pkg)
+			}
+		}
+
+		var err error
+		round2[i], err = p.Round2(others)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if tamper != nil {
+		tamper(round1, round2)
+	}
+
+	shares := make([]KeyShare, maxSigners)
+	commitments := make([]VSSCommitment, maxSigners)
+	for i, p := range participants {
+		var received []DKGRound2Package
+		for _, sent := range round2 {
+			for _, pkg := range sent {
+				if pkg.To().Cmp(p.id) == 0 {
+					received = append(received, pkg)
+				}
+			}
+		}
+
+		var err error
+		shares[i], commitments[i], err = p.Finalize(received)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return shares, commitments, nil
+}
@@ -0,0 +1,190 @@
+package becc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// minGeneratedCurveBits is the smallest field size accepted by GenerateCurve:
+// the order of smaller curves cannot reach the rho security that AuditCurve
+// requires.
+const minGeneratedCurveBits = 2 * minAuditSecurity
+
+// GenerateCurve returns a random curve y^2 = x^3 - 3x + b over a prime field
+// of the given size in bits, with h·n points for a prime n and a cofactor
+// h <= maxCofactor, that passes the checks of AuditCurve. Sizes below 200
+// bits are rejected with ErrInvalidParameters, since no curve of that size
+// can pass them.
+//
+// The curve is verifiably random in the style of ANSI X9.62 (with SHA-256
+// instead of SHA-1): every value is derived from the seed, so anyone can
+// reproduce the curve and check that it was not chosen for a hidden weakness.
+// With H(label, i) the first bits bits of SHA-256(seed || label || i || j)
+// for j = 0, 1, ... (i and j as 32-bit big-endian integers):
+//
+//   - p is the first H("p", i) that is prime after setting its top bit and
+//     its two low bits, so that p = 3 mod 4
+//   - b is the smaller square root of -27/r for the first r = H("b", c) mod p
+//     that gives a suitable curve, so that b^2·r = a^3 as in X9.62
+//   - G is h·P for the first point P with x = H("g", k) mod p and the smaller
+//     y, skipping the points with h·P = ∞
+//
+// The points of each candidate curve are counted with the SEA algorithm of
+// Order, which is abandoned as soon as a prime larger than maxCofactor
+// divides the count, or the small factors of the twist rule out the audit,
+// so most candidates are discarded in milliseconds. A 256-bit curve takes a
+// few hundred candidates and about ten minutes on average, depending on the
+// seed.
+func GenerateCurve(bits int, seed []byte, maxCofactor int64) (*ECC, error) {
+	if bits < minGeneratedCurveBits {
+		return nil, fmt.Errorf("%w: curves must have at least %d bits to pass AuditCurve", ErrInvalidParameters, minGeneratedCurveBits)
+	}
+
+	return generateCurve(bits, seed, maxCofactor, minAuditSecurity)
+}
+
+// generateCurve implements GenerateCurve with minSecurity as the minimum rho
+// security of the curve and its twist, which lets the tests generate small
+// curves.
+func generateCurve(bits int, seed []byte, maxCofactor int64, minSecurity int) (*ECC, error) {
+	if maxCofactor < 1 {
+		return nil, fmt.Errorf("%w: the maximum cofactor must be positive", ErrInvalidParameters)
+	}
+
+	p := generatePrime(bits, seed)
+	a := big.NewInt(-3)
+
+	// a^3 = -27
+	minus27 := new(big.Int).Sub(p, big.NewInt(27))
+
+	// every candidate curve is over F_p
+	modular := newModularPolynomials(p)
+
+	// the twist has at most p + 1 + 2 sqrt(p) points, and its largest prime
+	// factor needs 2·minSecurity bits
+	twistMax := new(big.Int).Sqrt(new(big.Int).Lsh(p, 2))
+	twistMax.Add(twistMax, p).Add(twistMax, bi1)
+	minTwistFactor := new(big.Int).Lsh(bi1, uint(max(2*minSecurity-1, 0)))
+
+	for c := uint32(0); ; c++ {
+		r := seedInt(seed, "b", c, bits)
+		r.Mod(r, p)
+
+		rInv := new(big.Int).ModInverse(r, p)
+		if rInv == nil {
+			continue
+		}
+
+		b := new(big.Int).ModSqrt(rInv.Mul(rInv, minus27), p)
+		if b == nil {
+			continue
+		}
+
+		if other := new(big.Int).Sub(p, b); other.Cmp(b) < 0 {
+			b = other
+		}
+
+		ec, err := NewEllipticCurve(a, b, p)
+		if err != nil {
+			continue
+		}
+
+		// order finds #E modulo small primes first: give up as soon as a
+		// prime larger than the cofactor divides it, or the small primes
+		// dividing the twist leave no room for a large enough factor
+		twistBound := new(big.Int).Set(twistMax)
+		pointCount, err := ec.order(modular, func(l, pointCountMod int64) bool {
+			if pointCountMod == 0 && l > maxCofactor {
+				return true
+			}
+
+			// #E' = 2p + 2 - #E
+			twistMod := new(big.Int).Add(p, bi1)
+			twistMod.Lsh(twistMod, 1).Sub(twistMod, big.NewInt(pointCountMod))
+			if twistMod.Mod(twistMod, big.NewInt(l)).Sign() == 0 {
+				twistBound.Div(twistBound, big.NewInt(l))
+			}
+
+			return twistBound.Cmp(minTwistFactor) < 0
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if pointCount == nil {
+			continue
+		}
+
+		report, err := auditCurve(a, b, p, pointCount, minSecurity)
+		if err != nil {
+			return nil, err
+		}
+
+		if !report.Safe() || report.Cofactor.Cmp(big.NewInt(maxCofactor)) > 0 {
+			continue
+		}
+
+		g := ec.generatorFromSeed(seed, bits, report.Cofactor)
+
+		e, err := NewECC(ec, g, report.Order, report.Cofactor)
+		if err != nil {
+			continue
+		}
+		e.seed = bytes.Clone(seed)
+
+		return e, nil
+	}
+}
+
+// generatorFromSeed returns h·P for the first point P derived from the seed
+// with h·P != ∞.
+func (ec EllipticCurve) generatorFromSeed(seed []byte, bits int, cofactor *big.Int) Point {
+	for k := uint32(0); ; k++ {
+		x := seedInt(seed, "g", k, bits)
+		x.Mod(x, ec.m)
+
+		ys := ec.Y(x)
+		if len(ys) == 0 {
+			continue
+		}
+
+		g := ec.NewPoint(x, ys[0]).ScalarMul(cofactor)
+		if !g.IsInfinity() {
+			return g
+		}
+	}
+}
+
+// generatePrime returns the first prime p = 3 mod 4 of the given size derived
+// from the seed.
+func generatePrime(bits int, seed []byte) *big.Int {
+	for i := uint32(0); ; i++ {
+		p := seedInt(seed, "p", i, bits)
+		p.SetBit(p, bits-1, 1).SetBit(p, 1, 1).SetBit(p, 0, 1)
+
+		if p.ProbablyPrime(20) {
+			return p
+		}
+	}
+}
+
+// seedInt returns the first bits bits of SHA-256(seed || label || i || j) for
+// j = 0, 1, ... as an integer.
+func seedInt(seed []byte, label string, i uint32, bits int) *big.Int {
+	var buf []byte
+	for j := uint32(0); len(buf)*8 < bits; j++ {
+		h := sha256.New()
+		h.Write(seed)
+		h.Write([]byte(label))
+		h.Write(binary.BigEndian.AppendUint32(nil, i))
+		h.Write(binary.BigEndian.AppendUint32(nil, j))
+		buf = h.Sum(buf)
+	}
+
+	n := new(big.Int).SetBytes(buf)
+
+	return n.Rsh(n, uint(len(buf)*8-bits))
+}
@@ -0,0 +1,140 @@
+package becc
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestGenerateCurve(t *testing.T) {
+	seed := []byte("becc test seed")
+
+	tt := []struct {
+		bits        int
+		maxCofactor int64
+		p, b        string
+		gx, gy      string
+		n           string
+	}{
+		{bits: 16, maxCofactor: 1, p: "c613", b: "2651", gx: "8f06", gy: "5610", n: "c641"},
+		{bits: 32, maxCofactor: 1, p: "c9fc5653", b: "1a61b594", gx: "8f063920", gy: "3aa22f42", n: "c9fcd025"},
+		{bits: 48, maxCofactor: 1, p: "c9fc56535d07", b: "0148b0f33e23", gx: "8f06392061eb", gy: "52e565923544", n: "c9fc56bdf513"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.p, func(t *testing.T) {
+			ecc, err := generateCurve(tc.bits, seed, tc.maxCofactor, tc.bits/2-8)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ec := ecc.Curve()
+			if ec.P().Cmp(bigIntHex(t, tc.p)) != 0 || ec.B().Cmp(bigIntHex(t, tc.b)) != 0 {
+				t.Errorf("got p = %x and b = %x, expected %s and %s", ec.P(), ec.B(), tc.p, tc.b)
+			}
+
+			if ec.A().Cmp(new(big.Int).Sub(ec.P(), big.NewInt(3))) != 0 {
+				t.Errorf("got a = %x, expected -3", ec.A())
+			}
+
+			g := ecc.Generator()
+			if g.X().Cmp(bigIntHex(t, tc.gx)) != 0 || g.Y().Cmp(bigIntHex(t, tc.gy)) != 0 {
+				t.Errorf("got G = %v, expected (%s, %s)", g, tc.gx, tc.gy)
+			}
+
+			if ecc.Order().Cmp(bigIntHex(t, tc.n)) != 0 || ecc.Cofactor().Cmp(bi1) != 0 {
+				t.Errorf("got n = %x and h = %s, expected %s and 1", ecc.Order(), ecc.Cofactor(), tc.n)
+			}
+
+			if !bytes.Equal(ecc.Seed(), seed) {
+				t.Errorf("got seed %x, expected %x", ecc.Seed(), seed)
+			}
+
+			// the generated order agrees with a plain point count
+			if tc.bits == 32 {
+				pointCount, err := ec.Order()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if pointCount.Cmp(ecc.Order()) != 0 {
+					t.Errorf("point count: got %x, expected %x", pointCount, ecc.Order())
+				}
+			}
+		})
+	}
+
+	t.Run("256 bits", func(t *testing.T) {
+		// a seed whose first candidates give a suitable curve: most seeds
+		// need a few hundred, which takes about ten minutes
+		ecc, err := GenerateCurve(256, []byte("becc 80"), 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ec := ecc.Curve()
+		p := bigIntHex(t, "b7aef22d6bfa227b61b68730af5eda070f223393144baa236a205f19804b91c7")
+		n := bigIntHex(t, "b7aef22d6bfa227b61b68730af5eda08bae3b9942abf35607e6b53f4e77a45d9")
+		if ec.P().Cmp(p) != 0 || ecc.Order().Cmp(n) != 0 || ecc.Cofactor().Cmp(bi1) != 0 {
+			t.Fatalf("got p = %x, n = %x and h = %s, expected %x, %x and 1", ec.P(), ecc.Order(), ecc.Cofactor(), p, n)
+		}
+
+		// the audit counts the points again
+		report, err := AuditCurve(ec.A(), ec.B(), ec.P(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !report.Safe() {
+			t.Errorf("the curve does not pass the audit: %v", report.Issues)
+		}
+
+		if report.Order.Cmp(n) != 0 {
+			t.Errorf("audit order: got %x, expected %x", report.Order, n)
+		}
+	})
+
+	t.Run("cofactor", func(t *testing.T) {
+		ecc, err := generateCurve(24, []byte("cofactor"), 4, 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if ecc.Cofactor().Cmp(big.NewInt(4)) > 0 {
+			t.Errorf("got cofactor %s, expected at most 4", ecc.Cofactor())
+		}
+	})
+
+	t.Run("parameters file", func(t *testing.T) {
+		ecc, err := generateCurve(32, seed, 1, 8)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, encode := range []func() ([]byte, error){ecc.ECParametersJSON, ecc.ECParametersPEM} {
+			params, err := encode()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			loaded, err := LoadECParameters(params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertSameDomain(t, loaded, ecc)
+
+			if !bytes.Equal(loaded.Seed(), seed) {
+				t.Errorf("got seed %x, expected %x", loaded.Seed(), seed)
+			}
+		}
+	})
+
+	t.Run("too small", func(t *testing.T) {
+		_, err := GenerateCurve(128, seed, 1)
+		if !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("got %v, expected ErrInvalidParameters", err)
+		}
+	})
+}
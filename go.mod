@@ -2,8 +2,14 @@ module github.com/artilugio0/becc
 
 go 1.25.5
 
+require (
+	github.com/spf13/cobra v1.10.2
+	golang.org/x/crypto v0.50.0
+	golang.org/x/text v0.36.0
+)
+
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.10.2 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
+	golang.org/x/sys v0.43.0 // indirect
 )
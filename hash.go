@@ -0,0 +1,117 @@
+package becc
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/sha3"
+)
+
+type HashFunc = func() hash.Hash
+
+var ErrUnknownHash error = errors.New("unknown hash function")
+
+var (
+	SHA224 = sha256.New224
+	SHA256 = sha256.New
+	SHA384 = sha512.New384
+	SHA512 = sha512.New
+
+	SHA3_256 = sha3.New256
+	SHA3_384 = sha3.New384
+	SHA3_512 = sha3.New512
+
+	// Keccak256 is the original Keccak-256 used by Ethereum, which differs
+	// from SHA3-256 in its padding.
+	Keccak256 = sha3.NewLegacyKeccak256
+
+	// BLAKE2b256, BLAKE2b512 and BLAKE2s256 are the unkeyed BLAKE2 hashes
+	// (RFC 7693).
+	BLAKE2b256 = unkeyed(blake2b.New256)
+	BLAKE2b512 = unkeyed(blake2b.New512)
+	BLAKE2s256 = unkeyed(blake2s.New256)
+)
+
+// unkeyed returns the hash function of a BLAKE2 constructor with no key,
+// which cannot fail.
+func unkeyed(newHash func(key []byte) (hash.Hash, error)) HashFunc {
+	return func() hash.Hash {
+		h, err := newHash(nil)
+		if err != nil {
+			panic(err)
+		}
+
+		return h
+	}
+}
+
+// hashes are the hash functions by the names LookupHash accepts.
+var hashes = map[string]HashFunc{
+	"sha224":      SHA224,
+	"sha256":      SHA256,
+	"sha384":      SHA384,
+	"sha512":      SHA512,
+	"sha3-256":    SHA3_256,
+	"sha3-384":    SHA3_384,
+	"sha3-512":    SHA3_512,
+	"keccak256":   Keccak256,
+	"blake2b-256": BLAKE2b256,
+	"blake2b-512": BLAKE2b512,
+	"blake2s-256": BLAKE2s256,
+}
+
+// LookupHash returns the hash function with the name, such as sha384,
+// sha3-256 or blake2b-512, in any case.
+func LookupHash(name string) (HashFunc, error) {
+	hf, ok := hashes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownHash, name)
+	}
+
+	return hf, nil
+}
+
+// HashNames returns the names of the hash functions of LookupHash, sorted.
+func HashNames() []string {
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// DefaultHash returns the SHA-2 hash function that matches the size of the
+// order of the curve: SHA-256 up to 256 bits, SHA-384 up to 384 bits, such
+// as for P-384, and SHA-512 above, such as for P-521.
+func (e *ECC) DefaultHash() HashFunc {
+	switch bits := e.n.BitLen(); {
+	case bits <= 256:
+		return SHA256
+	case bits <= 384:
+		return SHA384
+	default:
+		return SHA512
+	}
+}
+
+// hashToInt returns bits2int(hash) of RFC 6979 (section 2.3.2), the integer
+// z of ECDSA: the hash as a big-endian integer, shifted right to keep its
+// leftmost bits when it is longer than the order.
+func (e *ECC) hashToInt(hash []byte) *big.Int {
+	z := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - e.n.BitLen(); excess > 0 {
+		z.Rsh(z, uint(excess))
+	}
+
+	return z
+}
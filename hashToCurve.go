@@ -0,0 +1,361 @@
+package becc
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrUnsupportedCurve error = errors.New("unsupported curve")
+
+// hashToCurveSuite holds the parameters of an RFC 9380 hash-to-curve suite
+// with expand_message_xmd and the simplified SWU map.
+type hashToCurveSuite struct {
+	// id is the suite ID without the trailing "RO_" or "NU_"
+	id string
+
+	hash HashFunc
+
+	// k is the target security level in bits
+	k int
+
+	// z is the non-square of the simplified SWU map
+	z int64
+
+	// a and b are the coefficients of the curve the simplified SWU map
+	// targets. It needs a·b != 0, so for secp256k1 it maps to an isogenous
+	// curve and iso takes the points back to secp256k1.
+	a, b *big.Int
+	iso  *isogeny
+}
+
+// isogeny is a rational map (x, y) -> (xNum/xDen, y·yNum/yDen) between two
+// curves, with the coefficients of each polynomial from the constant term up.
+type isogeny struct {
+	xNum, xDen, yNum, yDen []*big.Int
+}
+
+var (
+	// secp256k1 3-isogeny from RFC 9380, appendix E.1
+	secp256k1IsoA, _ = new(big.Int).SetString("3f8731abdd661adca08a5558f0f5d272e953d363cb6f0e5d405447c01a444533", 16)
+	secp256k1IsoB    = big.NewInt(1771)
+	secp256k1Iso     = &isogeny{
+		xNum: hexInts(
+			"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa8c7",
+			"07d3d4c80bc321d5b9f315cea7fd44c5d595d2fc0bf63b92dfff1044f17c6581",
+			"534c328d23f234e6e2a413deca25caece4506144037c40314ecbd0b53d9dd262",
+			"8e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38e38daaaaa88c",
+		),
+		xDen: hexInts(
+			"d35771193d94918a9ca34ccbb7b640dd86cd409542f8487d9fe6b745781eb49b",
+			"edadc6f64383dc1df7c4b2d51b54225406d36b641f5e41bbc52a56612a8c6d14",
+			"01",
+		),
+		yNum: hexInts(
+			"4bda12f684bda12f684bda12f684bda12f684bda12f684bda12f684b8e38e23c",
+			"c75e0c32d5cb7c0fa9d0a54b12a0a6d5647ab046d686da6fdffc90fc201d71a3",
+			"29a6194691f91a73715209ef6512e576722830a201be2018a765e85a9ecee931",
+			"2f684bda12f684bda12f684bda12f684bda12f684bda12f684bda12f38e38d84",
+		),
+		yDen: hexInts(
+			"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffff93b",
+			"7a06534bb8bdb49fd5e9e6632722c2989467c1bfc8e8d978dfb425d2685c2573",
+			"6484aa716545ca2cf3a70c3fa8fe337e0a3d21162f0d6299a7bf8192bfd2a76f",
+			"01",
+		),
+	}
+)
+
+// hashToCurveSuites maps the curves with a suite in RFC 9380, section 8, to
+// it.
+var hashToCurveSuites = map[string]*hashToCurveSuite{
+	"secp256k1": {id: "secp256k1_XMD:SHA-256_SSWU_", hash: sha256.New, k: 128, z: -11, a: secp256k1IsoA, b: secp256k1IsoB, iso: secp256k1Iso},
+	"secp256r1": {id: "P256_XMD:SHA-256_SSWU_", hash: sha256.New, k: 128, z: -10, a: Secp256r1A, b: Secp256r1B},
+	"secp384r1": {id: "P384_XMD:SHA-384_SSWU_", hash: sha512.New384, k: 192, z: -12, a: Secp384r1A, b: Secp384r1B},
+	"secp521r1": {id: "P521_XMD:SHA-512_SSWU_", hash: sha512.New, k: 256, z: -4, a: Secp521r1A, b: Secp521r1B},
+}
+
+func hexInts(values ...string) []*big.Int {
+	ints := make([]*big.Int, len(values))
+	for i, v := range values {
+		ints[i], _ = new(big.Int).SetString(v, 16)
+	}
+
+	return ints
+}
+
+// HashToCurve hashes msg to a point of the curve with the hash_to_curve
+// function of the curve's RFC 9380 random oracle suite, such as
+// P256_XMD:SHA-256_SSWU_RO_. The result is indistinguishable from a random
+// point and nobody knows its discrete logarithm. dst is the domain separation
+// tag, which must be unique to the protocol and its use of the function, and
+// not empty.
+//
+// Only secp256k1, secp256r1, secp384r1 and secp521r1 have a suite. The map
+// is the straight-line one of the RFC, with no branches on secret data, but
+// math/big arithmetic is not constant-time.
+func (e *ECC) HashToCurve(msg, dst []byte) (Point, error) {
+	suite, err := e.hashToCurveSuite()
+	if err != nil {
+		return Point{}, err
+	}
+
+	u, err := hashToField(suite.hash, msg, dst, 2, e.ec.m, suite.k)
+	if err != nil {
+		return Point{}, err
+	}
+
+	q0 := e.mapToCurve(suite, u[0])
+	q1 := e.mapToCurve(suite, u[1])
+
+	return e.ClearCofactor(q0.Add(q1)), nil
+}
+
+// EncodeToCurve hashes msg to a point of the curve with the encode_to_curve
+// function of the curve's RFC 9380 nonuniform suite, such as
+// P256_XMD:SHA-256_SSWU_NU_. It is about twice as fast as HashToCurve, but
+// its output only covers about half of the points, so it must not be used
+// where the protocol needs a random oracle.
+func (e *ECC) EncodeToCurve(msg, dst []byte) (Point, error) {
+	suite, err := e.hashToCurveSuite()
+	if err != nil {
+		return Point{}, err
+	}
+
+	u, err := hashToField(suite.hash, msg, dst, 1, e.ec.m, suite.k)
+	if err != nil {
+		return Point{}, err
+	}
+
+	return e.ClearCofactor(e.mapToCurve(suite, u[0])), nil
+}
+
+// HashToCurveSuiteID returns the RFC 9380 suite ID used by HashToCurve, or
+// by EncodeToCurve if ro is false.
+func (e *ECC) HashToCurveSuiteID(ro bool) (string, error) {
+	suite, err := e.hashToCurveSuite()
+	if err != nil {
+		return "", err
+	}
+
+	if ro {
+		return suite.id + "RO_", nil
+	}
+
+	return suite.id + "NU_", nil
+}
+
+// hashToCurveSuite returns the suite for the curve, checking that the domain
+// parameters are the ones of the named curve and not a custom curve with the
+// same name.
+func (e *ECC) hashToCurveSuite() (*hashToCurveSuite, error) {
+	suite, ok := hashToCurveSuites[e.name]
+	if !ok {
+		return nil, fmt.Errorf("%w: no hash-to-curve suite for %q", ErrUnsupportedCurve, e.name)
+	}
+
+	if err := e.checkNamedCurve(); err != nil {
+		return nil, err
+	}
+
+	return suite, nil
+}
+
+// checkNamedCurve returns an error if the curve does not have the parameters
+// of the registry curve it is named after, so that a custom curve that reuses
+// a name does not get the constants of another curve.
+func (e *ECC) checkNamedCurve() error {
+	ci, err := lookupCurveInfo(e.name)
+	if err != nil {
+		return err
+	}
+
+	ec, _, _ := ci.params()
+	if !e.ec.a.Eq(ec.a) || !e.ec.b.Eq(ec.b) || e.ec.m.Cmp(ec.m) != 0 {
+		return fmt.Errorf("%w: the curve parameters do not match %s", ErrUnsupportedCurve, e.name)
+	}
+
+	return nil
+}
+
+// mapToCurve maps the field element u to a point with the simplified SWU
+// map, followed by the isogeny of the suite if it has one.
+func (e *ECC) mapToCurve(suite *hashToCurveSuite, u *big.Int) Point {
+	x, y := simplifiedSWU(suite, e.ec.m, u)
+	if suite.iso == nil {
+		return e.ec.NewPoint(x, y)
+	}
+
+	return suite.iso.apply(e.ec, x, y)
+}
+
+// simplifiedSWU returns the point of y^2 = x^3 + ax + b, with the a and b of
+// the suite, that u maps to. It is the straight-line version of RFC 9380,
+// appendix F.2, with sqrt_ratio optimized for p = 3 mod 4 (appendix F.2.1.2),
+// which holds for the primes of every suite.
+func simplifiedSWU(suite *hashToCurveSuite, p, u *big.Int) (*big.Int, *big.Int) {
+	fe := func(n *big.Int) *FieldElement { return NewFieldElement(n, p) }
+
+	a, b := fe(suite.a), fe(suite.b)
+	z := fe(big.NewInt(suite.z))
+	one := fe(bi1)
+	fu := fe(u)
+
+	tv1 := z.Mul(fu.Mul(fu))
+	tv2 := tv1.Mul(tv1).Add(tv1)
+	tv3 := b.Mul(tv2.Add(one))
+	tv4 := a.Mul(cmov(z, tv2.Neg(), !tv2.IsZero()))
+
+	tv6 := tv4.Mul(tv4)
+	gxNum := tv3.Mul(tv3).Add(a.Mul(tv6)).Mul(tv3)
+	tv6 = tv6.Mul(tv4)
+	gxNum = gxNum.Add(b.Mul(tv6))
+
+	isSquare, y1 := sqrtRatio(gxNum, tv6, z)
+
+	x := cmov(tv1.Mul(tv3), tv3, isSquare)
+	y := cmov(tv1.Mul(fu).Mul(y1), y1, isSquare)
+	y = cmov(y.Neg(), y, sgn0(fu) == sgn0(y))
+
+	// inv0: tv4 is never zero, as a != 0 and z is not a square
+	x = x.Mul(tv4.ModInverse())
+
+	return x.n, y.n
+}
+
+// sqrtRatio returns whether u/v is a square and sqrt(u/v) if it is, or
+// sqrt(z·u/v) if it is not, for p = 3 mod 4.
+func sqrtRatio(u, v, z *FieldElement) (bool, *FieldElement) {
+	p := u.m
+
+	// c1 = (p - 3) / 4, c2 = sqrt(-z)
+	c1 := new(big.Int).Rsh(p, 2)
+	c2 := new(big.Int).ModSqrt(z.Neg().n, p)
+
+	tv2 := u.Mul(v)
+	tv1 := v.Mul(v).Mul(tv2)
+	y1 := NewFieldElement(new(big.Int).Exp(tv1.n, c1, p), p).Mul(tv2)
+	y2 := y1.Mul(NewFieldElement(c2, p))
+
+	isQR := y1.Mul(y1).Mul(v).Eq(u)
+
+	return isQR, cmov(y2, y1, isQR)
+}
+
+// apply maps the point (x, y) of the isogenous curve to a point of ec. The
+// points where a denominator vanishes map to ∞.
+func (iso *isogeny) apply(ec EllipticCurve, x, y *big.Int) Point {
+	p := ec.m
+	eval := func(coeffs []*big.Int) *big.Int {
+		result := new(big.Int)
+		for i := len(coeffs) - 1; i >= 0; i-- {
+			result.Mul(result, x).Add(result, coeffs[i]).Mod(result, p)
+		}
+
+		return result
+	}
+
+	xDen, yDen := eval(iso.xDen), eval(iso.yDen)
+	if xDen.Sign() == 0 || yDen.Sign() == 0 {
+		return ec.Infinity()
+	}
+
+	xOut := eval(iso.xNum)
+	xOut.Mul(xOut, xDen.ModInverse(xDen, p)).Mod(xOut, p)
+
+	yOut := eval(iso.yNum)
+	yOut.Mul(yOut, yDen.ModInverse(yDen, p)).Mul(yOut, y).Mod(yOut, p)
+
+	return ec.NewPoint(xOut, yOut)
+}
+
+// cmov returns b if c is true and a otherwise.
+func cmov(a, b *FieldElement, c bool) *FieldElement {
+	if c {
+		return b
+	}
+
+	return a
+}
+
+// sgn0 returns the sign of a field element as defined in RFC 9380, section
+// 4.1, which for prime fields is its parity.
+func sgn0(fe *FieldElement) uint {
+	return fe.n.Bit(0)
+}
+
+// hashToField returns count elements of the field of integers modulo p
+// derived from msg with expand_message_xmd, as in RFC 9380, section 5.2. k
+// is the target security level in bits, which sets how many bytes are
+// reduced for each element so that the bias is negligible.
+func hashToField(hf HashFunc, msg, dst []byte, count int, p *big.Int, k int) ([]*big.Int, error) {
+	l := (p.BitLen() + k + 7) / 8
+
+	uniform, err := ExpandMessageXMD(hf, msg, dst, count*l)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]*big.Int, count)
+	for i := range elements {
+		elements[i] = new(big.Int).SetBytes(uniform[i*l : (i+1)*l])
+		elements[i].Mod(elements[i], p)
+	}
+
+	return elements, nil
+}
+
+// ExpandMessageXMD returns length uniformly random bytes derived from msg and
+// the domain separation tag dst with expand_message_xmd (RFC 9380, section
+// 5.3.1) on the hash function hf. dst must not be empty (section 3.1), and
+// one longer than 255 bytes is first hashed as in section 5.3.3.
+func ExpandMessageXMD(hf HashFunc, msg, dst []byte, length int) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, fmt.Errorf("%w: the domain separation tag must not be empty", ErrInvalidParameters)
+	}
+
+	h := hf()
+
+	if len(dst) > 255 {
+		h.Write([]byte("H2C-OVERSIZE-DST-"))
+		h.Write(dst)
+		dst = h.Sum(nil)
+		h.Reset()
+	}
+
+	ell := (length + h.Size() - 1) / h.Size()
+	if ell > 255 || length > 65535 || length <= 0 {
+		return nil, fmt.Errorf("%w: invalid expand_message_xmd length %d", ErrInvalidParameters, length)
+	}
+
+	dstPrime := append(dst[:len(dst):len(dst)], byte(len(dst)))
+
+	// b0 = H(Z_pad || msg || I2OSP(length, 2) || I2OSP(0, 1) || DST_prime)
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b1 = H(b0 || I2OSP(1, 1) || DST_prime)
+	// bi = H((b0 XOR b(i-1)) || I2OSP(i, 1) || DST_prime)
+	uniform := make([]byte, 0, ell*h.Size())
+	bi := make([]byte, h.Size())
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+
+		uniform = append(uniform, bi...)
+	}
+
+	return uniform[:length], nil
+}
@@ -0,0 +1,225 @@
+package becc
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExpandMessageXMD(t *testing.T) {
+	longDST := "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-" + strings.Repeat("1", 208)
+
+	tt := []struct {
+		name     string
+		hf       HashFunc
+		dst      string
+		msg      string
+		expected string
+	}{
+		{
+			name:     "SHA-256 empty message",
+			hf:       sha256.New,
+			dst:      "QUUX-V01-CS02-with-expander-SHA256-128",
+			msg:      "",
+			expected: "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		},
+		{
+			name:     "SHA-256",
+			hf:       sha256.New,
+			dst:      "QUUX-V01-CS02-with-expander-SHA256-128",
+			msg:      "abc",
+			expected: "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+		},
+		{
+			name:     "SHA-256 long output",
+			hf:       sha256.New,
+			dst:      "QUUX-V01-CS02-with-expander-SHA256-128",
+			msg:      "abc",
+			expected: "abba86a6129e366fc877aab32fc4ffc70120d8996c88aee2fe4b32d6c7b6437a647e6c3163d40b76a73cf6a5674ef1d890f95b664ee0afa5359a5c4e07985635bbecbac65d747d3d2da7ec2b8221b17b0ca9dc8a1ac1c07ea6a1e60583e2cb00058e77b7b72a298425cd1b941ad4ec65e8afc50303a22c0f99b0509b4c895f40",
+		},
+		{
+			name:     "SHA-256 oversize DST",
+			hf:       sha256.New,
+			dst:      longDST,
+			msg:      "abc",
+			expected: "52dbf4f36cf560fca57dedec2ad924ee9c266341d8f3d6afe5171733b16bbb12",
+		},
+		{
+			name:     "SHA-512",
+			hf:       sha512.New,
+			dst:      "QUUX-V01-CS02-with-expander-SHA512-256",
+			msg:      "abc",
+			expected: "0da749f12fbe5483eb066a5f595055679b976e93abe9be6f0f6318bce7aca8dc",
+		},
+		{
+			name:     "SHA-512 long output",
+			hf:       sha512.New,
+			dst:      "QUUX-V01-CS02-with-expander-SHA512-256",
+			msg:      "abc",
+			expected: "7f1dddd13c08b543f2e2037b14cefb255b44c83cc397c1786d975653e36a6b11bdd7732d8b38adb4a0edc26a0cef4bb45217135456e58fbca1703cd6032cb1347ee720b87972d63fbf232587043ed2901bce7f22610c0419751c065922b488431851041310ad659e4b23520e1772ab29dcdeb2002222a363f0c2b1c972b3efe1",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExpandMessageXMD(tc.hf, []byte(tc.msg), []byte(tc.dst), len(tc.expected)/2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if hex.EncodeToString(got) != tc.expected {
+				t.Errorf("got %x, expected %s", got, tc.expected)
+			}
+		})
+	}
+
+	t.Run("too long", func(t *testing.T) {
+		_, err := ExpandMessageXMD(sha256.New, nil, []byte("dst"), 255*32+1)
+		if !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("got %v, expected ErrInvalidParameters", err)
+		}
+	})
+
+	t.Run("empty dst", func(t *testing.T) {
+		_, err := ExpandMessageXMD(sha256.New, []byte("abc"), nil, 32)
+		if !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("got %v, expected ErrInvalidParameters", err)
+		}
+
+		if _, err := mustLookupCurve("secp256r1").HashToCurve([]byte("abc"), []byte{}); !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("got %v, expected ErrInvalidParameters", err)
+		}
+	})
+}
+
+type hashToCurveVector struct {
+	msg  string
+	x, y string
+}
+
+func TestHashToCurve(t *testing.T) {
+	// RFC 9380, appendix J
+	msgQ128 := "q128_" + strings.Repeat("q", 128)
+	msgA512 := "a512_" + strings.Repeat("a", 512)
+
+	suites := []struct {
+		curve   string
+		dst     string
+		ro      bool
+		vectors []hashToCurveVector
+	}{
+		{curve: "secp256k1", dst: "QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_", ro: true, vectors: []hashToCurveVector{
+			{msg: "", x: "c1cae290e291aee617ebaef1be6d73861479c48b841eaba9b7b5852ddfeb1346", y: "64fa678e07ae116126f08b022a94af6de15985c996c3a91b64c406a960e51067"},
+			{msg: "abc", x: "3377e01eab42db296b512293120c6cee72b6ecf9f9205760bd9ff11fb3cb2c4b", y: "7f95890f33efebd1044d382a01b1bee0900fb6116f94688d487c6c7b9c8371f6"},
+			{msg: "abcdef0123456789", x: "bac54083f293f1fe08e4a70137260aa90783a5cb84d3f35848b324d0674b0e3a", y: "4436476085d4c3c4508b60fcf4389c40176adce756b398bdee27bca19758d828"},
+			{msg: msgQ128, x: "e2167bc785333a37aa562f021f1e881defb853839babf52a7f72b102e41890e9", y: "f2401dd95cc35867ffed4f367cd564763719fbc6a53e969fb8496a1e6685d873"},
+			{msg: msgA512, x: "e3c8d35aaaf0b9b647e88a0a0a7ee5d5bed5ad38238152e4e6fd8c1f8cb7c998", y: "8446eeb6181bf12f56a9d24e262221cc2f0c4725c7e3803024b5888ee5823aa6"},
+		}},
+		{curve: "secp256k1", dst: "QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_NU_", ro: false, vectors: []hashToCurveVector{
+			{msg: "", x: "a4792346075feae77ac3b30026f99c1441b4ecf666ded19b7522cf65c4c55c5b", y: "62c59e2a6aeed1b23be5883e833912b08ba06be7f57c0e9cdc663f31639ff3a7"},
+			{msg: "abc", x: "3f3b5842033fff837d504bb4ce2a372bfeadbdbd84a1d2b678b6e1d7ee426b9d", y: "902910d1fef15d8ae2006fc84f2a5a7bda0e0407dc913062c3a493c4f5d876a5"},
+			{msg: "abcdef0123456789", x: "07644fa6281c694709f53bdd21bed94dab995671e4a8cd1904ec4aa50c59bfdf", y: "c79f8d1dad79b6540426922f7fbc9579c3018dafeffcd4552b1626b506c21e7b"},
+			{msg: msgQ128, x: "b734f05e9b9709ab631d960fa26d669c4aeaea64ae62004b9d34f483aa9acc33", y: "03fc8a4a5a78632e2eb4d8460d69ff33c1d72574b79a35e402e801f2d0b1d6ee"},
+			{msg: msgA512, x: "17d22b867658977b5002dbe8d0ee70a8cfddec3eec50fb93f36136070fd9fa6c", y: "e9178ff02f4dab73480f8dd590328aea99856a7b6cc8e5a6cdf289ecc2a51718"},
+		}},
+		{curve: "secp256r1", dst: "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_", ro: true, vectors: []hashToCurveVector{
+			{msg: "", x: "2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4", y: "8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+			{msg: "abc", x: "0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f", y: "5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+			{msg: "abcdef0123456789", x: "65038ac8f2b1def042a5df0b33b1f4eca6bff7cb0f9c6c1526811864e544ed80", y: "cad44d40a656e7aff4002a8de287abc8ae0482b5ae825822bb870d6df9b56ca3"},
+			{msg: msgQ128, x: "4be61ee205094282ba8a2042bcb48d88dfbb609301c49aa8b078533dc65a0b5d", y: "98f8df449a072c4721d241a3b1236d3caccba603f916ca680f4539d2bfb3c29e"},
+			{msg: msgA512, x: "457ae2981f70ca85d8e24c308b14db22f3e3862c5ea0f652ca38b5e49cd64bc5", y: "ecb9f0eadc9aeed232dabc53235368c1394c78de05dd96893eefa62b0f4757dc"},
+		}},
+		{curve: "secp256r1", dst: "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_NU_", ro: false, vectors: []hashToCurveVector{
+			{msg: "", x: "f871caad25ea3b59c16cf87c1894902f7e7b2c822c3d3f73596c5ace8ddd14d1", y: "87b9ae23335bee057b99bac1e68588b18b5691af476234b8971bc4f011ddc99b"},
+			{msg: "abc", x: "fc3f5d734e8dce41ddac49f47dd2b8a57257522a865c124ed02b92b5237befa4", y: "fe4d197ecf5a62645b9690599e1d80e82c500b22ac705a0b421fac7b47157866"},
+			{msg: "abcdef0123456789", x: "f164c6674a02207e414c257ce759d35eddc7f55be6d7f415e2cc177e5d8faa84", y: "3aa274881d30db70485368c0467e97da0e73c18c1d00f34775d012b6fcee7f97"},
+			{msg: msgQ128, x: "324532006312be4f162614076460315f7a54a6f85544da773dc659aca0311853", y: "8d8197374bcd52de2acfefc8a54fe2c8d8bebd2a39f16be9b710e4b1af6ef883"},
+			{msg: msgA512, x: "5c4bad52f81f39c8e8de1260e9a06d72b8b00a0829a8ea004a610b0691bea5d9", y: "c801e7c0782af1f74f24fc385a8555da0582032a3ce038de637ccdcb16f7ef7b"},
+		}},
+		{curve: "secp384r1", dst: "QUUX-V01-CS02-with-P384_XMD:SHA-384_SSWU_RO_", ro: true, vectors: []hashToCurveVector{
+			{msg: "", x: "eb9fe1b4f4e14e7140803c1d99d0a93cd823d2b024040f9c067a8eca1f5a2eeac9ad604973527a356f3fa3aeff0e4d83", y: "0c21708cff382b7f4643c07b105c2eaec2cead93a917d825601e63c8f21f6abd9abc22c93c2bed6f235954b25048bb1a"},
+			{msg: "abc", x: "e02fc1a5f44a7519419dd314e29863f30df55a514da2d655775a81d413003c4d4e7fd59af0826dfaad4200ac6f60abe1", y: "01f638d04d98677d65bef99aef1a12a70a4cbb9270ec55248c04530d8bc1f8f90f8a6a859a7c1f1ddccedf8f96d675f6"},
+			{msg: "abcdef0123456789", x: "bdecc1c1d870624965f19505be50459d363c71a699a496ab672f9a5d6b78676400926fbceee6fcd1780fe86e62b2aa89", y: "57cf1f99b5ee00f3c201139b3bfe4dd30a653193778d89a0accc5e0f47e46e4e4b85a0595da29c9494c1814acafe183c"},
+			{msg: msgQ128, x: "03c3a9f401b78c6c36a52f07eeee0ec1289f178adf78448f43a3850e0456f5dd7f7633dd31676d990eda32882ab486c0", y: "cc183d0d7bdfd0a3af05f50e16a3f2de4abbc523215bf57c848d5ea662482b8c1f43dc453a93b94a8026db58f3f5d878"},
+			{msg: msgA512, x: "7b18d210b1f090ac701f65f606f6ca18fb8d081e3bc6cbd937c5604325f1cdea4c15c10a54ef303aabf2ea58bd9947a4", y: "ea857285a33abb516732915c353c75c576bf82ccc96adb63c094dde580021eddeafd91f8c0bfee6f636528f3d0c47fd2"},
+		}},
+		{curve: "secp384r1", dst: "QUUX-V01-CS02-with-P384_XMD:SHA-384_SSWU_NU_", ro: false, vectors: []hashToCurveVector{
+			{msg: "", x: "de5a893c83061b2d7ce6a0d8b049f0326f2ada4b966dc7e72927256b033ef61058029a3bfb13c1c7ececd6641881ae20", y: "63f46da6139785674da315c1947e06e9a0867f5608cf24724eb3793a1f5b3809ee28eb21a0c64be3be169afc6cdb38ca"},
+			{msg: "abc", x: "1f08108b87e703c86c872ab3eb198a19f2b708237ac4be53d7929fb4bd5194583f40d052f32df66afe5249c9915d139b", y: "1369dc8d5bf038032336b989994874a2270adadb67a7fcc32f0f8824bc5118613f0ac8de04a1041d90ff8a5ad555f96c"},
+			{msg: "abcdef0123456789", x: "4dac31ec8a82ee3c02ba2d7c9fa431f1e59ffe65bf977b948c59e1d813c2d7963c7be81aa6db39e78ff315a10115c0d0", y: "845333cdb5702ad5c525e603f302904d6fc84879f0ef2ee2014a6b13edd39131bfd66f7bd7cdc2d9ccf778f0c8892c3f"},
+			{msg: msgQ128, x: "13c1f8c52a492183f7c28e379b0475486718a7e3ac1dfef39283b9ce5fb02b73f70c6c1f3dfe0c286b03e2af1af12d1d", y: "57e101887e73e40eab8963324ed16c177d55eb89f804ec9df06801579820420b5546b579008df2145fd770f584a1a54c"},
+			{msg: msgA512, x: "af129727a4207a8cb9e9dce656d88f79fce25edbcea350499d65e9bf1204537bdde73c7cefb752a6ed5ebcd44e183302", y: "ce68a3d5e161b2e6a968e4ddaa9e51504ad1516ec170c7eef3ca6b5327943eca95d90b23b009ba45f58b72906f2a99e2"},
+		}},
+		{curve: "secp521r1", dst: "QUUX-V01-CS02-with-P521_XMD:SHA-512_SSWU_RO_", ro: true, vectors: []hashToCurveVector{
+			{msg: "", x: "00fd767cebb2452030358d0e9cf907f525f50920c8f607889a6a35680727f64f4d66b161fafeb2654bea0d35086bec0a10b30b14adef3556ed9f7f1bc23cecc9c088", y: "0169ba78d8d851e930680322596e39c78f4fe31b97e57629ef6460ddd68f8763fd7bd767a4e94a80d3d21a3c2ee98347e024fc73ee1c27166dc3fe5eeef782be411d"},
+			{msg: "abc", x: "002f89a1677b28054b50d15e1f81ed6669b5a2158211118ebdef8a6efc77f8ccaa528f698214e4340155abc1fa08f8f613ef14a043717503d57e267d57155cf784a4", y: "010e0be5dc8e753da8ce51091908b72396d3deed14ae166f66d8ebf0a4e7059ead169ea4bead0232e9b700dd380b316e9361cfdba55a08c73545563a80966ecbb86d"},
+			{msg: "abcdef0123456789", x: "006e200e276a4a81760099677814d7f8794a4a5f3658442de63c18d2244dcc957c645e94cb0754f95fcf103b2aeaf94411847c24187b89fb7462ad3679066337cbc4", y: "001dd8dfa9775b60b1614f6f169089d8140d4b3e4012949b52f98db2deff3e1d97bf73a1fa4d437d1dcdf39b6360cc518d8ebcc0f899018206fded7617b654f6b168"},
+			{msg: msgQ128, x: "01b264a630bd6555be537b000b99a06761a9325c53322b65bdc41bf196711f9708d58d34b3b90faf12640c27b91c70a507998e55940648caa8e71098bf2bc8d24664", y: "01ea9f445bee198b3ee4c812dcf7b0f91e0881f0251aab272a12201fd89b1a95733fd2a699c162b639e9acdcc54fdc2f6536129b6beb0432be01aa8da02df5e59aaa"},
+			{msg: msgA512, x: "00c12bc3e28db07b6b4d2a2b1167ab9e26fc2fa85c7b0498a17b0347edf52392856d7e28b8fa7a2dd004611159505835b687ecf1a764857e27e9745848c436ef3925", y: "01cd287df9a50c22a9231beb452346720bb163344a41c5f5a24e8335b6ccc595fd436aea89737b1281aecb411eb835f0b939073fdd1dd4d5a2492e91ef4a3c55bcbd"},
+		}},
+		{curve: "secp521r1", dst: "QUUX-V01-CS02-with-P521_XMD:SHA-512_SSWU_NU_", ro: false, vectors: []hashToCurveVector{
+			{msg: "", x: "01ec604b4e1e3e4c7449b7a41e366e876655538acf51fd40d08b97be066f7d020634e906b1b6942f9174b417027c953d75fb6ec64b8cee2a3672d4f1987d13974705", y: "00944fc439b4aad2463e5c9cfa0b0707af3c9a42e37c5a57bb4ecd12fef9fb21508568aedcdd8d2490472df4bbafd79081c81e99f4da3286eddf19be47e9c4cf0e91"},
+			{msg: "abc", x: "00c720ab56aa5a7a4c07a7732a0a4e1b909e32d063ae1b58db5f0eb5e09f08a9884bff55a2bef4668f715788e692c18c1915cd034a6b998311fcf46924ce66a2be9a", y: "003570e87f91a4f3c7a56be2cb2a078ffc153862a53d5e03e5dad5bccc6c529b8bab0b7dbb157499e1949e4edab21cf5d10b782bc1e945e13d7421ad8121dbc72b1d"},
+			{msg: "abcdef0123456789", x: "00bcaf32a968ff7971b3bbd9ce8edfbee1309e2019d7ff373c38387a782b005dce6ceffccfeda5c6511c8f7f312f343f3a891029c5858f45ee0bf370aba25fc990cc", y: "00923517e767532d82cb8a0b59705eec2b7779ce05f9181c7d5d5e25694ef8ebd4696343f0bc27006834d2517215ecf79482a84111f50c1bae25044fe1dd77744bbd"},
+			{msg: msgQ128, x: "001ac69014869b6c4ad7aa8c443c255439d36b0e48a0f57b03d6fe9c40a66b4e2eaed2a93390679a5cc44b3a91862b34b673f0e92c83187da02bf3db967d867ce748", y: "00d5603d530e4d62b30fccfa1d90c2206654d74291c1db1c25b86a051ee3fffc294e5d56f2e776853406bd09206c63d40f37ad8829524cf89ad70b5d6e0b4a3b7341"},
+			{msg: msgA512, x: "01801de044c517a80443d2bd4f503a9e6866750d2f94a22970f62d721f96e4310e4a828206d9cdeaa8f2d476705cc3bbc490a6165c687668f15ec178a17e3d27349b", y: "0068889ea2e1442245fe42bfda9e58266828c0263119f35a61631a3358330f3bb84443fcb54fcd53a1d097fccbe310489b74ee143fc2938959a83a1f7dd4a6fd395b"},
+		}},
+	}
+
+	for _, s := range suites {
+		ecc := mustLookupCurve(s.curve)
+		id, err := ecc.HashToCurveSuiteID(s.ro)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		t.Run(id, func(t *testing.T) {
+			if !strings.HasSuffix(s.dst, id) {
+				t.Errorf("got suite ID %s for DST %s", id, s.dst)
+			}
+
+			hash := ecc.EncodeToCurve
+			if s.ro {
+				hash = ecc.HashToCurve
+			}
+
+			for _, v := range s.vectors {
+				got, err := hash([]byte(v.msg), []byte(s.dst))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				expected := point(t, ecc.Curve(), v.x, v.y)
+				if !got.Eq(expected) || got.IsInfinity() {
+					t.Errorf("msg %.10q: got %v, expected %v", v.msg, got, expected)
+				}
+
+				if !ecc.Curve().IsOnCurve(got) {
+					t.Errorf("msg %.10q: point is not on the curve", v.msg)
+				}
+			}
+		})
+	}
+}
+
+func TestHashToCurveUnsupported(t *testing.T) {
+	ecc := mustLookupCurve("brainpoolP256r1")
+
+	if _, err := ecc.HashToCurve([]byte("abc"), []byte("dst")); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+	}
+
+	// a custom curve that borrows the name of a supported one
+	custom, err := generateCurve(32, []byte("becc test seed"), 1, 8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	custom.name = "secp256r1"
+
+	if _, err := custom.EncodeToCurve([]byte("abc"), []byte("dst")); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+	}
+}
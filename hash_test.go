@@ -0,0 +1,186 @@
+package becc
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestHashFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      string
+		expected string
+	}{
+		{name: "sha224", msg: "abc", expected: "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7"},
+		{name: "sha256", msg: "abc", expected: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{name: "sha384", msg: "abc", expected: "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7"},
+		{name: "sha512", msg: "abc", expected: "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+		{name: "sha3-256", msg: "", expected: "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"},
+		{name: "sha3-384", msg: "", expected: "0c63a75b845e4f7d01107d852e4c2485c51a50aaaa94fc61995e71bbee983a2ac3713831264adb47fb6bd1e058d5f004"},
+		{name: "sha3-512", msg: "", expected: "a69f73cca23a9ac5c8b567dc185a756e97c982164fe25859e0d1dcc1475c80a615b2123af1f5f94c11e3e9402c3ac558f500199d95b6d3e301758586281dcd26"},
+		{name: "keccak256", msg: "", expected: "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{name: "blake2b-512", msg: "abc", expected: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{name: "blake2s-256", msg: "abc", expected: "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf, err := LookupHash(strings.ToUpper(tt.name))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			h := hf()
+			h.Write([]byte(tt.msg))
+			if got := hex.EncodeToString(h.Sum(nil)); got != tt.expected {
+				t.Errorf("got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+
+	if len(HashNames()) != 11 {
+		t.Errorf("got hash names %v", HashNames())
+	}
+
+	if _, err := LookupHash("md5"); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("expected error %v, got %v", ErrUnknownHash, err)
+	}
+
+	if size := BLAKE2b256().Size(); size != 32 {
+		t.Errorf("got BLAKE2b-256 size %d", size)
+	}
+}
+
+func TestDefaultHash(t *testing.T) {
+	for _, tt := range []struct {
+		ecc  *ECC
+		size int
+	}{
+		{ecc: Secp256k1ECC(), size: 32},
+		{ecc: Secp256r1ECC(), size: 32},
+		{ecc: Secp384r1ECC(), size: 48},
+		{ecc: Secp521r1ECC(), size: 64},
+	} {
+		if size := tt.ecc.DefaultHash()().Size(); size != tt.size {
+			t.Errorf("%s: got a hash of %d bytes, expected %d", tt.ecc.Name(), size, tt.size)
+		}
+	}
+}
+
+func TestECDSASignDeterministicRFC6979(t *testing.T) {
+	// RFC 6979, appendix A.2.5 to A.2.7, message "sample": the hashes longer
+	// than the order are truncated to its length
+	keys := map[string]string{
+		"secp256r1": "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		"secp384r1": "6b9d3dad2e1b8c1c05b19875b6659f4de23c3b667bf297ba9aa47740787137d896d5724e4c70a825f872c9ea60d2edf5",
+		"secp521r1": "00fad06daa62ba3b25d2fb40133da757205de67f5bb0018fee8c86e1b68c7e75caa896eb32f1f47c70855836a6d16fcc1466f6d8fbec67db89ec0c08b0e996b83538",
+	}
+
+	tests := []struct {
+		curve, hash, r, s string
+	}{
+		{
+			curve: "secp256r1",
+			hash:  "sha224",
+			r:     "53b2fff5d1752b2c689df257c04c40a587fababb3f6fc2702f1343af7ca9aa3f",
+			s:     "b9afb64fdc03dc1a131c7d2386d11e349f070aa432a4acc918bea988bf75c74c",
+		},
+		{
+			curve: "secp256r1",
+			hash:  "sha256",
+			r:     "efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716",
+			s:     "f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8",
+		},
+		{
+			curve: "secp256r1",
+			hash:  "sha384",
+			r:     "0eafea039b20e9b42309fb1d89e213057cbf973dc0cfc8f129edddc800ef7719",
+			s:     "4861f0491e6998b9455193e34e7b0d284ddd7149a74b95b9261f13abde940954",
+		},
+		{
+			curve: "secp256r1",
+			hash:  "sha512",
+			r:     "8496a60b5e9b47c825488827e0495b0e3fa109ec4568fd3f8d1097678eb97f00",
+			s:     "2362ab1adbe2b8adf9cb9edab740ea6049c028114f2460f96554f61fae3302fe",
+		},
+		{
+			curve: "secp384r1",
+			hash:  "sha384",
+			r:     "94edbb92a5ecb8aad4736e56c691916b3f88140666ce9fa73d64c4ea95ad133c81a648152e44acf96e36dd1e80fabe46",
+			s:     "99ef4aeb15f178cea1fe40db2603138f130e740a19624526203b6351d0a3a94fa329c145786e679e7b82c71a38628ac8",
+		},
+		{
+			curve: "secp384r1",
+			hash:  "sha512",
+			r:     "ed0959d5880ab2d869ae7f6c2915c6d60f96507f9cb3e047c0046861da4a799cfe30f35cc900056d7c99cd7882433709",
+			s:     "512c8cceee3890a84058ce1e22dbc2198f42323ce8aca9135329f03c068e5112dc7cc3ef3446defceb01a45c2667fdd5",
+		},
+		{
+			curve: "secp521r1",
+			hash:  "sha224",
+			r:     "01776331cfcdf927d666e032e00cf776187bc9fdd8e69d0dabb4109ffe1b5e2a30715f4cc923a4a5e94d2503e9acfed92857b7f31d7152e0f8c00c15ff3d87e2ed2e",
+			s:     "0050cb5265417fe2320bbb5a122b8e1a32bd699089851128e360e620a30c7e17ba41a666af126ce100e5799b153b60528d5300d08489ca9178fb610a2006c254b41f",
+		},
+		{
+			curve: "secp521r1",
+			hash:  "sha256",
+			r:     "01511bb4d675114fe266fc4372b87682baecc01d3cc62cf2303c92b3526012659d16876e25c7c1e57648f23b73564d67f61c6f14d527d54972810421e7d87589e1a7",
+			s:     "004a171143a83163d6df460aaf61522695f207a58b95c0644d87e52aa1a347916e4f7a72930b1bc06dbe22ce3f58264afd23704cbb63b29b931f7de6c9d949a7ecfc",
+		},
+		{
+			curve: "secp521r1",
+			hash:  "sha512",
+			r:     "00c328fafcbd79dd77850370c46325d987cb525569fb63c5d3bc53950e6d4c5f174e25a1ee9017b5d450606add152b534931d7d4e8455cc91f9b15bf05ec36e377fa",
+			s:     "00617cce7cf5064806c467f678d3b4080d6f1cc50af26ca209417308281b68af282623eaa63e5b5c0723d8b8c37ff0777b1a20f8ccb1dccc43997f1ee0e44da4a67a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.curve+"/"+tt.hash, func(t *testing.T) {
+			ecc := mustLookupCurve(tt.curve)
+
+			priv, err := ecc.NewPrivateKeyBytes(hexBytes(t, keys[tt.curve]))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			hf, err := LookupHash(tt.hash)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			sig, err := priv.SignDeterministic(hf, []byte("sample"), false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expectedR := new(big.Int).SetBytes(hexBytes(t, tt.r))
+			expectedS := new(big.Int).SetBytes(hexBytes(t, tt.s))
+
+			if sig.R().Cmp(expectedR) != 0 {
+				t.Errorf("r: got %x, expected %s", sig.R(), tt.r)
+			}
+
+			if sig.S().Cmp(expectedS) != 0 {
+				t.Errorf("s: got %x, expected %s", sig.S(), tt.s)
+			}
+
+			if !priv.PublicKey().Verify(hf, []byte("sample"), sig) {
+				t.Errorf("the signature does not verify")
+			}
+
+			// the random signatures truncate the hash the same way
+			random, err := priv.Sign(hf, []byte("sample"), false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !priv.PublicKey().Verify(hf, []byte("sample"), random) {
+				t.Errorf("the random signature does not verify")
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+// Package hd implements hierarchical deterministic keys (BIP-32) for
+// secp256k1: a master extended key derived from a seed, from which a tree of
+// child keys is derived by index, and the xprv/xpub serialization of the
+// extended keys.
+//
+// An extended key is a private or public key with a 32-byte chain code.
+// Normal children can be derived from either, so an extended public key
+// yields the public keys of all the normal children of its private key.
+// Hardened children, with an index of HardenedOffset or more, can only be
+// derived from the private key.
+//
+// NewMasterSLIP10 extends the derivation to secp256r1 as specified by
+// SLIP-10.
+package hd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/artilugio0/becc"
+	"github.com/artilugio0/becc/base58"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// HardenedOffset is the index of the first hardened child, written i' or iH
+// in paths for the index HardenedOffset + i.
+const HardenedOffset uint32 = 0x80000000
+
+// serializedSize is the length of a serialized extended key, without the
+// checksum.
+const serializedSize = 78
+
+var (
+	ErrInvalidSeed        error = errors.New("invalid seed")
+	ErrInvalidExtendedKey error = errors.New("invalid extended key")
+	ErrInvalidPath        error = errors.New("invalid derivation path")
+	ErrInvalidChild       error = errors.New("invalid child key")
+	ErrHardenedFromPublic error = errors.New("cannot derive a hardened child from a public key")
+	ErrUnknownNetwork     error = errors.New("unknown extended key version")
+	ErrMaxDepth           error = errors.New("maximum depth reached")
+)
+
+// Network holds the version bytes of the serialized extended keys of a
+// network.
+type Network struct {
+	name            string
+	private, public uint32
+}
+
+var (
+	// Mainnet keys serialize as xprv and xpub.
+	Mainnet = Network{name: "mainnet", private: 0x0488ade4, public: 0x0488b21e}
+
+	// Testnet keys serialize as tprv and tpub.
+	Testnet = Network{name: "testnet", private: 0x04358394, public: 0x043587cf}
+)
+
+var networks = []Network{Mainnet, Testnet}
+
+func (n Network) Name() string {
+	return n.name
+}
+
+// ExtendedKey is a node of the derivation tree: a private or public key with
+// its chain code, and its position in the tree.
+type ExtendedKey struct {
+	ecc               *becc.ECC
+	network           Network
+	depth             uint8
+	parentFingerprint uint32
+	childNumber       uint32
+	chainCode         []byte
+	priv              *becc.PrivateKey
+	pub               becc.PublicKey
+
+	// retry is set for SLIP-10 keys, which derive invalid children again
+	// instead of failing with ErrInvalidChild
+	retry bool
+}
+
+// NewMaster returns the master extended private key of the seed, which must
+// be between 16 and 64 bytes long: the key and the chain code are the two
+// halves of HMAC-SHA512("Bitcoin seed", seed). It fails with ErrInvalidSeed
+// in the unlikely case that the key is not valid, and another seed must be
+// used.
+func NewMaster(seed []byte, network Network) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("%w: the seed must be between 16 and 64 bytes", ErrInvalidSeed)
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	i := mac.Sum(nil)
+
+	ecc := becc.Secp256k1ECC()
+	k := new(big.Int).SetBytes(i[:32])
+	if k.Sign() == 0 || k.Cmp(ecc.Order()) >= 0 {
+		return nil, fmt.Errorf("%w: the master key is out of range", ErrInvalidSeed)
+	}
+
+	priv, err := ecc.NewPrivateKey(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtendedKey{
+		ecc:       ecc,
+		network:   network,
+		chainCode: i[32:],
+		priv:      &priv,
+		pub:       priv.PublicKey(),
+	}, nil
+}
+
+// ECC returns the curve of the keys, secp256k1 unless k derives from
+// NewMasterSLIP10.
+func (k *ExtendedKey) ECC() *becc.ECC {
+	return k.ecc
+}
+
+func (k *ExtendedKey) Network() Network {
+	return k.network
+}
+
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// ChildNumber returns the index of the key in its parent, HardenedOffset or
+// more for a hardened child.
+func (k *ExtendedKey) ChildNumber() uint32 {
+	return k.childNumber
+}
+
+// ParentFingerprint returns the fingerprint of the parent key, 0 for the
+// master key.
+func (k *ExtendedKey) ParentFingerprint() uint32 {
+	return k.parentFingerprint
+}
+
+func (k *ExtendedKey) ChainCode() []byte {
+	return bytes.Clone(k.chainCode)
+}
+
+// IsPrivate reports whether k holds a private key.
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.priv != nil
+}
+
+// PrivateKey returns the private key of k, if it has one.
+func (k *ExtendedKey) PrivateKey() (becc.PrivateKey, error) {
+	if k.priv == nil {
+		return becc.PrivateKey{}, fmt.Errorf("%w: not an extended private key", ErrInvalidExtendedKey)
+	}
+
+	return *k.priv, nil
+}
+
+func (k *ExtendedKey) PublicKey() becc.PublicKey {
+	return k.pub
+}
+
+// Identifier returns the HASH160 of the compressed public key,
+// RIPEMD-160(SHA-256(key)).
+func (k *ExtendedKey) Identifier() []byte {
+	h := sha256.Sum256(k.pub.Compressed())
+
+	r := ripemd160.New()
+	r.Write(h[:])
+
+	return r.Sum(nil)
+}
+
+// Fingerprint returns the first 4 bytes of the identifier.
+func (k *ExtendedKey) Fingerprint() uint32 {
+	return binary.BigEndian.Uint32(k.Identifier())
+}
+
+// Neuter returns the extended public key of k.
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	neutered := *k
+	neutered.priv = nil
+
+	return &neutered
+}
+
+// Child returns the child of k with the index i, hardened if i is
+// HardenedOffset or more. Hardened children can only be derived from
+// private keys. It fails with ErrInvalidChild in the unlikely case that the
+// child key is not valid, and the next index must be used, except for SLIP-10
+// keys, whose derivation is then repeated from
+// HMAC-SHA512(c_par, 0x01 || IR || ser32(i)) until the child is valid.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	hardened := i >= HardenedOffset
+	if hardened && k.priv == nil {
+		return nil, ErrHardenedFromPublic
+	}
+
+	if k.depth == 255 {
+		return nil, ErrMaxDepth
+	}
+
+	// hardened: 0x00 || ser256(k_par) || ser32(i), normal: serP(K_par) || ser32(i)
+	var data []byte
+	if hardened {
+		data = append([]byte{0}, k.priv.Bytes()...)
+	} else {
+		data = k.pub.Compressed()
+	}
+	data = binary.BigEndian.AppendUint32(data, i)
+
+	for {
+		mac := hmac.New(sha512.New, k.chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		child, err := k.child(i, sum)
+		if !errors.Is(err, ErrInvalidChild) || !k.retry {
+			return child, err
+		}
+
+		data = append([]byte{1}, sum[32:]...)
+		data = binary.BigEndian.AppendUint32(data, i)
+	}
+}
+
+// child returns the child of k with the index i from the output of the HMAC,
+// or ErrInvalidChild if it is not valid.
+func (k *ExtendedKey) child(i uint32, sum []byte) (*ExtendedKey, error) {
+	n := k.ecc.Order()
+
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("%w: index %d", ErrInvalidChild, i)
+	}
+
+	child := &ExtendedKey{
+		ecc:               k.ecc,
+		network:           k.network,
+		depth:             k.depth + 1,
+		parentFingerprint: k.Fingerprint(),
+		childNumber:       i,
+		chainCode:         sum[32:],
+		retry:             k.retry,
+	}
+
+	if k.priv != nil {
+		// k_i = IL + k_par mod n
+		d := il.Add(il, k.priv.Int())
+		d.Mod(d, n)
+		if d.Sign() == 0 {
+			return nil, fmt.Errorf("%w: index %d", ErrInvalidChild, i)
+		}
+
+		priv, err := k.ecc.NewPrivateKey(d)
+		if err != nil {
+			return nil, err
+		}
+
+		child.priv = &priv
+		child.pub = priv.PublicKey()
+
+		return child, nil
+	}
+
+	// K_i = IL·G + K_par
+	p := k.ecc.Generator().ScalarMul(il).Add(k.pub.Point())
+	pub, err := k.ecc.NewPublicKey(p)
+	if err != nil {
+		return nil, fmt.Errorf("%w: index %d", ErrInvalidChild, i)
+	}
+	child.pub = pub
+
+	return child, nil
+}
+
+// Derive returns the descendant of k at the path, a string such as
+// m/44'/0'/0'/0/0 parsed by ParsePath. The path is relative to k, which
+// is usually the master key.
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k
+	for _, i := range indices {
+		if key, err = key.Child(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// ParsePath parses a derivation path: "m" followed by the indices separated
+// by "/", each one marked as hardened with a trailing "'", "h" or "H".
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("%w: the path must start with m", ErrInvalidPath)
+	}
+
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+
+		i, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid index %q", ErrInvalidPath, part)
+		}
+
+		if hardened {
+			i += uint64(HardenedOffset)
+		}
+
+		indices = append(indices, uint32(i))
+	}
+
+	return indices, nil
+}
+
+// FormatPath returns the path of the indices, with "'" for hardened
+// indices.
+func FormatPath(indices []uint32) string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, i := range indices {
+		if i >= HardenedOffset {
+			fmt.Fprintf(&sb, "/%d'", i-HardenedOffset)
+		} else {
+			fmt.Fprintf(&sb, "/%d", i)
+		}
+	}
+
+	return sb.String()
+}
+
+// Serialize returns the Base58Check serialization of k, xprv/xpub on mainnet
+// and tprv/tpub on testnet: version || depth || parent fingerprint ||
+// child number || chain code || key, where the key is 0x00 || ser256(k) for
+// private keys and serP(K) for public ones. SLIP-10 defines no serialization
+// for the keys of other curves than secp256k1, so it fails for them.
+func (k *ExtendedKey) Serialize() (string, error) {
+	if k.ecc.Name() != becc.Secp256k1ECC().Name() {
+		return "", fmt.Errorf("%w: extended keys are only serialized for secp256k1", becc.ErrUnsupportedCurve)
+	}
+
+	version := k.network.public
+	if k.priv != nil {
+		version = k.network.private
+	}
+
+	b := make([]byte, 0, serializedSize)
+	b = binary.BigEndian.AppendUint32(b, version)
+	b = append(b, k.depth)
+	b = binary.BigEndian.AppendUint32(b, k.parentFingerprint)
+	b = binary.BigEndian.AppendUint32(b, k.childNumber)
+	b = append(b, k.chainCode...)
+
+	if k.priv != nil {
+		b = append(b, 0)
+		b = append(b, k.priv.Bytes()...)
+	} else {
+		b = append(b, k.pub.Compressed()...)
+	}
+
+	return base58.CheckEncode(b), nil
+}
+
+// ParseExtendedKey parses a serialized extended private or public key of any
+// of the known networks.
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	b, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExtendedKey, err)
+	}
+
+	if len(b) != serializedSize {
+		return nil, fmt.Errorf("%w: invalid length", ErrInvalidExtendedKey)
+	}
+
+	ecc := becc.Secp256k1ECC()
+
+	k := &ExtendedKey{
+		ecc:               ecc,
+		depth:             b[4],
+		parentFingerprint: binary.BigEndian.Uint32(b[5:9]),
+		childNumber:       binary.BigEndian.Uint32(b[9:13]),
+		chainCode:         bytes.Clone(b[13:45]),
+	}
+
+	if k.depth == 0 && (k.parentFingerprint != 0 || k.childNumber != 0) {
+		return nil, fmt.Errorf("%w: the master key must have a zero parent fingerprint and index", ErrInvalidExtendedKey)
+	}
+
+	version := binary.BigEndian.Uint32(b[:4])
+
+	var private, found bool
+	for _, network := range networks {
+		if version == network.private || version == network.public {
+			k.network, private, found = network, version == network.private, true
+		}
+	}
+
+	if !found {
+		return nil, ErrUnknownNetwork
+	}
+
+	keyData := b[45:]
+
+	if private {
+		if keyData[0] != 0 {
+			return nil, fmt.Errorf("%w: invalid private key prefix", ErrInvalidExtendedKey)
+		}
+
+		priv, err := ecc.NewPrivateKeyBytes(keyData[1:])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidExtendedKey, err)
+		}
+
+		k.priv = &priv
+		k.pub = priv.PublicKey()
+
+		return k, nil
+	}
+
+	pub, err := ecc.NewPublicKeyCompressed(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExtendedKey, err)
+	}
+	k.pub = pub
+
+	return k, nil
+}
@@ -0,0 +1,323 @@
+package hd
+
+import (
+	"encoding/hex"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBIP32Vectors(t *testing.T) {
+	// BIP-32 test vectors 1 to 4
+	type chain struct {
+		path string
+		pub  string
+		priv string
+	}
+
+	tests := []struct {
+		name   string
+		seed   string
+		chains []chain
+	}{
+		{
+			name: "vector 1",
+			seed: "000102030405060708090a0b0c0d0e0f",
+			chains: []chain{
+				{
+					path: "m",
+					pub:  "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+					priv: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+				},
+				{
+					path: "m/0H",
+					pub:  "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+					priv: "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+				},
+				{
+					path: "m/0H/1",
+					pub:  "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+					priv: "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+				},
+				{
+					path: "m/0H/1/2H",
+					pub:  "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+					priv: "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+				},
+				{
+					path: "m/0H/1/2H/2",
+					pub:  "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+					priv: "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+				},
+				{
+					path: "m/0H/1/2H/2/1000000000",
+					pub:  "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+					priv: "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+				},
+			},
+		},
+		{
+			name: "vector 2",
+			seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+			chains: []chain{
+				{
+					path: "m",
+					pub:  "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+					priv: "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+				},
+				{
+					path: "m/0",
+					pub:  "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+					priv: "xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+				},
+				{
+					path: "m/0/2147483647H",
+					pub:  "xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+					priv: "xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+				},
+				{
+					path: "m/0/2147483647H/1",
+					pub:  "xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+					priv: "xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+				},
+				{
+					path: "m/0/2147483647H/1/2147483646H",
+					pub:  "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+					priv: "xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+				},
+				{
+					path: "m/0/2147483647H/1/2147483646H/2",
+					pub:  "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+					priv: "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+				},
+			},
+		},
+		{
+			name: "vector 3",
+			seed: "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+			chains: []chain{
+				{
+					path: "m",
+					pub:  "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+					priv: "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+				},
+				{
+					path: "m/0H",
+					pub:  "xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+					priv: "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+				},
+			},
+		},
+		{
+			name: "vector 4",
+			seed: "3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678",
+			chains: []chain{
+				{
+					path: "m",
+					pub:  "xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+					priv: "xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv",
+				},
+				{
+					path: "m/0H",
+					pub:  "xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+					priv: "xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G",
+				},
+				{
+					path: "m/0H/1H",
+					pub:  "xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+					priv: "xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed, err := hex.DecodeString(tt.seed)
+			if err != nil {
+				t.Fatalf("invalid hex: %v", err)
+			}
+
+			master, err := NewMaster(seed, Mainnet)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, c := range tt.chains {
+				key, err := master.Derive(c.path)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", c.path, err)
+				}
+
+				if got := mustSerialize(t, key); got != c.priv {
+					t.Errorf("%s: got private key %s, want %s", c.path, got, c.priv)
+				}
+
+				if got := mustSerialize(t, key.Neuter()); got != c.pub {
+					t.Errorf("%s: got public key %s, want %s", c.path, got, c.pub)
+				}
+
+				for _, s := range []string{c.priv, c.pub} {
+					parsed, err := ParseExtendedKey(s)
+					if err != nil {
+						t.Fatalf("%s: unexpected error: %v", c.path, err)
+					}
+
+					if got := mustSerialize(t, parsed); got != s {
+						t.Errorf("%s: round trip got %s, want %s", c.path, got, s)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPublicDerivation(t *testing.T) {
+	master, err := NewMaster(mustHex(t, "000102030405060708090a0b0c0d0e0f"), Testnet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := mustSerialize(t, master); got != "tprv8ZgxMBicQKsPeDgjzdC36fs6bMjGApWDNLR9erAXMs5skhMv36j9MV5ecvfavji5khqjWaWSFhN3YcCUUdiKH6isR4Pwy3U5y5egddBr16m" {
+		t.Errorf("got testnet master key %s", got)
+	}
+
+	account, err := master.Derive("m/44'/1'/0'")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the public children of the neutered key are the children of the private key
+	fromPrivate, err := account.Derive("m/0/5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fromPublic, err := account.Neuter().Derive("m/0/5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := mustSerialize(t, fromPublic), mustSerialize(t, fromPrivate.Neuter()); fromPublic.IsPrivate() || got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if fromPrivate.Depth() != 5 || fromPrivate.ChildNumber() != 5 {
+		t.Errorf("got depth %d and child number %d", fromPrivate.Depth(), fromPrivate.ChildNumber())
+	}
+
+	parent, err := account.Derive("m/0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fromPrivate.ParentFingerprint() != parent.Fingerprint() {
+		t.Errorf("got parent fingerprint %08x, want %08x", fromPrivate.ParentFingerprint(), parent.Fingerprint())
+	}
+
+	if s := mustSerialize(t, fromPublic); s[:4] != "tpub" {
+		t.Errorf("got testnet public key %s", s)
+	}
+
+	if _, err := account.Neuter().Child(HardenedOffset); !errors.Is(err, ErrHardenedFromPublic) {
+		t.Errorf("expected error %v, got %v", ErrHardenedFromPublic, err)
+	}
+
+	if _, err := account.Neuter().PrivateKey(); !errors.Is(err, ErrInvalidExtendedKey) {
+		t.Errorf("expected error %v, got %v", ErrInvalidExtendedKey, err)
+	}
+
+	if _, err := NewMaster(make([]byte, 15), Mainnet); !errors.Is(err, ErrInvalidSeed) {
+		t.Errorf("expected error %v, got %v", ErrInvalidSeed, err)
+	}
+}
+
+func TestParseExtendedKeyInvalid(t *testing.T) {
+	// BIP-32 test vector 5
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "pubkey version / prvkey mismatch", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm"},
+		{name: "prvkey version / pubkey mismatch", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH"},
+		{name: "invalid pubkey prefix 04", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn"},
+		{name: "invalid prvkey prefix 04", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ"},
+		{name: "invalid pubkey prefix 01", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6N8ZMMXctdiCjxTNq964yKkwrkBJJwpzZS4HS2fxvyYUA4q2Xe4"},
+		{name: "invalid prvkey prefix 01", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD9y5gkZ6Eq3Rjuahrv17fEQ3Qen6J"},
+		{name: "zero depth with non-zero parent fingerprint (private)", key: "xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv"},
+		{name: "zero depth with non-zero parent fingerprint (public)", key: "xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ"},
+		{name: "zero depth with non-zero index (private)", key: "xprv9s21ZrQH4r4TsiLvyLXqM9P7k1K3EYhA1kkD6xuquB5i39AU8KF42acDyL3qsDbU9NmZn6MsGSUYZEsuoePmjzsB3eFKSUEh3Gu1N3cqVUN"},
+		{name: "zero depth with non-zero index (public)", key: "xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8"},
+		{name: "unknown extended key version", key: "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4"},
+		{name: "private key 0 not in 1..n-1", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx"},
+		{name: "private key n not in 1..n-1", key: "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G"},
+		{name: "invalid pubkey", key: "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY"},
+		{name: "invalid checksum", key: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExtendedKey(tt.key)
+			if !errors.Is(err, ErrInvalidExtendedKey) && !errors.Is(err, ErrUnknownNetwork) {
+				t.Errorf("expected an invalid extended key error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		indices []uint32
+	}{
+		{path: "m", indices: []uint32{}},
+		{path: "m/0", indices: []uint32{0}},
+		{path: "m/44'/0'/0'/0/0", indices: []uint32{HardenedOffset + 44, HardenedOffset, HardenedOffset, 0, 0}},
+		{path: "m/0h/1H/2147483647", indices: []uint32{HardenedOffset, HardenedOffset + 1, 2147483647}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(got, tt.indices) {
+				t.Errorf("got %v, want %v", got, tt.indices)
+			}
+		})
+	}
+
+	if got := FormatPath([]uint32{HardenedOffset + 44, HardenedOffset, HardenedOffset, 0, 7}); got != "m/44'/0'/0'/0/7" {
+		t.Errorf("got %s", got)
+	}
+
+	for _, path := range []string{"", "0/1", "m/", "m/2147483648", "m/-1", "m/1''", "m/a", "M/0", "m/+1"} {
+		if _, err := ParsePath(path); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%q: expected error %v, got %v", path, ErrInvalidPath, err)
+		}
+	}
+}
+
+func mustSerialize(t *testing.T, key *ExtendedKey) string {
+	t.Helper()
+
+	s, err := key.Serialize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return s
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex string %s: %v", s, err)
+	}
+
+	return b
+}
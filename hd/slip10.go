@@ -0,0 +1,66 @@
+package hd
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"github.com/artilugio0/becc"
+)
+
+// slip10Keys are the HMAC keys of the master key derivation of the curves
+// supported by SLIP-10. Ed25519 is not among them, as becc has no Edwards
+// curves.
+var slip10Keys = map[string]string{
+	"secp256k1": "Bitcoin seed",
+	"secp256r1": "Nist256p1 seed",
+}
+
+// NewMasterSLIP10 returns the SLIP-10 master extended private key of the
+// seed for secp256k1 or secp256r1. The seed must be between 16 and 64 bytes
+// long. The key and the chain code are the two halves of
+// HMAC-SHA512(curve key, seed), where the key is "Bitcoin seed" for
+// secp256k1 and "Nist256p1 seed" for secp256r1; while the key is not valid,
+// they are the halves of HMAC-SHA512(curve key, previous output).
+//
+// For secp256k1, the keys derived from it are the keys of NewMaster, except
+// in the unlikely case that BIP-32 finds an invalid key, and serialize as
+// those of the network. The secp256r1 keys have no serialization.
+func NewMasterSLIP10(ecc *becc.ECC, seed []byte, network Network) (*ExtendedKey, error) {
+	curveKey, ok := slip10Keys[ecc.Name()]
+	if !ok {
+		return nil, fmt.Errorf("%w: SLIP-10 is not defined for %s", becc.ErrUnsupportedCurve, ecc.Name())
+	}
+
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("%w: the seed must be between 16 and 64 bytes", ErrInvalidSeed)
+	}
+
+	data := seed
+	for {
+		mac := hmac.New(sha512.New, []byte(curveKey))
+		mac.Write(data)
+		i := mac.Sum(nil)
+
+		k := new(big.Int).SetBytes(i[:32])
+		if k.Sign() == 0 || k.Cmp(ecc.Order()) >= 0 {
+			data = i
+			continue
+		}
+
+		priv, err := ecc.NewPrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ExtendedKey{
+			ecc:       ecc,
+			network:   network,
+			chainCode: i[32:],
+			priv:      &priv,
+			pub:       priv.PublicKey(),
+			retry:     true,
+		}, nil
+	}
+}
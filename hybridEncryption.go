@@ -7,11 +7,31 @@ import (
 	"crypto/hkdf"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io"
 	"slices"
 )
 
+const (
+	hybridInfo          = "becc hybrid file encryption v1"
+	hybridElligatorInfo = "becc hybrid file encryption v1 elligator"
+)
+
 func (pub PublicKey) Encrypt(input io.Reader) ([]byte, error) {
+	return pub.encrypt(input, false)
+}
+
+// EncryptElligator is like Encrypt, but writes the ephemeral public key with
+// PublicKey.Elligator instead of compressed, so that the whole output is
+// indistinguishable from random bytes. The shared secret is the x-coordinate
+// of the shared point, which is all ElligatorSwift keeps on secp256k1, and
+// the keys are derived with a different HKDF info string. The output must be
+// decrypted with PrivateKey.DecryptElligator.
+func (pub PublicKey) EncryptElligator(input io.Reader) ([]byte, error) {
+	return pub.encrypt(input, true)
+}
+
+func (pub PublicKey) encrypt(input io.Reader, elligator bool) ([]byte, error) {
 	ePriv, ePub, err := pub.ecc.GenKeyPair()
 	if err != nil {
 		return nil, err
@@ -22,9 +42,20 @@ func (pub PublicKey) Encrypt(input io.Reader) ([]byte, error) {
 		return nil, err
 	}
 
+	encodedPub := ePub.Compressed()
 	sharedSecret := ePriv.ECDH(pub)
+	info := hybridInfo
+
+	if elligator {
+		encodedPub, err = ePub.Elligator()
+		if err != nil {
+			return nil, err
+		}
+
+		sharedSecret = ePriv.ECDHX(pub)
+		info = hybridElligatorInfo
+	}
 
-	info := "becc hybrid file encryption v1"
 	keyMaterial, err := hkdf.Expand(sha256.New, sharedSecret, info, 32+12)
 	if err != nil {
 		return nil, err
@@ -45,31 +76,63 @@ func (pub PublicKey) Encrypt(input io.Reader) ([]byte, error) {
 
 	ciphertext := aesgcm.Seal(nil, aesNonce, plaintext, nil)
 
-	result := slices.Concat(ePub.Compressed(), aesNonce, ciphertext)
+	result := slices.Concat(encodedPub, aesNonce, ciphertext)
 
 	return result, nil
 }
 
 func (priv PrivateKey) Decrypt(input io.Reader) ([]byte, error) {
+	return priv.decrypt(input, false)
+}
+
+// DecryptElligator decrypts the output of PublicKey.EncryptElligator.
+func (priv PrivateKey) DecryptElligator(input io.Reader) ([]byte, error) {
+	return priv.decrypt(input, true)
+}
+
+func (priv PrivateKey) decrypt(input io.Reader, elligator bool) ([]byte, error) {
 	inputBytes, err := io.ReadAll(input)
 	if err != nil {
 		return nil, err
 	}
 
-	pubKeyLen := 1 + priv.ecc.Security()/4
+	pubKeyLen := 1 + priv.ecc.CoordinateSize()
+	if elligator {
+		pubKeyLen = priv.ecc.ElligatorSize()
+		if pubKeyLen == 0 {
+			return nil, fmt.Errorf("%w: no Elligator encoding for %q", ErrUnsupportedCurve, priv.ecc.name)
+		}
+	}
+
 	nonceLen := 12
-	compressedPub := inputBytes[:pubKeyLen]
+	if len(inputBytes) < pubKeyLen+nonceLen {
+		return nil, errors.New("invalid ciphertext: input too short")
+	}
+
+	encodedPub := inputBytes[:pubKeyLen]
 	aesNonce := inputBytes[pubKeyLen : pubKeyLen+nonceLen]
 	ciphertext := inputBytes[pubKeyLen+nonceLen:]
 
-	pub, err := priv.ecc.NewPublicKeyCompressed(compressedPub)
-	if err != nil {
-		return nil, err
-	}
+	var sharedSecret []byte
+	info := hybridInfo
+
+	if elligator {
+		pub, err := priv.ecc.NewPublicKeyElligator(encodedPub)
+		if err != nil {
+			return nil, err
+		}
 
-	sharedSecret := priv.ECDH(pub)
+		sharedSecret = priv.ECDHX(pub)
+		info = hybridElligatorInfo
+	} else {
+		pub, err := priv.ecc.NewPublicKeyCompressed(encodedPub)
+		if err != nil {
+			return nil, err
+		}
+
+		sharedSecret = priv.ECDH(pub)
+	}
 
-	info := "becc hybrid file encryption v1"
 	keyMaterial, err := hkdf.Expand(sha256.New, sharedSecret, info, 32+12)
 	if err != nil {
 		return nil, err
@@ -2,16 +2,38 @@ package becc
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 )
 
 func TestSecp256k1HybridEncryption(t *testing.T) {
-	ecc := Secp256k1ECC()
+	testHybridEncryption(t, Secp256k1ECC(), 10)
+}
+
+func TestHybridEncryptionAllCurves(t *testing.T) {
+	tt := []struct {
+		name string
+		ecc  *ECC
+	}{
+		{name: "secp256r1", ecc: Secp256r1ECC()},
+		{name: "secp384r1", ecc: Secp384r1ECC()},
+		{name: "secp521r1", ecc: Secp521r1ECC()},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			testHybridEncryption(t, tc.ecc, 2)
+		})
+	}
+}
+
+func testHybridEncryption(t *testing.T, ecc *ECC, rounds int) {
+	t.Helper()
 
 	plaintext := "this is a test plaintext"
 
-	for range 10 {
+	for range rounds {
 		priv, pub, err := ecc.GenKeyPair()
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -32,3 +54,52 @@ func TestSecp256k1HybridEncryption(t *testing.T) {
 		}
 	}
 }
+
+func TestHybridEncryptionElligator(t *testing.T) {
+	plaintext := "this is a test plaintext"
+
+	for _, name := range []string{"secp256k1", "secp256r1", "secp384r1", "secp521r1"} {
+		t.Run(name, func(t *testing.T) {
+			ecc := mustLookupCurve(name)
+
+			priv, pub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ciphertext, err := pub.EncryptElligator(strings.NewReader(plaintext))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// ephemeral key, nonce, plaintext and tag
+			if expected := ecc.ElligatorSize() + 12 + len(plaintext) + 16; len(ciphertext) != expected {
+				t.Errorf("got %d bytes, expected %d", len(ciphertext), expected)
+			}
+
+			decrypted, err := priv.DecryptElligator(bytes.NewReader(ciphertext))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(decrypted) != plaintext {
+				t.Errorf("got '%s', expected '%s'", string(decrypted), plaintext)
+			}
+
+			if _, err := priv.Decrypt(bytes.NewReader(ciphertext)); err == nil {
+				t.Errorf("decrypting without Elligator succeeded")
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, pub, err := BrainpoolP256r1ECC().GenKeyPair()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := pub.EncryptElligator(strings.NewReader(plaintext)); !errors.Is(err, ErrUnsupportedCurve) {
+			t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+		}
+	})
+}
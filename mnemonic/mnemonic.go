@@ -0,0 +1,145 @@
+// Package mnemonic implements BIP-39 mnemonic sentences: the encoding of 128
+// to 256 bits of entropy, with a checksum, as 12 to 24 words of the English
+// wordlist, and the derivation of the 64-byte seed of a sentence, the input
+// of hd.NewMaster.
+package mnemonic
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidEntropy  error = errors.New("invalid entropy")
+	ErrInvalidMnemonic error = errors.New("invalid mnemonic")
+	ErrInvalidChecksum error = errors.New("invalid mnemonic checksum")
+)
+
+// english is the BIP-39 English wordlist.
+//
+//go:embed english.txt
+var english string
+
+var (
+	words     = strings.Fields(english)
+	wordIndex = map[string]int{}
+)
+
+func init() {
+	for i, w := range words {
+		wordIndex[w] = i
+	}
+}
+
+// Words returns the 2048 words of the English wordlist.
+func Words() []string {
+	return append([]string(nil), words...)
+}
+
+// Generate returns a new mnemonic sentence of the given number of words: 12,
+// 15, 18, 21 or 24, for 128 to 256 bits of entropy.
+func Generate(wordCount int) (string, error) {
+	if wordCount < 12 || wordCount > 24 || wordCount%3 != 0 {
+		return "", fmt.Errorf("%w: the number of words must be 12, 15, 18, 21 or 24", ErrInvalidMnemonic)
+	}
+
+	entropy := make([]byte, wordCount/3*4)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+
+	return New(entropy)
+}
+
+// New returns the mnemonic sentence of the entropy, which must be 16 to 32
+// bytes long in steps of 4: the words are the 11-bit groups of
+// entropy || checksum, where the checksum is the first len(entropy)/4 bits of
+// SHA-256(entropy).
+func New(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", fmt.Errorf("%w: the entropy must be 16, 20, 24, 28 or 32 bytes", ErrInvalidEntropy)
+	}
+
+	checksumBits := uint(len(entropy) / 4)
+	h := sha256.Sum256(entropy)
+
+	b := new(big.Int).SetBytes(entropy)
+	b.Lsh(b, checksumBits)
+	b.Or(b, big.NewInt(int64(h[0]>>(8-checksumBits))))
+
+	wordCount := (len(entropy)*8 + int(checksumBits)) / 11
+	sentence := make([]string, wordCount)
+	mask := big.NewInt(2047)
+	for i := wordCount - 1; i >= 0; i-- {
+		sentence[i] = words[new(big.Int).And(b, mask).Int64()]
+		b.Rsh(b, 11)
+	}
+
+	return strings.Join(sentence, " "), nil
+}
+
+// Entropy returns the entropy encoded by the mnemonic sentence, after
+// checking its words and its checksum.
+func Entropy(mnemonic string) ([]byte, error) {
+	sentence := strings.Fields(mnemonic)
+	if len(sentence) < 12 || len(sentence) > 24 || len(sentence)%3 != 0 {
+		return nil, fmt.Errorf("%w: the number of words must be 12, 15, 18, 21 or 24", ErrInvalidMnemonic)
+	}
+
+	b := new(big.Int)
+	for _, w := range sentence {
+		i, ok := wordIndex[w]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, w)
+		}
+
+		b.Lsh(b, 11).Or(b, big.NewInt(int64(i)))
+	}
+
+	checksumBits := uint(len(sentence) / 3)
+	checksum := new(big.Int).And(b, big.NewInt(1<<checksumBits-1))
+	b.Rsh(b, checksumBits)
+
+	entropy := b.FillBytes(make([]byte, len(sentence)/3*4))
+
+	h := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(h[0]>>(8-checksumBits)) {
+		return nil, ErrInvalidChecksum
+	}
+
+	return entropy, nil
+}
+
+// Validate checks the words and the checksum of the mnemonic sentence.
+func Validate(mnemonic string) error {
+	_, err := Entropy(mnemonic)
+
+	return err
+}
+
+// Seed returns the 64-byte seed of the mnemonic sentence and the optional
+// passphrase: PBKDF2-HMAC-SHA512 with 2048 iterations of the sentence, with
+// the salt "mnemonic" || passphrase. As BIP-39 requires, both are brought to
+// Unicode NFKD form first, so that every encoding of the same text gives the
+// same seed.
+func Seed(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = norm.NFKD.String(mnemonic)
+	passphrase = norm.NFKD.String(passphrase)
+
+	if err := Validate(mnemonic); err != nil {
+		return nil, err
+	}
+
+	sentence := strings.Join(strings.Fields(mnemonic), " ")
+
+	return pbkdf2.Key(sha512.New, sentence, []byte("mnemonic"+passphrase), 2048, 64)
+}
@@ -0,0 +1,366 @@
+package becc
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"slices"
+)
+
+// modularPolynomial is Müller's canonical modular polynomial Φ_l(X, J)
+// modulo p, for a prime l >= 3. With s = 12/gcd(12, l - 1) and
+// v = s(l - 1)/12, the function f(τ) = l^s (η(lτ)/η(τ))^2s is a root of
+// Φ_l(X, j(τ)), which has degree l + 1 in X and v in J: a much smaller
+// polynomial than the classical Φ_l(X, j(lτ)), of degree l + 1 in both.
+type modularPolynomial struct {
+	l, s int64
+
+	// coefficients[i] is the coefficient of X^i, a polynomial in J
+	coefficients []poly
+}
+
+// canonicalModularPolynomial returns Φ_l modulo p from the q-expansions of
+// its roots. The other l roots are g_k(τ) = l^s / f((τ + k)/l) for
+// k = 0, ..., l - 1, so with x = q^(1/l) and E(x) = ∏(1 - x^n)^2s
+//
+//	g_0 = x^-v E(x) / E(q)
+//
+// and the power sum of the i-th powers of the roots is a polynomial in j of
+// degree vi/l: f^i only has positive powers of q, and the sum of the g_k^i
+// is l times the terms of g_0^i whose exponent is a multiple of 1/l. Its
+// terms from q^-vi/l to q^0 are enough to read the polynomial, and Newton's
+// identities turn the power sums into the coefficients of Φ_l.
+//
+// E(x)^i is needed up to x^v(l+1), and it is computed from E(x)^(i-1)
+// multiplying by ∏(1 - x^n) and ∏(1 - x^n)^3, whose few terms are given by
+// Euler's pentagonal number theorem and Jacobi's identity. The cost grows as
+// l^(5/2) v^(3/2), which makes the primes with s = 6 (l = 11 mod 12) the most
+// expensive by far.
+func canonicalModularPolynomial(f polyField, l int64) modularPolynomial {
+	s := 12 / gcd64(12, l-1)
+	v := int(s * (l - 1) / 12)
+	n := int(l) + 1
+	top := v * n
+
+	// E(x) = ∏(1 - x^n)^3^(2s/3) ∏(1 - x^n)^(2s mod 3)
+	var factors [][]seriesTerm
+	for range 2 * s / 3 {
+		factors = append(factors, eulerCubeSeries(top))
+	}
+	for range 2 * s % 3 {
+		factors = append(factors, eulerSeries(top))
+	}
+
+	// (q·j)^k up to q^v
+	j := jSeries(f, v)
+	jPowers := []poly{f.constant(bi1)}
+	for k := 1; k <= v; k++ {
+		jPowers = append(jPowers, truncate(f.mul(jPowers[k-1], j), v+1))
+	}
+
+	bl := big.NewInt(l)
+
+	// the coefficients of E(x)^i, as words for mulSparse
+	w := len(f.p.Bits())
+	words := make([]big.Word, (top+1)*w)
+	e := make([][]big.Word, top+1)
+	for i := range e {
+		e[i] = words[i*w : (i+1)*w]
+	}
+	e[0][0] = 1
+
+	powerSums := make([]poly, n+1)
+	for i := 1; i <= n; i++ {
+		for _, factor := range factors {
+			f.mulSparse(e, factor)
+		}
+
+		// l Σ e[vi + l·k] q^k for -d <= k <= 0, times E(q)^-i
+		d := v * i / int(l)
+		principal := make(poly, d+1)
+		for k := range principal {
+			principal[k] = new(big.Int).SetBits(slices.Clone(e[v*i-int(l)*(d-k)]))
+			principal[k].Mul(principal[k], bl).Mod(principal[k], f.p)
+		}
+		laurent := make([]*big.Int, d+1)
+		for k, c := range f.mul(principal.normalize(), eulerPowerSeries(f, -2*s*int64(i), d)) {
+			if k <= d {
+				laurent[k] = c
+			}
+		}
+		for k := range laurent {
+			if laurent[k] == nil {
+				laurent[k] = new(big.Int)
+			}
+		}
+
+		// subtract the powers of j from the highest one down: laurent[m] is
+		// the coefficient of q^(m-d) and jPowers[k][m] that of q^(m-k) in j^k
+		coefficients := make([]*big.Int, d+1)
+		for k := d; k >= 0; k-- {
+			c := new(big.Int).Set(laurent[d-k])
+			coefficients[k] = c
+
+			for m := 0; m <= k && m < len(jPowers[k]); m++ {
+				idx := m + d - k
+				laurent[idx].Sub(laurent[idx], new(big.Int).Mul(c, jPowers[k][m])).Mod(laurent[idx], f.p)
+			}
+		}
+		powerSums[i] = f.newPoly(coefficients...)
+	}
+
+	// Newton's identities: r e_r = Σ (-1)^(m-1) e_(r-m) p_m
+	elementary := []poly{f.constant(bi1)}
+	for r := 1; r <= n; r++ {
+		var sum poly
+		for m := 1; m <= r; m++ {
+			term := f.mul(elementary[r-m], powerSums[m])
+			if m%2 == 0 {
+				sum = f.sub(sum, term)
+			} else {
+				sum = f.add(sum, term)
+			}
+		}
+		elementary = append(elementary, f.scale(sum, new(big.Int).ModInverse(big.NewInt(int64(r)), f.p)))
+	}
+
+	coefficients := make([]poly, n+1)
+	for r, c := range elementary {
+		if r%2 == 1 {
+			c = f.neg(c)
+		}
+		coefficients[n-r] = c
+	}
+
+	return modularPolynomial{l: l, s: s, coefficients: coefficients}
+}
+
+// modularCost estimates the cost of canonicalModularPolynomial for l as
+// l^(5/2) v^(3/2).
+func modularCost(l int64) float64 {
+	s := 12 / gcd64(12, l-1)
+	v := float64(s*(l-1)) / 12
+
+	return math.Pow(float64(l), 2.5) * math.Pow(v, 1.5)
+}
+
+// atJ returns Φ_l(X, j) as a polynomial in X.
+func (m modularPolynomial) atJ(f polyField, j *big.Int) poly {
+	c := make([]*big.Int, len(m.coefficients))
+	for i, cj := range m.coefficients {
+		c[i] = f.evaluate(cj, j)
+	}
+
+	return f.newPoly(c...)
+}
+
+// partial returns the partial derivative ∂^(dx+dj) Φ_l / ∂X^dx ∂J^dj at
+// (x, j).
+func (m modularPolynomial) partial(f polyField, x, j *big.Int, dx, dj int) *big.Int {
+	return f.evaluate(f.derivative(m.atDerivativeJ(f, j, dj), dx), x)
+}
+
+// atDerivativeJ returns ∂^dj Φ_l / ∂J^dj at J = j as a polynomial in X.
+func (m modularPolynomial) atDerivativeJ(f polyField, j *big.Int, dj int) poly {
+	c := make([]*big.Int, len(m.coefficients))
+	for i, cj := range m.coefficients {
+		c[i] = f.evaluate(f.derivative(cj, dj), j)
+	}
+
+	return f.newPoly(c...)
+}
+
+// evaluate returns a(x).
+func (f polyField) evaluate(a poly, x *big.Int) *big.Int {
+	result := new(big.Int)
+	for i := len(a) - 1; i >= 0; i-- {
+		result.Mul(result, x).Add(result, a[i]).Mod(result, f.p)
+	}
+
+	return result
+}
+
+// derivative returns the k-th derivative of a.
+func (f polyField) derivative(a poly, k int) poly {
+	for range k {
+		if a.isZero() {
+			return a
+		}
+
+		d := make([]*big.Int, len(a)-1)
+		for i := range d {
+			d[i] = new(big.Int).Mul(a[i+1], big.NewInt(int64(i+1)))
+		}
+		a = f.newPoly(d...)
+	}
+
+	return a
+}
+
+// seriesTerm is a non-zero term c·x^n of a power series with few of them.
+type seriesTerm struct {
+	n int
+	c int64
+}
+
+// eulerSeries returns the terms of ∏(1 - x^n) up to x^top, which by the
+// pentagonal number theorem is the sum of (-1)^k x^(k(3k-1)/2) over the
+// integers k.
+func eulerSeries(top int) []seriesTerm {
+	terms := []seriesTerm{{n: 0, c: 1}}
+	for k := 1; k*(3*k-1)/2 <= top; k++ {
+		c := int64(1)
+		if k%2 == 1 {
+			c = -1
+		}
+
+		terms = append(terms, seriesTerm{n: k * (3*k - 1) / 2, c: c})
+		if n := k * (3*k + 1) / 2; n <= top {
+			terms = append(terms, seriesTerm{n: n, c: c})
+		}
+	}
+
+	return terms
+}
+
+// eulerCubeSeries returns the terms of ∏(1 - x^n)^3 up to x^top, which by
+// Jacobi's identity is the sum of (-1)^k (2k + 1) x^(k(k+1)/2) for k >= 0.
+func eulerCubeSeries(top int) []seriesTerm {
+	var terms []seriesTerm
+	for k := 0; k*(k+1)/2 <= top; k++ {
+		c := int64(2*k + 1)
+		if k%2 == 1 {
+			c = -c
+		}
+
+		terms = append(terms, seriesTerm{n: k * (k + 1) / 2, c: c})
+	}
+
+	return terms
+}
+
+// mulSparse sets a to a·b mod x^len(a), for b with a constant term. The
+// coefficients of a are reduced modulo p and stored in as many words as p,
+// and the products by the small coefficients of b are added up in words
+// too: this is the inner loop of canonicalModularPolynomial, and big.Int
+// arithmetic makes it several times slower.
+func (f polyField) mulSparse(a [][]big.Word, b []seriesTerm) {
+	// the sums of the positive and the negative terms, with two extra words
+	// for their carries
+	w := len(f.p.Bits())
+	pos, neg := make([]big.Word, w+2), make([]big.Word, w+2)
+	x, y := new(big.Int), new(big.Int)
+
+	// a[n] only depends on a[m] for m <= n
+	for n := len(a) - 1; n >= 0; n-- {
+		clear(pos)
+		clear(neg)
+		for _, term := range b {
+			if term.n > n {
+				break
+			}
+
+			if term.c > 0 {
+				mulAddWords(pos, a[n-term.n], big.Word(term.c))
+			} else {
+				mulAddWords(neg, a[n-term.n], big.Word(-term.c))
+			}
+		}
+
+		x.Sub(x.SetBits(pos), y.SetBits(neg)).Mod(x, f.p)
+		clear(a[n])
+		copy(a[n], x.Bits())
+
+		// x shared the words of pos
+		x = new(big.Int)
+	}
+}
+
+// mulAddWords sets acc to acc + x·c, where acc has more words than x and
+// the sum does not overflow.
+func mulAddWords(acc, x []big.Word, c big.Word) {
+	var carry uint
+	for i, xi := range x {
+		hi, lo := bits.Mul(uint(xi), uint(c))
+
+		var cc uint
+		lo, cc = bits.Add(lo, uint(acc[i]), 0)
+		hi += cc
+		lo, cc = bits.Add(lo, carry, 0)
+		hi += cc
+
+		acc[i] = big.Word(lo)
+		carry = hi
+	}
+
+	for i := len(x); carry != 0; i++ {
+		var s uint
+		s, carry = bits.Add(uint(acc[i]), carry, 0)
+		acc[i] = big.Word(s)
+	}
+}
+
+// eulerPowerSeries returns ∏(1 - x^n)^k up to x^top for any integer k, from
+// the recurrence m c_m = -k Σ σ(i) c_(m-i) of its logarithmic derivative.
+func eulerPowerSeries(f polyField, k int64, top int) poly {
+	sigma := divisorSums(top, 1)
+
+	c := make([]*big.Int, top+1)
+	c[0] = big.NewInt(1)
+	for m := 1; m <= top; m++ {
+		sum := new(big.Int)
+		for i := 1; i <= m; i++ {
+			sum.Add(sum, new(big.Int).Mul(sigma[i], c[m-i]))
+		}
+
+		sum.Mul(sum, big.NewInt(-k))
+		sum.Mul(sum, new(big.Int).ModInverse(big.NewInt(int64(m)), f.p))
+		c[m] = sum.Mod(sum, f.p)
+	}
+
+	return f.newPoly(c...)
+}
+
+// jSeries returns q·j(q) = q E4^3 / Δ up to q^top, where
+// E4 = 1 + 240 Σ σ3(n) q^n and Δ = q ∏(1 - q^n)^24, so that the coefficient
+// of q^k in j is at index k + 1.
+func jSeries(f polyField, top int) poly {
+	sigma3 := divisorSums(top, 3)
+
+	c := make([]*big.Int, top+1)
+	c[0] = big.NewInt(1)
+	for m := 1; m <= top; m++ {
+		c[m] = new(big.Int).Mul(sigma3[m], big.NewInt(240))
+	}
+
+	e4 := f.newPoly(c...)
+	e4Cube := truncate(f.mul(f.mul(e4, e4), e4), top+1)
+
+	return truncate(f.mul(e4Cube, eulerPowerSeries(f, -24, top)), top+1)
+}
+
+// divisorSums returns σ_k(m), the sum of the k-th powers of the divisors of
+// m, for m <= top.
+func divisorSums(top int, k int) []*big.Int {
+	sigma := make([]*big.Int, top+1)
+	for i := range sigma {
+		sigma[i] = new(big.Int)
+	}
+
+	for d := 1; d <= top; d++ {
+		dk := new(big.Int).Exp(big.NewInt(int64(d)), big.NewInt(int64(k)), nil)
+		for m := d; m <= top; m += d {
+			sigma[m].Add(sigma[m], dk)
+		}
+	}
+
+	return sigma
+}
+
+func gcd64(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
@@ -0,0 +1,56 @@
+package becc
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestCanonicalModularPolynomial(t *testing.T) {
+	f := polyField{p: mustLookupCurve("secp256r1").Curve().P()}
+	x := func(coefficients ...int64) poly {
+		c := make([]*big.Int, len(coefficients))
+		for i, ci := range coefficients {
+			c[i] = big.NewInt(ci)
+		}
+
+		return f.newPoly(c...)
+	}
+	cube := func(a poly) poly {
+		return f.mul(a, f.mul(a, a))
+	}
+
+	// the closed forms of the canonical modular polynomials of Müller's thesis
+	// for l = 5 and 7 are P(X) - JX
+	tt := []struct {
+		l, s     int64
+		expected poly
+	}{
+		{l: 5, s: 3, expected: cube(x(5, 10, 1))},
+		{l: 7, s: 2, expected: f.mul(x(49, 13, 1), cube(x(1, 5, 1)))},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("l = %d", tc.l), func(t *testing.T) {
+			phi := canonicalModularPolynomial(f, tc.l)
+			if phi.l != tc.l || phi.s != tc.s {
+				t.Fatalf("got l = %d, s = %d, expected l = %d, s = %d", phi.l, phi.s, tc.l, tc.s)
+			}
+
+			if len(phi.coefficients) != len(tc.expected) {
+				t.Fatalf("got degree %d in X, expected %d", len(phi.coefficients)-1, len(tc.expected)-1)
+			}
+
+			for i, c := range phi.coefficients {
+				expected := f.constant(tc.expected[i])
+				if i == 1 {
+					expected = f.add(expected, x(0, -1))
+				}
+
+				if !c.equal(expected) {
+					t.Errorf("coefficient of X^%d: got %v, expected %v", i, c, expected)
+				}
+			}
+		})
+	}
+}
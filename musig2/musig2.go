@@ -0,0 +1,514 @@
+// Package musig2 implements MuSig2 (BIP-327), the n-of-n multi-signature
+// scheme for secp256k1 whose signatures are ordinary BIP-340 Schnorr
+// signatures for the aggregate public key:
+//
+//  1. KeyAgg combines the public keys of the signers into the aggregate key,
+//     optionally tweaked with KeyAggContext.ApplyTweak (as in BIP-32 or
+//     Taproot)
+//  2. every signer calls NonceGen and sends its PublicNonce to the others,
+//     and NonceAgg combines them
+//  3. every signer calls Session.Sign with its SecretNonce, and
+//     Session.Aggregate combines the partial signatures
+//
+// A SecretNonce must never be used twice: Sign erases it.
+package musig2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/artilugio0/becc"
+)
+
+var (
+	ErrInvalidNonce            error = errors.New("invalid nonce")
+	ErrInvalidPartialSignature error = errors.New("invalid partial signature")
+	ErrInvalidTweak            error = errors.New("invalid tweak")
+	ErrUnknownSigner           error = errors.New("signer not in the aggregate key")
+)
+
+// KeyAggContext is the aggregate public key Q of a list of signers, with the
+// accumulated tweaks: gacc, the product of the negations of Q, and tacc, the
+// sum of the tweaks.
+type KeyAggContext struct {
+	ecc        *becc.ECC
+	pubkeys    [][]byte
+	hashKeys   []byte
+	secondKey  []byte
+	q          becc.PublicKey
+	gacc, tacc *big.Int
+}
+
+// KeySort returns the public keys sorted by their compressed encoding, the
+// order that makes the aggregate key independent of the order of the signers.
+func KeySort(pubs []becc.PublicKey) []becc.PublicKey {
+	sorted := slices.Clone(pubs)
+	slices.SortFunc(sorted, func(a, b becc.PublicKey) int {
+		return bytes.Compare(a.Compressed(), b.Compressed())
+	})
+
+	return sorted
+}
+
+// KeyAgg returns the aggregate key of the public keys, in the given order:
+// Q = Σ a_i·P_i, with the coefficient a_i derived from the hash of all the
+// keys, except for the second distinct key, whose coefficient is 1.
+func KeyAgg(pubs []becc.PublicKey) (*KeyAggContext, error) {
+	if len(pubs) == 0 {
+		return nil, fmt.Errorf("%w: no public keys", becc.ErrInvalidParameters)
+	}
+
+	ecc := becc.Secp256k1ECC()
+
+	ctx := &KeyAggContext{
+		ecc:  ecc,
+		gacc: big.NewInt(1),
+		tacc: new(big.Int),
+	}
+
+	for i, pub := range pubs {
+		if pub.ECC().Name() != ecc.Name() {
+			return nil, fmt.Errorf("%w: public key %d is not a secp256k1 key", becc.ErrUnsupportedCurve, i)
+		}
+
+		ctx.pubkeys = append(ctx.pubkeys, pub.Compressed())
+	}
+
+	ctx.hashKeys = becc.TaggedHash("KeyAgg list", ctx.pubkeys...)
+	for _, pk := range ctx.pubkeys[1:] {
+		if !bytes.Equal(pk, ctx.pubkeys[0]) {
+			ctx.secondKey = pk
+			break
+		}
+	}
+
+	q := ecc.Curve().Infinity()
+	for i, pub := range pubs {
+		q = q.Add(pub.Point().ScalarMul(ctx.coefficient(ctx.pubkeys[i])))
+	}
+
+	aggregate, err := ecc.NewPublicKey(q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: the aggregate key is invalid", becc.ErrInvalidPublicKey)
+	}
+	ctx.q = aggregate
+
+	return ctx, nil
+}
+
+// PublicKey returns the aggregate public key Q, with its tweaks.
+func (ctx *KeyAggContext) PublicKey() becc.PublicKey {
+	return ctx.q
+}
+
+// XOnlyPublicKey returns the x-only encoding of Q, the BIP-340 public key
+// that verifies the aggregate signatures.
+func (ctx *KeyAggContext) XOnlyPublicKey() []byte {
+	return ctx.q.XOnly()
+}
+
+// ApplyTweak returns the context of the key tweaked with the 32-byte tweak
+// t: Q + t·G for a plain tweak, as in BIP-32, or Q' + t·G for an x-only
+// tweak, as in Taproot, where Q' is the point of x(Q) with an even y.
+func (ctx *KeyAggContext) ApplyTweak(tweak []byte, xOnly bool) (*KeyAggContext, error) {
+	n := ctx.ecc.Order()
+
+	if len(tweak) != 32 {
+		return nil, fmt.Errorf("%w: the tweak must be 32 bytes", ErrInvalidTweak)
+	}
+
+	t := new(big.Int).SetBytes(tweak)
+	if t.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("%w: the tweak must be less than n", ErrInvalidTweak)
+	}
+
+	g := big.NewInt(1)
+	if xOnly && !hasEvenY(ctx.q.Point()) {
+		g.Sub(n, g)
+	}
+
+	q := ctx.q.Point().ScalarMul(g).Add(ctx.ecc.Generator().ScalarMul(t))
+	tweaked, err := ctx.ecc.NewPublicKey(q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: the tweaked key is the point at infinity", ErrInvalidTweak)
+	}
+
+	gacc := new(big.Int).Mul(g, ctx.gacc)
+	gacc.Mod(gacc, n)
+
+	tacc := new(big.Int).Mul(g, ctx.tacc)
+	tacc.Add(tacc, t).Mod(tacc, n)
+
+	result := *ctx
+	result.q, result.gacc, result.tacc = tweaked, gacc, tacc
+
+	return &result, nil
+}
+
+// coefficient returns the key aggregation coefficient of the compressed key
+// pk, which must be one of the keys of the context.
+func (ctx *KeyAggContext) coefficient(pk []byte) *big.Int {
+	if bytes.Equal(pk, ctx.secondKey) {
+		return big.NewInt(1)
+	}
+
+	a := new(big.Int).SetBytes(becc.TaggedHash("KeyAgg coefficient", ctx.hashKeys, pk))
+
+	return a.Mod(a, ctx.ecc.Order())
+}
+
+func (ctx *KeyAggContext) contains(pk []byte) bool {
+	return slices.ContainsFunc(ctx.pubkeys, func(k []byte) bool { return bytes.Equal(k, pk) })
+}
+
+// SecretNonce is the secret part of the nonce of a signer, with the public
+// key it was generated for.
+type SecretNonce struct {
+	k1, k2 *big.Int
+	pk     []byte
+}
+
+// PublicNonce is the pair of points R1 = k1·G, R2 = k2·G that a signer sends
+// to the others.
+type PublicNonce struct {
+	r1, r2 becc.Point
+}
+
+// ParsePublicNonce parses the 66-byte encoding of a public nonce, two
+// compressed points.
+func ParsePublicNonce(b []byte) (PublicNonce, error) {
+	if len(b) != 66 {
+		return PublicNonce{}, fmt.Errorf("%w: invalid length", ErrInvalidNonce)
+	}
+
+	ecc := becc.Secp256k1ECC()
+
+	r1, err := ecc.NewPublicKeyCompressed(b[:33])
+	if err != nil {
+		return PublicNonce{}, fmt.Errorf("%w: %w", ErrInvalidNonce, err)
+	}
+
+	r2, err := ecc.NewPublicKeyCompressed(b[33:])
+	if err != nil {
+		return PublicNonce{}, fmt.Errorf("%w: %w", ErrInvalidNonce, err)
+	}
+
+	return PublicNonce{r1: r1.Point(), r2: r2.Point()}, nil
+}
+
+// Bytes returns the 66-byte encoding of the nonce.
+func (pn PublicNonce) Bytes() []byte {
+	return slices.Concat(encodePoint(pn.r1), encodePoint(pn.r2))
+}
+
+// AggregateNonce is the sum of the public nonces of all the signers. Unlike
+// those, either of its points may be the point at infinity.
+type AggregateNonce struct {
+	r1, r2 becc.Point
+}
+
+// ParseAggregateNonce parses the 66-byte encoding of an aggregate nonce, two
+// compressed points where 33 zero bytes stand for the point at infinity.
+func ParseAggregateNonce(b []byte) (AggregateNonce, error) {
+	if len(b) != 66 {
+		return AggregateNonce{}, fmt.Errorf("%w: invalid length", ErrInvalidNonce)
+	}
+
+	ecc := becc.Secp256k1ECC()
+
+	var points [2]becc.Point
+	for i := range points {
+		encoded := b[33*i : 33*(i+1)]
+		if bytes.Equal(encoded, make([]byte, 33)) {
+			points[i] = ecc.Curve().Infinity()
+			continue
+		}
+
+		p, err := ecc.NewPublicKeyCompressed(encoded)
+		if err != nil {
+			return AggregateNonce{}, fmt.Errorf("%w: %w", ErrInvalidNonce, err)
+		}
+
+		points[i] = p.Point()
+	}
+
+	return AggregateNonce{r1: points[0], r2: points[1]}, nil
+}
+
+// Bytes returns the 66-byte encoding of the aggregate nonce.
+func (an AggregateNonce) Bytes() []byte {
+	return slices.Concat(encodePointExt(an.r1), encodePointExt(an.r2))
+}
+
+// NonceGenOptions are the optional inputs of NonceGen. Each one that is known
+// when the nonce is generated makes it more robust against a bad random
+// number generator. A nil Message means that the message is not known, which
+// differs from an empty one.
+type NonceGenOptions struct {
+	PrivateKey   *becc.PrivateKey
+	AggregateKey []byte
+	Message      []byte
+	ExtraInput   []byte
+}
+
+// NonceGen returns a fresh nonce for the signer with the public key pub.
+func NonceGen(pub becc.PublicKey, opts NonceGenOptions) (*SecretNonce, PublicNonce, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, PublicNonce{}, err
+	}
+
+	return nonceGen(random, pub, opts)
+}
+
+func nonceGen(random []byte, pub becc.PublicKey, opts NonceGenOptions) (*SecretNonce, PublicNonce, error) {
+	ecc := becc.Secp256k1ECC()
+	if pub.ECC().Name() != ecc.Name() {
+		return nil, PublicNonce{}, fmt.Errorf("%w: not a secp256k1 key", becc.ErrUnsupportedCurve)
+	}
+
+	if opts.PrivateKey != nil {
+		random = xorBytes(opts.PrivateKey.Bytes(), becc.TaggedHash("MuSig/aux", random))
+	}
+
+	msgPrefixed := []byte{0}
+	if opts.Message != nil {
+		msgPrefixed = slices.Concat([]byte{1}, binary.BigEndian.AppendUint64(nil, uint64(len(opts.Message))), opts.Message)
+	}
+
+	pk := pub.Compressed()
+
+	var k [2]*big.Int
+	for i := range k {
+		h := becc.TaggedHash("MuSig/nonce",
+			random,
+			[]byte{byte(len(pk))}, pk,
+			[]byte{byte(len(opts.AggregateKey))}, opts.AggregateKey,
+			msgPrefixed,
+			binary.BigEndian.AppendUint32(nil, uint32(len(opts.ExtraInput))), opts.ExtraInput,
+			[]byte{byte(i)},
+		)
+
+		k[i] = new(big.Int).SetBytes(h)
+		k[i].Mod(k[i], ecc.Order())
+		if k[i].Sign() == 0 {
+			return nil, PublicNonce{}, fmt.Errorf("%w: zero nonce", ErrInvalidNonce)
+		}
+	}
+
+	g := ecc.Generator()
+	secret := &SecretNonce{k1: k[0], k2: k[1], pk: pk}
+
+	return secret, PublicNonce{r1: g.ScalarMul(k[0]), r2: g.ScalarMul(k[1])}, nil
+}
+
+// NonceAgg returns the aggregate nonce of the public nonces of all the
+// signers.
+func NonceAgg(nonces []PublicNonce) AggregateNonce {
+	inf := becc.Secp256k1ECC().Curve().Infinity()
+	result := AggregateNonce{r1: inf, r2: inf}
+
+	for _, n := range nonces {
+		result.r1 = result.r1.Add(n.r1)
+		result.r2 = result.r2.Add(n.r2)
+	}
+
+	return result
+}
+
+// PartialSignature is the share s_i of the signature computed by one signer.
+type PartialSignature struct {
+	s *big.Int
+}
+
+// ParsePartialSignature parses the 32-byte encoding of a partial signature.
+func ParsePartialSignature(b []byte) (PartialSignature, error) {
+	if len(b) != 32 {
+		return PartialSignature{}, fmt.Errorf("%w: invalid length", ErrInvalidPartialSignature)
+	}
+
+	s := new(big.Int).SetBytes(b)
+	if s.Cmp(becc.Secp256k1ECC().Order()) >= 0 {
+		return PartialSignature{}, fmt.Errorf("%w: out of range", ErrInvalidPartialSignature)
+	}
+
+	return PartialSignature{s: s}, nil
+}
+
+// Bytes returns the 32-byte encoding of the partial signature.
+func (ps PartialSignature) Bytes() []byte {
+	return ps.s.FillBytes(make([]byte, 32))
+}
+
+// Session is the signing session of one message with one aggregate nonce.
+type Session struct {
+	keyAgg *KeyAggContext
+	msg    []byte
+	b, e   *big.Int
+	r      becc.Point
+}
+
+// NewSession returns the session that signs msg with the aggregate key of ctx
+// and the aggregate nonce.
+func (ctx *KeyAggContext) NewSession(aggNonce AggregateNonce, msg []byte) *Session {
+	ecc := ctx.ecc
+	n := ecc.Order()
+
+	b := new(big.Int).SetBytes(becc.TaggedHash("MuSig/noncecoef", aggNonce.Bytes(), ctx.XOnlyPublicKey(), msg))
+	b.Mod(b, n)
+
+	// R = R1 + b·R2, or G if that is the point at infinity
+	r := aggNonce.r1.Add(aggNonce.r2.ScalarMul(b))
+	if r.IsInfinity() {
+		r = ecc.Generator()
+	}
+
+	e := new(big.Int).SetBytes(becc.TaggedHash("BIP0340/challenge", encodeX(r), ctx.XOnlyPublicKey(), msg))
+	e.Mod(e, n)
+
+	return &Session{keyAgg: ctx, msg: bytes.Clone(msg), b: b, e: e, r: r}
+}
+
+// Sign returns the partial signature of the signer with the private key priv
+// and the secret nonce, which is erased so that it cannot be used again.
+func (s *Session) Sign(secNonce *SecretNonce, priv becc.PrivateKey) (PartialSignature, error) {
+	ctx := s.keyAgg
+	n := ctx.ecc.Order()
+
+	if secNonce == nil || secNonce.k1 == nil {
+		return PartialSignature{}, fmt.Errorf("%w: the secret nonce was already used", ErrInvalidNonce)
+	}
+
+	k1, k2 := secNonce.k1, secNonce.k2
+	secNonce.k1, secNonce.k2 = nil, nil
+
+	if k1.Sign() <= 0 || k1.Cmp(n) >= 0 || k2.Sign() <= 0 || k2.Cmp(n) >= 0 {
+		return PartialSignature{}, fmt.Errorf("%w: the secret nonce is out of range", ErrInvalidNonce)
+	}
+
+	pub := priv.PublicKey()
+	pk := pub.Compressed()
+	if !bytes.Equal(pk, secNonce.pk) {
+		return PartialSignature{}, fmt.Errorf("%w: the secret nonce was generated for another key", ErrInvalidNonce)
+	}
+
+	if !ctx.contains(pk) {
+		return PartialSignature{}, ErrUnknownSigner
+	}
+
+	pubNonce := PublicNonce{r1: ctx.ecc.Generator().ScalarMul(k1), r2: ctx.ecc.Generator().ScalarMul(k2)}
+
+	if !hasEvenY(s.r) {
+		k1 = new(big.Int).Sub(n, k1)
+		k2 = new(big.Int).Sub(n, k2)
+	}
+
+	// d = g·gacc·d' and s = k1 + b·k2 + e·a·d
+	d := new(big.Int).Mul(ctx.gacc, priv.Int())
+	if !hasEvenY(ctx.q.Point()) {
+		d.Neg(d)
+	}
+
+	sig := new(big.Int).Mul(s.e, ctx.coefficient(pk))
+	sig.Mul(sig, d)
+	sig.Add(sig, new(big.Int).Mul(s.b, k2))
+	sig.Add(sig, k1).Mod(sig, n)
+
+	psig := PartialSignature{s: sig}
+	if !s.VerifyPartial(psig, pubNonce, pub) {
+		return PartialSignature{}, errors.New("partial signature verification failed")
+	}
+
+	return psig, nil
+}
+
+// VerifyPartial reports whether psig is the valid partial signature of the
+// signer with the public key pub and the public nonce pubNonce, so that the
+// aggregator can find out which signer misbehaved.
+func (s *Session) VerifyPartial(psig PartialSignature, pubNonce PublicNonce, pub becc.PublicKey) bool {
+	ctx := s.keyAgg
+	n := ctx.ecc.Order()
+
+	pk := pub.Compressed()
+	if psig.s == nil || psig.s.Cmp(n) >= 0 || !ctx.contains(pk) {
+		return false
+	}
+
+	// Re = R1 + b·R2, negated if R has an odd y
+	re := pubNonce.r1.Add(pubNonce.r2.ScalarMul(s.b))
+	if !hasEvenY(s.r) {
+		re = re.Neg()
+	}
+
+	// P' = g·gacc·P
+	g := new(big.Int).Set(ctx.gacc)
+	if !hasEvenY(ctx.q.Point()) {
+		g.Sub(n, g)
+	}
+	p := pub.Point().ScalarMul(g)
+
+	// s·G = Re + e·a·P'
+	ea := new(big.Int).Mul(s.e, ctx.coefficient(pk))
+	ea.Mod(ea, n)
+
+	return ctx.ecc.Generator().ScalarMul(psig.s).Eq(re.Add(p.ScalarMul(ea)))
+}
+
+// Aggregate returns the BIP-340 signature of the session, the sum of the
+// partial signatures of all the signers and of the tweaks.
+func (s *Session) Aggregate(psigs []PartialSignature) (becc.SchnorrSignature, error) {
+	ctx := s.keyAgg
+	n := ctx.ecc.Order()
+
+	// s = Σ s_i + e·g·tacc
+	sum := new(big.Int).Mul(s.e, ctx.tacc)
+	if !hasEvenY(ctx.q.Point()) {
+		sum.Neg(sum)
+	}
+
+	for i, psig := range psigs {
+		if psig.s == nil || psig.s.Cmp(n) >= 0 {
+			return becc.SchnorrSignature{}, fmt.Errorf("%w: signer %d", ErrInvalidPartialSignature, i)
+		}
+
+		sum.Add(sum, psig.s)
+	}
+	sum.Mod(sum, n)
+
+	return becc.NewSchnorrSignature(new(big.Int).Set(s.r.X()), sum), nil
+}
+
+func hasEvenY(p becc.Point) bool {
+	return p.Y().Bit(0) == 0
+}
+
+func encodeX(p becc.Point) []byte {
+	return p.X().FillBytes(make([]byte, 32))
+}
+
+func encodePoint(p becc.Point) []byte {
+	return slices.Concat([]byte{byte(2 + p.Y().Bit(0))}, encodeX(p))
+}
+
+// encodePointExt encodes the point at infinity as 33 zero bytes.
+func encodePointExt(p becc.Point) []byte {
+	if p.IsInfinity() {
+		return make([]byte, 33)
+	}
+
+	return encodePoint(p)
+}
+
+func xorBytes(a, b []byte) []byte {
+	result := make([]byte, len(a))
+	for i := range a {
+		result[i] = a[i] ^ b[i]
+	}
+
+	return result
+}
@@ -0,0 +1,726 @@
+package musig2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/artilugio0/becc"
+)
+
+// The test vectors of BIP-327, in testdata.
+
+type vectorError struct {
+	Type    string `json:"type"`
+	Signer  *int   `json:"signer"`
+	Contrib string `json:"contrib"`
+	Message string `json:"message"`
+}
+
+func TestKeySort(t *testing.T) {
+	var v struct {
+		Pubkeys       []string `json:"pubkeys"`
+		SortedPubkeys []string `json:"sorted_pubkeys"`
+	}
+	loadVectors(t, "key_sort_vectors.json", &v)
+
+	pubs := make([]becc.PublicKey, len(v.Pubkeys))
+	for i, pk := range v.Pubkeys {
+		pubs[i] = mustParsePublicKey(t, pk)
+	}
+
+	for i, pub := range KeySort(pubs) {
+		if got := strings.ToUpper(hex.EncodeToString(pub.Compressed())); got != v.SortedPubkeys[i] {
+			t.Errorf("key %d: got %s, want %s", i, got, v.SortedPubkeys[i])
+		}
+	}
+}
+
+func TestKeyAgg(t *testing.T) {
+	var v struct {
+		Pubkeys        []string `json:"pubkeys"`
+		Tweaks         []string `json:"tweaks"`
+		ValidTestCases []struct {
+			KeyIndices []int  `json:"key_indices"`
+			Expected   string `json:"expected"`
+		} `json:"valid_test_cases"`
+		ErrorTestCases []struct {
+			KeyIndices   []int       `json:"key_indices"`
+			TweakIndices []int       `json:"tweak_indices"`
+			IsXOnly      []bool      `json:"is_xonly"`
+			Error        vectorError `json:"error"`
+			Comment      string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "key_agg_vectors.json", &v)
+
+	for i, tc := range v.ValidTestCases {
+		pubs := make([]becc.PublicKey, len(tc.KeyIndices))
+		for j, k := range tc.KeyIndices {
+			pubs[j] = mustParsePublicKey(t, v.Pubkeys[k])
+		}
+
+		ctx, err := KeyAgg(pubs)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		if got := strings.ToUpper(hex.EncodeToString(ctx.XOnlyPublicKey())); got != tc.Expected {
+			t.Errorf("valid case %d: got %s, want %s", i, got, tc.Expected)
+		}
+	}
+
+	for _, tc := range v.ErrorTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			pubs, err := parsePublicKeys(v.Pubkeys, tc.KeyIndices)
+			if tc.Error.Contrib == "pubkey" {
+				if err == nil {
+					t.Fatalf("expected an invalid public key error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, err := KeyAgg(pubs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for j, k := range tc.TweakIndices {
+				if ctx, err = ctx.ApplyTweak(hexBytes(t, v.Tweaks[k]), tc.IsXOnly[j]); err != nil {
+					break
+				}
+			}
+
+			if !errors.Is(err, ErrInvalidTweak) {
+				t.Errorf("expected error %v, got %v", ErrInvalidTweak, err)
+			}
+		})
+	}
+}
+
+func TestNonceGen(t *testing.T) {
+	var v struct {
+		TestCases []struct {
+			Rand     string  `json:"rand_"`
+			SK       *string `json:"sk"`
+			PK       string  `json:"pk"`
+			AggPK    *string `json:"aggpk"`
+			Msg      *string `json:"msg"`
+			ExtraIn  *string `json:"extra_in"`
+			Expected string  `json:"expected"`
+		} `json:"test_cases"`
+	}
+	loadVectors(t, "nonce_gen_vectors.json", &v)
+
+	optional := func(s *string) []byte {
+		if s == nil {
+			return nil
+		}
+		return hexBytes(t, *s)
+	}
+
+	for i, tc := range v.TestCases {
+		opts := NonceGenOptions{
+			AggregateKey: optional(tc.AggPK),
+			Message:      optional(tc.Msg),
+			ExtraInput:   optional(tc.ExtraIn),
+		}
+
+		if tc.SK != nil {
+			priv, err := becc.Secp256k1ECC().NewPrivateKeyBytes(hexBytes(t, *tc.SK))
+			if err != nil {
+				t.Fatalf("case %d: unexpected error: %v", i, err)
+			}
+			opts.PrivateKey = &priv
+		}
+
+		secret, public, err := nonceGen(hexBytes(t, tc.Rand), mustParsePublicKey(t, tc.PK), opts)
+		if err != nil {
+			t.Fatalf("case %d: unexpected error: %v", i, err)
+		}
+
+		got := append(append(secret.k1.FillBytes(make([]byte, 32)), secret.k2.FillBytes(make([]byte, 32))...), secret.pk...)
+		if want := hexBytes(t, tc.Expected); !bytes.Equal(got, want) {
+			t.Errorf("case %d: got secret nonce %x, want %x", i, got, want)
+		}
+
+		g := becc.Secp256k1ECC().Generator()
+		if !public.r1.Eq(g.ScalarMul(secret.k1)) || !public.r2.Eq(g.ScalarMul(secret.k2)) {
+			t.Errorf("case %d: the public nonce does not match the secret nonce", i)
+		}
+	}
+}
+
+func TestNonceAgg(t *testing.T) {
+	var v struct {
+		PNonces        []string `json:"pnonces"`
+		ValidTestCases []struct {
+			PNonceIndices []int  `json:"pnonce_indices"`
+			Expected      string `json:"expected"`
+		} `json:"valid_test_cases"`
+		ErrorTestCases []struct {
+			PNonceIndices []int       `json:"pnonce_indices"`
+			Error         vectorError `json:"error"`
+			Comment       string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "nonce_agg_vectors.json", &v)
+
+	for i, tc := range v.ValidTestCases {
+		nonces, err := parsePublicNonces(v.PNonces, tc.PNonceIndices)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		aggNonce := NonceAgg(nonces)
+		if got := strings.ToUpper(hex.EncodeToString(aggNonce.Bytes())); got != tc.Expected {
+			t.Errorf("valid case %d: got %s, want %s", i, got, tc.Expected)
+		}
+
+		parsed, err := ParseAggregateNonce(aggNonce.Bytes())
+		if err != nil || !bytes.Equal(parsed.Bytes(), aggNonce.Bytes()) {
+			t.Errorf("valid case %d: aggregate nonce round trip failed: %v", i, err)
+		}
+	}
+
+	for _, tc := range v.ErrorTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			nonce := v.PNonces[tc.PNonceIndices[*tc.Error.Signer]]
+			if _, err := ParsePublicNonce(hexBytes(t, nonce)); !errors.Is(err, ErrInvalidNonce) {
+				t.Errorf("expected error %v, got %v", ErrInvalidNonce, err)
+			}
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	var v struct {
+		SK             string   `json:"sk"`
+		Pubkeys        []string `json:"pubkeys"`
+		SecNonces      []string `json:"secnonces"`
+		PNonces        []string `json:"pnonces"`
+		AggNonces      []string `json:"aggnonces"`
+		Msgs           []string `json:"msgs"`
+		ValidTestCases []struct {
+			KeyIndices    []int  `json:"key_indices"`
+			NonceIndices  []int  `json:"nonce_indices"`
+			AggNonceIndex int    `json:"aggnonce_index"`
+			MsgIndex      int    `json:"msg_index"`
+			SignerIndex   int    `json:"signer_index"`
+			Expected      string `json:"expected"`
+		} `json:"valid_test_cases"`
+		SignErrorTestCases []struct {
+			KeyIndices    []int       `json:"key_indices"`
+			AggNonceIndex int         `json:"aggnonce_index"`
+			MsgIndex      int         `json:"msg_index"`
+			SecNonceIndex int         `json:"secnonce_index"`
+			Error         vectorError `json:"error"`
+			Comment       string      `json:"comment"`
+		} `json:"sign_error_test_cases"`
+		VerifyFailTestCases []struct {
+			Sig          string `json:"sig"`
+			KeyIndices   []int  `json:"key_indices"`
+			NonceIndices []int  `json:"nonce_indices"`
+			MsgIndex     int    `json:"msg_index"`
+			SignerIndex  int    `json:"signer_index"`
+			Comment      string `json:"comment"`
+		} `json:"verify_fail_test_cases"`
+		VerifyErrorTestCases []struct {
+			Sig          string      `json:"sig"`
+			KeyIndices   []int       `json:"key_indices"`
+			NonceIndices []int       `json:"nonce_indices"`
+			MsgIndex     int         `json:"msg_index"`
+			SignerIndex  int         `json:"signer_index"`
+			Error        vectorError `json:"error"`
+			Comment      string      `json:"comment"`
+		} `json:"verify_error_test_cases"`
+	}
+	loadVectors(t, "sign_verify_vectors.json", &v)
+
+	priv, err := becc.Secp256k1ECC().NewPrivateKeyBytes(hexBytes(t, v.SK))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, tc := range v.ValidTestCases {
+		pubs, err := parsePublicKeys(v.Pubkeys, tc.KeyIndices)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		nonces, err := parsePublicNonces(v.PNonces, tc.NonceIndices)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		aggNonce, err := ParseAggregateNonce(hexBytes(t, v.AggNonces[tc.AggNonceIndex]))
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		if !bytes.Equal(NonceAgg(nonces).Bytes(), aggNonce.Bytes()) {
+			t.Errorf("valid case %d: the aggregate nonce does not match the public nonces", i)
+		}
+
+		ctx, err := KeyAgg(pubs)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		session := ctx.NewSession(aggNonce, hexBytes(t, v.Msgs[tc.MsgIndex]))
+
+		secNonce := parseSecretNonce(t, v.SecNonces[0])
+		psig, err := session.Sign(secNonce, priv)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		if got := strings.ToUpper(hex.EncodeToString(psig.Bytes())); got != tc.Expected {
+			t.Errorf("valid case %d: got %s, want %s", i, got, tc.Expected)
+		}
+
+		if !session.VerifyPartial(psig, nonces[tc.SignerIndex], pubs[tc.SignerIndex]) {
+			t.Errorf("valid case %d: the partial signature is not valid", i)
+		}
+
+		if _, err := session.Sign(secNonce, priv); !errors.Is(err, ErrInvalidNonce) {
+			t.Errorf("valid case %d: reusing the secret nonce: expected error %v, got %v", i, ErrInvalidNonce, err)
+		}
+	}
+
+	for _, tc := range v.SignErrorTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			pubs, err := parsePublicKeys(v.Pubkeys, tc.KeyIndices)
+			if tc.Error.Contrib == "pubkey" {
+				if err == nil {
+					t.Errorf("expected an invalid public key error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			aggNonce, err := ParseAggregateNonce(hexBytes(t, v.AggNonces[tc.AggNonceIndex]))
+			if tc.Error.Contrib == "aggnonce" {
+				if !errors.Is(err, ErrInvalidNonce) {
+					t.Errorf("expected error %v, got %v", ErrInvalidNonce, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			ctx, err := KeyAgg(pubs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			session := ctx.NewSession(aggNonce, hexBytes(t, v.Msgs[tc.MsgIndex]))
+
+			want := ErrUnknownSigner
+			if strings.Contains(tc.Error.Message, "secnonce") {
+				want = ErrInvalidNonce
+			}
+
+			if _, err := session.Sign(parseSecretNonce(t, v.SecNonces[tc.SecNonceIndex]), priv); !errors.Is(err, want) {
+				t.Errorf("expected error %v, got %v", want, err)
+			}
+		})
+	}
+
+	for _, tc := range v.VerifyFailTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			pubs, err := parsePublicKeys(v.Pubkeys, tc.KeyIndices)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			nonces, err := parsePublicNonces(v.PNonces, tc.NonceIndices)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			psig, err := ParsePartialSignature(hexBytes(t, tc.Sig))
+			if err != nil {
+				if !errors.Is(err, ErrInvalidPartialSignature) {
+					t.Errorf("expected error %v, got %v", ErrInvalidPartialSignature, err)
+				}
+				return
+			}
+
+			ctx, err := KeyAgg(pubs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			session := ctx.NewSession(NonceAgg(nonces), hexBytes(t, v.Msgs[tc.MsgIndex]))
+			if session.VerifyPartial(psig, nonces[tc.SignerIndex], pubs[tc.SignerIndex]) {
+				t.Errorf("the partial signature should not be valid")
+			}
+		})
+	}
+
+	for _, tc := range v.VerifyErrorTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			switch tc.Error.Contrib {
+			case "pubkey":
+				if _, err := parsePublicKeys(v.Pubkeys, tc.KeyIndices); err == nil {
+					t.Errorf("expected an invalid public key error")
+				}
+			case "pubnonce":
+				if _, err := parsePublicNonces(v.PNonces, tc.NonceIndices); !errors.Is(err, ErrInvalidNonce) {
+					t.Errorf("expected error %v, got %v", ErrInvalidNonce, err)
+				}
+			default:
+				t.Fatalf("unexpected contribution %s", tc.Error.Contrib)
+			}
+		})
+	}
+}
+
+func TestTweak(t *testing.T) {
+	var v struct {
+		SK             string   `json:"sk"`
+		Pubkeys        []string `json:"pubkeys"`
+		SecNonce       string   `json:"secnonce"`
+		PNonces        []string `json:"pnonces"`
+		AggNonce       string   `json:"aggnonce"`
+		Tweaks         []string `json:"tweaks"`
+		Msg            string   `json:"msg"`
+		ValidTestCases []struct {
+			KeyIndices   []int  `json:"key_indices"`
+			NonceIndices []int  `json:"nonce_indices"`
+			TweakIndices []int  `json:"tweak_indices"`
+			IsXOnly      []bool `json:"is_xonly"`
+			SignerIndex  int    `json:"signer_index"`
+			Expected     string `json:"expected"`
+			Comment      string `json:"comment"`
+		} `json:"valid_test_cases"`
+		ErrorTestCases []struct {
+			KeyIndices   []int       `json:"key_indices"`
+			TweakIndices []int       `json:"tweak_indices"`
+			IsXOnly      []bool      `json:"is_xonly"`
+			Error        vectorError `json:"error"`
+			Comment      string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "tweak_vectors.json", &v)
+
+	priv, err := becc.Secp256k1ECC().NewPrivateKeyBytes(hexBytes(t, v.SK))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	aggNonce, err := ParseAggregateNonce(hexBytes(t, v.AggNonce))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tweakedContext := func(t *testing.T, keyIndices, tweakIndices []int, isXOnly []bool) (*KeyAggContext, []becc.PublicKey, error) {
+		pubs, err := parsePublicKeys(v.Pubkeys, keyIndices)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ctx, err := KeyAgg(pubs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for i, k := range tweakIndices {
+			if ctx, err = ctx.ApplyTweak(hexBytes(t, v.Tweaks[k]), isXOnly[i]); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return ctx, pubs, nil
+	}
+
+	for _, tc := range v.ValidTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			ctx, pubs, err := tweakedContext(t, tc.KeyIndices, tc.TweakIndices, tc.IsXOnly)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			nonces, err := parsePublicNonces(v.PNonces, tc.NonceIndices)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			session := ctx.NewSession(aggNonce, hexBytes(t, v.Msg))
+
+			psig, err := session.Sign(parseSecretNonce(t, v.SecNonce), priv)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := strings.ToUpper(hex.EncodeToString(psig.Bytes())); got != tc.Expected {
+				t.Errorf("got %s, want %s", got, tc.Expected)
+			}
+
+			if !session.VerifyPartial(psig, nonces[tc.SignerIndex], pubs[tc.SignerIndex]) {
+				t.Errorf("the partial signature is not valid")
+			}
+		})
+	}
+
+	for _, tc := range v.ErrorTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			if _, _, err := tweakedContext(t, tc.KeyIndices, tc.TweakIndices, tc.IsXOnly); !errors.Is(err, ErrInvalidTweak) {
+				t.Errorf("expected error %v, got %v", ErrInvalidTweak, err)
+			}
+		})
+	}
+}
+
+func TestSigAgg(t *testing.T) {
+	var v struct {
+		Pubkeys        []string `json:"pubkeys"`
+		PNonces        []string `json:"pnonces"`
+		Tweaks         []string `json:"tweaks"`
+		PSigs          []string `json:"psigs"`
+		Msg            string   `json:"msg"`
+		ValidTestCases []struct {
+			AggNonce     string `json:"aggnonce"`
+			NonceIndices []int  `json:"nonce_indices"`
+			KeyIndices   []int  `json:"key_indices"`
+			TweakIndices []int  `json:"tweak_indices"`
+			IsXOnly      []bool `json:"is_xonly"`
+			PSigIndices  []int  `json:"psig_indices"`
+			Expected     string `json:"expected"`
+		} `json:"valid_test_cases"`
+		ErrorTestCases []struct {
+			PSigIndices []int       `json:"psig_indices"`
+			Error       vectorError `json:"error"`
+			Comment     string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "sig_agg_vectors.json", &v)
+
+	msg := hexBytes(t, v.Msg)
+
+	for i, tc := range v.ValidTestCases {
+		pubs, err := parsePublicKeys(v.Pubkeys, tc.KeyIndices)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		nonces, err := parsePublicNonces(v.PNonces, tc.NonceIndices)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		aggNonce := NonceAgg(nonces)
+		if got := strings.ToUpper(hex.EncodeToString(aggNonce.Bytes())); got != tc.AggNonce {
+			t.Errorf("valid case %d: got aggregate nonce %s, want %s", i, got, tc.AggNonce)
+		}
+
+		ctx, err := KeyAgg(pubs)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		for j, k := range tc.TweakIndices {
+			if ctx, err = ctx.ApplyTweak(hexBytes(t, v.Tweaks[k]), tc.IsXOnly[j]); err != nil {
+				t.Fatalf("valid case %d: unexpected error: %v", i, err)
+			}
+		}
+
+		psigs := make([]PartialSignature, len(tc.PSigIndices))
+		for j, k := range tc.PSigIndices {
+			if psigs[j], err = ParsePartialSignature(hexBytes(t, v.PSigs[k])); err != nil {
+				t.Fatalf("valid case %d: unexpected error: %v", i, err)
+			}
+		}
+
+		sig, err := ctx.NewSession(aggNonce, msg).Aggregate(psigs)
+		if err != nil {
+			t.Fatalf("valid case %d: unexpected error: %v", i, err)
+		}
+
+		if got := strings.ToUpper(hex.EncodeToString(sig.Bytes())); got != tc.Expected {
+			t.Errorf("valid case %d: got %s, want %s", i, got, tc.Expected)
+		}
+
+		if !ctx.PublicKey().VerifySchnorr(msg, sig) {
+			t.Errorf("valid case %d: the signature is not valid for the aggregate key", i)
+		}
+	}
+
+	for _, tc := range v.ErrorTestCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			psig := v.PSigs[tc.PSigIndices[*tc.Error.Signer]]
+			if _, err := ParsePartialSignature(hexBytes(t, psig)); !errors.Is(err, ErrInvalidPartialSignature) {
+				t.Errorf("expected error %v, got %v", ErrInvalidPartialSignature, err)
+			}
+		})
+	}
+}
+
+func TestMuSig2(t *testing.T) {
+	ecc := becc.Secp256k1ECC()
+	msg := []byte("musig2 test message")
+
+	privs := make([]becc.PrivateKey, 3)
+	pubs := make([]becc.PublicKey, 3)
+	for i := range privs {
+		var err error
+		if privs[i], pubs[i], err = ecc.GenKeyPair(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ctx, err := KeyAgg(KeySort(pubs))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ctx, err = ctx.ApplyTweak(bytes.Repeat([]byte{0x42}, 32), true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secNonces := make([]*SecretNonce, len(privs))
+	pubNonces := make([]PublicNonce, len(privs))
+	for i := range privs {
+		opts := NonceGenOptions{PrivateKey: &privs[i], AggregateKey: ctx.XOnlyPublicKey(), Message: msg}
+		if secNonces[i], pubNonces[i], err = NonceGen(pubs[i], opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		parsed, err := ParsePublicNonce(pubNonces[i].Bytes())
+		if err != nil || !bytes.Equal(parsed.Bytes(), pubNonces[i].Bytes()) {
+			t.Fatalf("public nonce round trip failed: %v", err)
+		}
+	}
+
+	session := ctx.NewSession(NonceAgg(pubNonces), msg)
+
+	psigs := make([]PartialSignature, len(privs))
+	for i := range privs {
+		if psigs[i], err = session.Sign(secNonces[i], privs[i]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if session.VerifyPartial(psigs[i], pubNonces[i], pubs[(i+1)%len(pubs)]) {
+			t.Errorf("partial signature %d is valid for another signer", i)
+		}
+	}
+
+	sig, err := session.Aggregate(psigs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	xOnly, err := ecc.NewPublicKeyXOnly(ctx.XOnlyPublicKey())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !xOnly.VerifySchnorr(msg, sig) {
+		t.Errorf("the aggregate signature is not valid")
+	}
+
+	if xOnly.VerifySchnorr([]byte("another message"), sig) {
+		t.Errorf("the aggregate signature is valid for another message")
+	}
+
+	_, p256Pub, err := becc.Secp256r1ECC().GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := KeyAgg([]becc.PublicKey{p256Pub}); !errors.Is(err, becc.ErrUnsupportedCurve) {
+		t.Errorf("expected error %v, got %v", becc.ErrUnsupportedCurve, err)
+	}
+}
+
+func loadVectors(t *testing.T, name string, v any) {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("could not read the test vectors: %v", err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("could not parse the test vectors: %v", err)
+	}
+}
+
+func parsePublicKeys(pubkeys []string, indices []int) ([]becc.PublicKey, error) {
+	pubs := make([]becc.PublicKey, len(indices))
+	for i, k := range indices {
+		b, err := hex.DecodeString(pubkeys[k])
+		if err != nil {
+			return nil, err
+		}
+
+		if pubs[i], err = becc.Secp256k1ECC().NewPublicKeyCompressed(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return pubs, nil
+}
+
+func parsePublicNonces(pnonces []string, indices []int) ([]PublicNonce, error) {
+	nonces := make([]PublicNonce, len(indices))
+	for i, k := range indices {
+		b, err := hex.DecodeString(pnonces[k])
+		if err != nil {
+			return nil, err
+		}
+
+		if nonces[i], err = ParsePublicNonce(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return nonces, nil
+}
+
+func parseSecretNonce(t *testing.T, s string) *SecretNonce {
+	t.Helper()
+
+	b := hexBytes(t, s)
+
+	return &SecretNonce{
+		k1: new(big.Int).SetBytes(b[:32]),
+		k2: new(big.Int).SetBytes(b[32:64]),
+		pk: b[64:],
+	}
+}
+
+func mustParsePublicKey(t *testing.T, s string) becc.PublicKey {
+	t.Helper()
+
+	pub, err := becc.Secp256k1ECC().NewPublicKeyCompressed(hexBytes(t, s))
+	if err != nil {
+		t.Fatalf("invalid public key %s: %v", s, err)
+	}
+
+	return pub
+}
+
+func hexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex string %s: %v", s, err)
+	}
+
+	return b
+}
@@ -0,0 +1,257 @@
+package becc
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// ecParametersJSON is the JSON encoding of explicit domain parameters. Every
+// number is a hexadecimal string.
+type ecParametersJSON struct {
+	Name string `json:"name,omitempty"`
+	P    string `json:"p"`
+	A    string `json:"a"`
+	B    string `json:"b"`
+	Gx   string `json:"gx"`
+	Gy   string `json:"gy"`
+	N    string `json:"n"`
+	H    string `json:"h,omitempty"`
+	Seed string `json:"seed,omitempty"`
+}
+
+// ecParameters is the SEC 1 (section C.2) ECParameters structure for curves
+// over prime fields.
+type ecParameters struct {
+	Version  int
+	FieldID  fieldID
+	Curve    curveCoefficients
+	Base     []byte
+	Order    *big.Int
+	Cofactor *big.Int `asn1:"optional"`
+}
+
+type fieldID struct {
+	FieldType asn1.ObjectIdentifier
+	Prime     *big.Int
+}
+
+type curveCoefficients struct {
+	A    []byte
+	B    []byte
+	Seed asn1.BitString `asn1:"optional"`
+}
+
+var oidPrimeField = asn1.ObjectIdentifier{1, 2, 840, 10045, 1, 1}
+
+const pemTypeECParameters = "EC PARAMETERS"
+
+// LoadECParameters builds an ECC instance from domain parameters encoded
+// either as JSON or as a PEM "EC PARAMETERS" block.
+func LoadECParameters(data []byte) (*ECC, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN")) {
+		return ParseECParametersPEM(data)
+	}
+
+	return ParseECParametersJSON(data)
+}
+
+// ParseECParametersJSON builds an ECC instance from explicit domain
+// parameters in JSON, with the fields p, a, b, gx, gy, n and the optional
+// h (default 1), name and seed, all numbers in hexadecimal.
+func ParseECParametersJSON(data []byte) (*ECC, error) {
+	var params ecParametersJSON
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("invalid parameters file: %w", err)
+	}
+
+	if params.H == "" {
+		params.H = "1"
+	}
+
+	values := make([]*big.Int, 7)
+	for i, v := range []string{params.P, params.A, params.B, params.Gx, params.Gy, params.N, params.H} {
+		n, ok := new(big.Int).SetString(v, 16)
+		if !ok {
+			return nil, fmt.Errorf("invalid parameters file: invalid number %q", v)
+		}
+		values[i] = n
+	}
+	p, a, b, gx, gy, n, h := values[0], values[1], values[2], values[3], values[4], values[5], values[6]
+
+	seed, err := hex.DecodeString(params.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters file: invalid seed %q", params.Seed)
+	}
+
+	ecc, err := newECCFromParams(p, a, b, gx, gy, n, h)
+	if err != nil {
+		return nil, err
+	}
+	ecc.name = params.Name
+	if len(seed) > 0 {
+		ecc.seed = seed
+	}
+
+	return ecc, nil
+}
+
+// ParseECParametersPEM builds an ECC instance from a PEM "EC PARAMETERS"
+// block holding either a named curve OID or explicit SEC 1 ECParameters.
+func ParseECParametersPEM(data []byte) (*ECC, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypeECParameters {
+		return nil, errors.New("invalid parameters file: no EC PARAMETERS PEM block")
+	}
+
+	return ParseECParametersDER(block.Bytes)
+}
+
+// ParseECParametersDER builds an ECC instance from the DER encoding of the
+// SEC 1 ECParameters choice: a named curve OID or explicit parameters.
+func ParseECParametersDER(der []byte) (*ECC, error) {
+	var oid asn1.ObjectIdentifier
+	if rest, err := asn1.Unmarshal(der, &oid); err == nil {
+		if len(rest) != 0 {
+			return nil, errors.New("invalid parameters file: trailing data")
+		}
+		return CurveByOID(oid)
+	}
+
+	var params ecParameters
+	rest, err := asn1.Unmarshal(der, &params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters file: %w", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("invalid parameters file: trailing data")
+	}
+
+	if params.Version != 1 {
+		return nil, fmt.Errorf("invalid parameters file: unsupported version %d", params.Version)
+	}
+
+	if !params.FieldID.FieldType.Equal(oidPrimeField) {
+		return nil, errors.New("invalid parameters file: only prime fields are supported")
+	}
+
+	p := params.FieldID.Prime
+	a := new(big.Int).SetBytes(params.Curve.A)
+	b := new(big.Int).SetBytes(params.Curve.B)
+
+	h := params.Cofactor
+	if h == nil {
+		h = big.NewInt(1)
+	}
+
+	gx, gy, err := decodeBasePoint(p, a, b, params.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	ecc, err := newECCFromParams(p, a, b, gx, gy, params.Order, h)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(params.Curve.Seed.Bytes) > 0 {
+		ecc.seed = params.Curve.Seed.Bytes
+	}
+
+	return ecc, nil
+}
+
+// ECParametersJSON returns the explicit domain parameters encoded as JSON,
+// in the format read by ParseECParametersJSON.
+func (e *ECC) ECParametersJSON() ([]byte, error) {
+	hexLen := 2 * e.CoordinateSize()
+
+	return json.MarshalIndent(ecParametersJSON{
+		Name: e.name,
+		P:    fmt.Sprintf("%0*x", hexLen, e.ec.m),
+		A:    fmt.Sprintf("%0*x", hexLen, e.ec.a.n),
+		B:    fmt.Sprintf("%0*x", hexLen, e.ec.b.n),
+		Gx:   fmt.Sprintf("%0*x", hexLen, e.g.x.n),
+		Gy:   fmt.Sprintf("%0*x", hexLen, e.g.y.n),
+		N:    fmt.Sprintf("%0*x", 2*e.ScalarSize(), e.n),
+		H:    fmt.Sprintf("%x", e.h),
+		Seed: hex.EncodeToString(e.seed),
+	}, "", "  ")
+}
+
+// ECParametersPEM returns the explicit domain parameters as a PEM
+// "EC PARAMETERS" block with SEC 1 ECParameters.
+func (e *ECC) ECParametersPEM() ([]byte, error) {
+	coordLen := e.CoordinateSize()
+
+	der, err := asn1.Marshal(ecParameters{
+		Version: 1,
+		FieldID: fieldID{
+			FieldType: oidPrimeField,
+			Prime:     e.ec.m,
+		},
+		Curve: curveCoefficients{
+			A:    e.ec.a.n.FillBytes(make([]byte, coordLen)),
+			B:    e.ec.b.n.FillBytes(make([]byte, coordLen)),
+			Seed: asn1.BitString{Bytes: e.seed, BitLength: 8 * len(e.seed)},
+		},
+		Base: slices.Concat(
+			[]byte{4},
+			e.g.x.n.FillBytes(make([]byte, coordLen)),
+			e.g.y.n.FillBytes(make([]byte, coordLen)),
+		),
+		Order:    e.n,
+		Cofactor: e.h,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeECParameters, Bytes: der}), nil
+}
+
+func newECCFromParams(p, a, b, gx, gy, n, h *big.Int) (*ECC, error) {
+	ec, err := NewEllipticCurve(a, b, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewECC(ec, ec.NewPoint(gx, gy), n, h)
+}
+
+// decodeBasePoint decodes the SEC 1 encoding of the base point.
+func decodeBasePoint(p, a, b *big.Int, base []byte) (*big.Int, *big.Int, error) {
+	coordLen := (p.BitLen() + 7) / 8
+
+	if len(base) == 2*coordLen+1 && base[0] == 4 {
+		gx := new(big.Int).SetBytes(base[1 : coordLen+1])
+		gy := new(big.Int).SetBytes(base[coordLen+1:])
+		return gx, gy, nil
+	}
+
+	if len(base) == coordLen+1 && (base[0] == 2 || base[0] == 3) {
+		ec, err := NewEllipticCurve(a, b, p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		gx := new(big.Int).SetBytes(base[1:])
+		ys := ec.Y(gx)
+		for _, y := range ys {
+			if y.Bit(0) == uint(base[0]&1) {
+				return gx, y, nil
+			}
+		}
+
+		return nil, nil, fmt.Errorf("%w: generator not in curve", ErrInvalidDomainParameters)
+	}
+
+	return nil, nil, errors.New("invalid parameters file: invalid base point encoding")
+}
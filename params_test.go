@@ -0,0 +1,137 @@
+package becc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseECParametersPEM(t *testing.T) {
+	// openssl ecparam -name brainpoolP256r1 -param_enc explicit
+	brainpoolExplicit := `-----BEGIN EC PARAMETERS-----
+MIHgAgEBMCwGByqGSM49AQECIQCp+1fboe6pvD5mCpCdg41ybjv2I9UmICggE0gd
+H25TdzBEBCB9Wgl1/CwwV+72dTBBev/n+4BVwSbcXGzpSktE8zC12QQgJtxcbOlK
+S0TzMLXZu9d8v5WEFilc9+HOa8zcGP+MB7YEQQSL0q65y35XyyxLSC/8gbevud4n
+4eO9I8I6RFO9ms4yYlR++DXD2sT9l/hGGhRhHcnCd0UTLe2OVFwdVMcvBGmXAiEA
+qftX26Huqbw+ZgqQnYONcYw5eqO1Yab3kB4OgpdIVqcCAQE=
+-----END EC PARAMETERS-----
+`
+
+	ecc, err := LoadECParameters([]byte(brainpoolExplicit))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSameDomain(t, ecc, BrainpoolP256r1ECC())
+
+	// openssl ecparam -name prime256v1
+	named := `-----BEGIN EC PARAMETERS-----
+BggqhkjOPQMBBw==
+-----END EC PARAMETERS-----
+`
+
+	ecc, err = LoadECParameters([]byte(named))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ecc.Name() != "secp256r1" {
+		t.Errorf("got %s, expected secp256r1", ecc.Name())
+	}
+
+	// openssl ecparam -name secp128r1 -param_enc explicit, which includes a
+	// seed and is not in the registry
+	secp128r1 := `-----BEGIN EC PARAMETERS-----
+MIGXAgEBMBwGByqGSM49AQECEQD////9////////////////MDsEEP////3/////
+//////////wEEOh1ecEQefQ92CSZPCzuXtMDFQAADg1NaW5naHVhUXUMwDpEc9A2
+eQQhBBYf91KLiZstDChgfKUsW4bPWsg5W6/rE8AtopLd7XqDAhEA/////gAAAAB1
+ow0bkDihFQIBAQ==
+-----END EC PARAMETERS-----
+`
+
+	ecc, err = LoadECParameters([]byte(secp128r1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ecc.Security() != 64 {
+		t.Errorf("security: got %d, expected 64", ecc.Security())
+	}
+
+	if ecc.Order().Cmp(bigIntHex(t, "fffffffe0000000075a30d1b9038a115")) != 0 {
+		t.Errorf("order: got %x", ecc.Order())
+	}
+}
+
+func TestECParametersRoundTrip(t *testing.T) {
+	for _, ci := range Curves() {
+		t.Run(ci.Name, func(t *testing.T) {
+			ecc := ci.ECC()
+
+			jsonParams, err := ecc.ECParametersJSON()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fromJSON, err := LoadECParameters(jsonParams)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSameDomain(t, fromJSON, ecc)
+
+			if fromJSON.Name() != ci.Name {
+				t.Errorf("name: got %s, expected %s", fromJSON.Name(), ci.Name)
+			}
+
+			pemParams, err := ecc.ECParametersPEM()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fromPEM, err := LoadECParameters(pemParams)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertSameDomain(t, fromPEM, ecc)
+		})
+	}
+}
+
+func TestParseECParametersJSONInvalid(t *testing.T) {
+	// secp256k1 with a generator that is not in the curve
+	params := `{
+  "p": "fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+  "a": "0",
+  "b": "7",
+  "gx": "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+  "gy": "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b9",
+  "n": "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"
+}`
+
+	_, err := LoadECParameters([]byte(params))
+	if !errors.Is(err, ErrInvalidDomainParameters) {
+		t.Errorf("got %v, expected ErrInvalidDomainParameters", err)
+	}
+
+	if _, err := LoadECParameters([]byte(`{"p": "zz"}`)); err == nil {
+		t.Errorf("expected error for invalid number")
+	}
+}
+
+func assertSameDomain(t *testing.T, got, expected *ECC) {
+	t.Helper()
+
+	if got.ec.m.Cmp(expected.ec.m) != 0 || !got.ec.a.Eq(expected.ec.a) || !got.ec.b.Eq(expected.ec.b) {
+		t.Errorf("curve: got %s, expected %s", got.ec.m, expected.ec.m)
+	}
+
+	if !got.g.Eq(expected.g) {
+		t.Errorf("generator: got %s, expected %s", got.g, expected.g)
+	}
+
+	if got.n.Cmp(expected.n) != 0 {
+		t.Errorf("order: got %x, expected %x", got.n, expected.n)
+	}
+
+	if got.h.Cmp(expected.h) != 0 {
+		t.Errorf("cofactor: got %d, expected %d", got.h, expected.h)
+	}
+}
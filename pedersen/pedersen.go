@@ -0,0 +1,115 @@
+// Package pedersen implements Pedersen commitments over the curves of becc
+// that have a hash-to-curve suite.
+//
+// A commitment to the value v with the blinding factor r is C = v·G + r·H,
+// where G is the generator of the curve and H a second generator derived with
+// hash-to-curve, so that nobody knows log_G(H). The commitment is perfectly
+// hiding, as any value can be opened with some blinding factor, and
+// computationally binding, as opening it to two different values reveals
+// log_G(H). Commitments are additively homomorphic: C1 + C2 is a commitment to
+// v1 + v2 with the blinding factor r1 + r2, modulo the order n.
+package pedersen
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/artilugio0/becc"
+)
+
+// generatorSeed is the input hashed to H.
+const generatorSeed = "becc pedersen generator H"
+
+// Params holds the curve and its two generators.
+type Params struct {
+	ecc *becc.ECC
+	h   becc.Point
+}
+
+// New returns the parameters for ecc, with H = hash_to_curve("becc pedersen
+// generator H") and the domain separation tag
+// "BECC-PEDERSEN-V01-CS01-with-" followed by the RFC 9380 suite ID of the
+// curve. It fails with becc.ErrUnsupportedCurve for curves without a suite.
+func New(ecc *becc.ECC) (*Params, error) {
+	suiteID, err := ecc.HashToCurveSuiteID(true)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := ecc.HashToCurve([]byte(generatorSeed), []byte("BECC-PEDERSEN-V01-CS01-with-"+suiteID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Params{ecc: ecc, h: h}, nil
+}
+
+// G returns the generator the values are multiplied by.
+func (p *Params) G() becc.Point {
+	return p.ecc.Generator()
+}
+
+// H returns the generator the blinding factors are multiplied by.
+func (p *Params) H() becc.Point {
+	return p.h
+}
+
+// Order returns the order n of the generators, the modulus of values and
+// blinding factors.
+func (p *Params) Order() *big.Int {
+	return p.ecc.Order()
+}
+
+// Commitment is a commitment v·G + r·H.
+type Commitment struct {
+	p becc.Point
+}
+
+// Commit returns the commitment to value with the blinding factor blinding,
+// both taken modulo n. The blinding factor must be uniformly random, for
+// example from RandomBlinding, and kept secret until the commitment is
+// opened.
+func (p *Params) Commit(value, blinding *big.Int) Commitment {
+	n := p.ecc.Order()
+	v := new(big.Int).Mod(value, n)
+	r := new(big.Int).Mod(blinding, n)
+
+	return Commitment{p: p.ecc.Generator().ScalarMul(v).Add(p.h.ScalarMul(r))}
+}
+
+// Open reports whether c is the commitment to value with the blinding factor
+// blinding.
+func (p *Params) Open(c Commitment, value, blinding *big.Int) bool {
+	return c.Eq(p.Commit(value, blinding))
+}
+
+// RandomBlinding returns a uniformly random blinding factor in [0, n-1].
+func (p *Params) RandomBlinding() (*big.Int, error) {
+	return rand.Int(rand.Reader, p.ecc.Order())
+}
+
+// Add returns c + d, the commitment to the sum of the values with the sum of
+// the blinding factors.
+func (c Commitment) Add(d Commitment) Commitment {
+	return Commitment{p: c.p.Add(d.p)}
+}
+
+// Sub returns c - d, the commitment to the difference of the values with the
+// difference of the blinding factors.
+func (c Commitment) Sub(d Commitment) Commitment {
+	return Commitment{p: c.p.Add(d.p.Neg())}
+}
+
+// Eq reports whether c and d are the same commitment.
+func (c Commitment) Eq(d Commitment) bool {
+	if c.p.IsInfinity() || d.p.IsInfinity() {
+		return c.p.IsInfinity() == d.p.IsInfinity()
+	}
+
+	return c.p.Eq(d.p)
+}
+
+// Point returns the curve point of the commitment.
+func (c Commitment) Point() becc.Point {
+	return c.p
+}
@@ -0,0 +1,160 @@
+package pedersen
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/artilugio0/becc"
+)
+
+var supportedCurves = []string{"secp256k1", "secp256r1", "secp384r1", "secp521r1"}
+
+func TestNew(t *testing.T) {
+	for _, name := range supportedCurves {
+		t.Run(name, func(t *testing.T) {
+			ecc, err := becc.LookupCurve(name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			params, err := New(ecc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			h := params.H()
+			if h.IsInfinity() || h.Eq(params.G()) || !ecc.Curve().IsOnCurve(h) {
+				t.Errorf("invalid generator H = %v", h)
+			}
+
+			if !h.ScalarMul(params.Order()).IsInfinity() {
+				t.Errorf("H is not in the subgroup of G")
+			}
+
+			// H is deterministic
+			again, err := New(ecc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !again.H().Eq(h) {
+				t.Errorf("got a different H: %v and %v", again.H(), h)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, err := New(becc.BrainpoolP256r1ECC()); !errors.Is(err, becc.ErrUnsupportedCurve) {
+			t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+		}
+	})
+}
+
+func TestCommitment(t *testing.T) {
+	for _, name := range supportedCurves {
+		t.Run(name, func(t *testing.T) {
+			ecc, err := becc.LookupCurve(name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			params, err := New(ecc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			n := params.Order()
+			v1, v2 := big.NewInt(1000), big.NewInt(234)
+			r1, r2 := randomBlinding(t, params), randomBlinding(t, params)
+			c1, c2 := params.Commit(v1, r1), params.Commit(v2, r2)
+
+			if !params.Open(c1, v1, r1) || !params.Open(c2, v2, r2) {
+				t.Fatalf("the commitments do not open")
+			}
+
+			sum := c1.Add(c2)
+			sumValue := new(big.Int).Add(v1, v2)
+			sumBlinding := new(big.Int).Add(r1, r2)
+			sumBlinding.Mod(sumBlinding, n)
+
+			// homomorphism
+			if !params.Open(sum, sumValue, sumBlinding) {
+				t.Errorf("c1 + c2 does not open to v1 + v2 with r1 + r2")
+			}
+
+			diff := c2.Sub(c1)
+			diffValue := new(big.Int).Sub(v2, v1)
+			diffBlinding := new(big.Int).Sub(r2, r1)
+
+			// negative values and blinding factors wrap around modulo n
+			if !params.Open(diff, diffValue, diffBlinding) {
+				t.Errorf("c2 - c1 does not open to v2 - v1 with r2 - r1")
+			}
+
+			if !diff.Add(c1).Eq(c2) || !c1.Sub(c1).Eq(params.Commit(big.NewInt(0), big.NewInt(0))) {
+				t.Errorf("subtraction is not the inverse of addition")
+			}
+
+			// binding: the sum does not open to any other value, nor without
+			// both blinding factors
+			if params.Open(sum, new(big.Int).Add(sumValue, big.NewInt(1)), sumBlinding) {
+				t.Errorf("c1 + c2 opens to v1 + v2 + 1")
+			}
+
+			if params.Open(sum, sumValue, r1) || params.Open(sum, sumValue, r2) {
+				t.Errorf("c1 + c2 opens with a single blinding factor")
+			}
+
+			// hiding: the same value with different blinding factors gives
+			// unrelated commitments
+			if params.Commit(v1, r2).Eq(c1) {
+				t.Errorf("the commitment does not depend on the blinding factor")
+			}
+		})
+	}
+}
+
+// With a trapdoor x = log_G(H), which New does not let anyone know, every
+// commitment opens to every value: the commitments reveal nothing about the
+// values, and binding rests only on log_G(H) being unknown.
+func TestCommitmentTrapdoor(t *testing.T) {
+	ecc := becc.Secp256k1ECC()
+	n := ecc.Order()
+	x := big.NewInt(0x5eed)
+	params := &Params{ecc: ecc, h: ecc.Generator().ScalarMul(x)}
+
+	v1, v2 := big.NewInt(10), big.NewInt(20)
+	r1, r2 := randomBlinding(t, params), randomBlinding(t, params)
+	sum := params.Commit(v1, r1).Add(params.Commit(v2, r2))
+
+	// v1 + v2 + r·x = v' + r'·x gives r' = r + (v1 + v2 - v')/x
+	fake := big.NewInt(1234567)
+	r := new(big.Int).Add(r1, r2)
+	delta := new(big.Int).Add(v1, v2)
+	delta.Sub(delta, fake).Mul(delta, new(big.Int).ModInverse(x, n))
+	fakeBlinding := new(big.Int).Add(r, delta)
+
+	if !params.Open(sum, fake, fakeBlinding) {
+		t.Errorf("the trapdoor does not open the sum to another value")
+	}
+
+	// two openings of the same commitment reveal x
+	dv := new(big.Int).Sub(fake, new(big.Int).Add(v1, v2))
+	dr := new(big.Int).Sub(r, fakeBlinding)
+	recovered := dv.Mul(dv, dr.ModInverse(dr.Mod(dr, n), n))
+	if recovered.Mod(recovered, n).Cmp(x) != 0 {
+		t.Errorf("got log_G(H) = %s, expected %s", recovered, x)
+	}
+}
+
+func randomBlinding(t *testing.T, params *Params) *big.Int {
+	t.Helper()
+
+	r, err := params.RandomBlinding()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return r
+}
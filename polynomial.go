@@ -0,0 +1,332 @@
+package becc
+
+import "math/big"
+
+// poly is a polynomial over F_p with the coefficient of x^i at index i. It is
+// kept normalized: the zero polynomial is empty and the last coefficient is
+// never zero.
+type poly []*big.Int
+
+func (a poly) degree() int {
+	return len(a) - 1
+}
+
+func (a poly) isZero() bool {
+	return len(a) == 0
+}
+
+func (a poly) isOne() bool {
+	return len(a) == 1 && a[0].Cmp(bi1) == 0
+}
+
+func (a poly) equal(b poly) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// polyField implements the arithmetic of F_p[x].
+type polyField struct {
+	p *big.Int
+}
+
+// kroneckerThreshold is the length from which mul packs the polynomials into
+// integers instead of multiplying them term by term.
+const kroneckerThreshold = 16
+
+// newPoly returns the normalized polynomial with the given coefficients
+// reduced modulo p.
+func (f polyField) newPoly(coefficients ...*big.Int) poly {
+	a := make(poly, len(coefficients))
+	for i, c := range coefficients {
+		a[i] = new(big.Int).Mod(c, f.p)
+	}
+
+	return a.normalize()
+}
+
+func (a poly) normalize() poly {
+	for len(a) > 0 && a[len(a)-1].Sign() == 0 {
+		a = a[:len(a)-1]
+	}
+
+	return a
+}
+
+func (f polyField) constant(c *big.Int) poly {
+	return f.newPoly(c)
+}
+
+func (f polyField) add(a, b poly) poly {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	r := make(poly, len(a))
+	for i := range a {
+		r[i] = new(big.Int).Set(a[i])
+		if i < len(b) {
+			r[i].Add(r[i], b[i])
+			if r[i].Cmp(f.p) >= 0 {
+				r[i].Sub(r[i], f.p)
+			}
+		}
+	}
+
+	return r.normalize()
+}
+
+func (f polyField) neg(a poly) poly {
+	r := make(poly, len(a))
+	for i := range a {
+		r[i] = new(big.Int).Sub(f.p, a[i])
+	}
+
+	return r
+}
+
+func (f polyField) sub(a, b poly) poly {
+	return f.add(a, f.neg(b))
+}
+
+func (f polyField) scale(a poly, c *big.Int) poly {
+	r := make(poly, len(a))
+	for i := range a {
+		r[i] = new(big.Int).Mul(a[i], c)
+		r[i].Mod(r[i], f.p)
+	}
+
+	return r.normalize()
+}
+
+func (f polyField) mul(a, b poly) poly {
+	if a.isZero() || b.isZero() {
+		return poly{}
+	}
+
+	if min(len(a), len(b)) < kroneckerThreshold {
+		r := make(poly, len(a)+len(b)-1)
+		for i := range r {
+			r[i] = new(big.Int)
+		}
+
+		t := new(big.Int)
+		for i := range a {
+			for j := range b {
+				r[i+j].Add(r[i+j], t.Mul(a[i], b[j]))
+			}
+		}
+
+		for i := range r {
+			r[i].Mod(r[i], f.p)
+		}
+
+		return r.normalize()
+	}
+
+	// Kronecker substitution: evaluate both polynomials at x = 2^k with k
+	// large enough for every coefficient of the product, multiply the
+	// integers and read the coefficients back.
+	bits := 2*f.p.BitLen() + big.NewInt(int64(min(len(a), len(b)))).BitLen()
+	slot := (bits + 7) / 8
+
+	product := new(big.Int).Mul(f.pack(a, slot), f.pack(b, slot))
+
+	n := len(a) + len(b) - 1
+	buf := product.FillBytes(make([]byte, n*slot))
+
+	r := make(poly, n)
+	for i := range r {
+		end := len(buf) - i*slot
+		r[i] = new(big.Int).SetBytes(buf[end-slot : end])
+		r[i].Mod(r[i], f.p)
+	}
+
+	return r.normalize()
+}
+
+// pack returns a(2^(8*slot)).
+func (f polyField) pack(a poly, slot int) *big.Int {
+	buf := make([]byte, len(a)*slot)
+	for i, c := range a {
+		end := len(buf) - i*slot
+		c.FillBytes(buf[end-slot : end])
+	}
+
+	return new(big.Int).SetBytes(buf)
+}
+
+// divMod returns the quotient and remainder of a divided by b != 0.
+func (f polyField) divMod(a, b poly) (poly, poly) {
+	if len(a) < len(b) {
+		return poly{}, a
+	}
+
+	lcInv := new(big.Int).ModInverse(b[len(b)-1], f.p)
+
+	r := make(poly, len(a))
+	for i := range a {
+		r[i] = new(big.Int).Set(a[i])
+	}
+
+	q := make(poly, len(a)-len(b)+1)
+	t := new(big.Int)
+	for i := len(q) - 1; i >= 0; i-- {
+		c := new(big.Int).Mul(r[i+len(b)-1], lcInv)
+		c.Mod(c, f.p)
+		q[i] = c
+
+		if c.Sign() == 0 {
+			continue
+		}
+
+		for j := range b {
+			r[i+j].Sub(r[i+j], t.Mul(c, b[j])).Mod(r[i+j], f.p)
+		}
+	}
+
+	return q.normalize(), r[:len(b)-1].normalize()
+}
+
+// monic returns a scaled to leading coefficient 1.
+func (f polyField) monic(a poly) poly {
+	if a.isZero() {
+		return a
+	}
+
+	return f.scale(a, new(big.Int).ModInverse(a[len(a)-1], f.p))
+}
+
+// gcd returns the monic greatest common divisor of a and b.
+func (f polyField) gcd(a, b poly) poly {
+	for !b.isZero() {
+		_, r := f.divMod(a, b)
+		a, b = b, r
+	}
+
+	return f.monic(a)
+}
+
+// inverseSeries returns 1/a mod x^n for a with a non-zero constant term,
+// using Newton iteration.
+func (f polyField) inverseSeries(a poly, n int) poly {
+	g := f.constant(new(big.Int).ModInverse(a[0], f.p))
+	two := f.constant(bi2)
+
+	for k := 1; k < n; {
+		k = min(2*k, n)
+		ag := f.mul(truncate(a, k), g)
+		g = truncate(f.mul(g, f.sub(two, truncate(ag, k))), k)
+	}
+
+	return g
+}
+
+func truncate(a poly, n int) poly {
+	if len(a) > n {
+		a = a[:n]
+	}
+
+	return a.normalize()
+}
+
+func reverse(a poly, n int) poly {
+	r := make(poly, n)
+	for i := range r {
+		if n-1-i < len(a) {
+			r[i] = a[n-1-i]
+		} else {
+			r[i] = new(big.Int)
+		}
+	}
+
+	return r.normalize()
+}
+
+// polyQuotientRing implements the arithmetic of F_p[x]/(h) for a monic h.
+type polyQuotientRing struct {
+	polyField
+	h poly
+
+	// hRevInv is 1/rev(h) mod x^(deg h - 1), used to reduce products by
+	// multiplication instead of long division.
+	hRevInv poly
+}
+
+func newPolyQuotientRing(f polyField, h poly) polyQuotientRing {
+	h = f.monic(h)
+
+	r := polyQuotientRing{polyField: f, h: h}
+	if d := h.degree(); d > 1 {
+		r.hRevInv = f.inverseSeries(reverse(h, d+1), d-1)
+	}
+
+	return r
+}
+
+// reduce returns a mod h.
+func (r polyQuotientRing) reduce(a poly) poly {
+	d := r.h.degree()
+	if a.degree() < d {
+		return a
+	}
+
+	if d < 2 || a.degree() > 2*d-2 {
+		_, rem := r.divMod(a, r.h)
+		return rem
+	}
+
+	// rev(q) = rev(a) / rev(h) mod x^m, with m the number of terms of q
+	m := a.degree() - d + 1
+	qRev := truncate(r.polyField.mul(truncate(reverse(a, a.degree()+1), m), truncate(r.hRevInv, m)), m)
+	q := reverse(qRev, m)
+
+	return truncate(r.sub(a, r.polyField.mul(q, r.h)), d)
+}
+
+func (r polyQuotientRing) mul(a, b poly) poly {
+	return r.reduce(r.polyField.mul(a, b))
+}
+
+// exp returns a^e mod h.
+func (r polyQuotientRing) exp(a poly, e *big.Int) poly {
+	result := r.reduce(r.constant(bi1))
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = r.mul(result, result)
+		if e.Bit(i) == 1 {
+			result = r.mul(result, a)
+		}
+	}
+
+	return result
+}
+
+// inverse returns 1/a mod h and 1 if a is invertible, or nil and gcd(a, h)
+// otherwise.
+func (r polyQuotientRing) inverse(a poly) (poly, poly) {
+	// extended Euclid keeping only the coefficient of a
+	r0, r1 := r.h, r.reduce(a)
+	s0, s1 := poly{}, r.constant(bi1)
+
+	for !r1.isZero() {
+		q, rem := r.divMod(r0, r1)
+		r0, r1 = r1, rem
+		s0, s1 = s1, r.sub(s0, r.polyField.mul(q, s1))
+	}
+
+	if r0.degree() != 0 {
+		return nil, r.monic(r0)
+	}
+
+	inv := r.scale(s0, new(big.Int).ModInverse(r0[0], r.p))
+
+	return r.reduce(inv), r.constant(bi1)
+}
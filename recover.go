@@ -0,0 +1,73 @@
+package becc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+var ErrInvalidSignature error = errors.New("invalid signature")
+
+// SignRecoverable returns the deterministic low-s signature of the message
+// (RFC 6979) together with its recovery id, from which RecoverPublicKey
+// recovers the public key: bit 0 is the parity of the y-coordinate of the
+// point R, and bit 1 is set when its x-coordinate is n or more, so that r is
+// the reduced x-coordinate. Bit 1 is set with negligible probability on
+// curves whose order is close to the field size, such as secp256k1.
+//
+// Whatever hf is, the nonces are generated with HMAC-SHA256 over the hash of
+// the message, as libsecp256k1 does, so that the signatures match those of
+// Bitcoin and Ethereum software. With SHA256 they are the signatures of
+// SignDeterministic.
+func (priv PrivateKey) SignRecoverable(hf HashFunc, message []byte) (Signature, byte, error) {
+	return priv.signDeterministic(hf, SHA256, message, true)
+}
+
+// RecoverPublicKey returns the public key whose signature of the message is
+// sig, given the recovery id of SignRecoverable (SEC 1, section 4.1.6):
+// Q = r⁻¹·(s·R - z·G), where R is the point with the x-coordinate
+// r + (id >> 1)·n and the y parity id & 1.
+func (e *ECC) RecoverPublicKey(hf HashFunc, message []byte, sig Signature, recoveryID byte) (PublicKey, error) {
+	if recoveryID > 3 {
+		return PublicKey{}, fmt.Errorf("%w: recovery id %d", ErrInvalidSignature, recoveryID)
+	}
+
+	if sig.r.Cmp(bi1) < 0 || sig.s.Cmp(bi1) < 0 || sig.r.Cmp(e.n) >= 0 || sig.s.Cmp(e.n) >= 0 {
+		return PublicKey{}, fmt.Errorf("%w: r or s out of range", ErrInvalidSignature)
+	}
+
+	x := new(big.Int).Set(sig.r)
+	if recoveryID&2 != 0 {
+		x.Add(x, e.n)
+	}
+
+	if !e.inField(x) {
+		return PublicKey{}, fmt.Errorf("%w: x-coordinate of R out of range", ErrInvalidSignature)
+	}
+
+	r, err := e.NewPublicKeyCompressed(slices.Concat([]byte{2 + recoveryID&1}, x.FillBytes(make([]byte, e.CoordinateSize()))))
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("%w: R is not a curve point", ErrInvalidSignature)
+	}
+
+	h := hf()
+	h.Write(message)
+	hash := h.Sum(nil)
+
+	z := e.hashToInt(hash)
+
+	rInv := modInverse(sig.r, e.n)
+	u1 := new(big.Int).Mul(z, rInv)
+	u1.Neg(u1).Mod(u1, e.n)
+	u2 := new(big.Int).Mul(sig.s, rInv)
+	u2.Mod(u2, e.n)
+
+	q := e.g.ScalarMul(u1).Add(r.p.ScalarMul(u2))
+	pub, err := e.NewPublicKey(q)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return pub, nil
+}
@@ -0,0 +1,90 @@
+package becc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestRecoverPublicKey(t *testing.T) {
+	for _, ecc := range []*ECC{Secp256k1ECC(), Secp256r1ECC(), Secp384r1ECC()} {
+		t.Run(ecc.Name(), func(t *testing.T) {
+			for i := range 4 {
+				priv, pub, err := ecc.GenKeyPair()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				message := []byte(fmt.Sprintf("message %d", i))
+
+				sig, recoveryID, err := priv.SignRecoverable(SHA256, message)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !pub.Verify(SHA256, message, sig) {
+					t.Fatalf("invalid signature")
+				}
+
+				if sig.S().Cmp(new(big.Int).Rsh(ecc.Order(), 1)) > 0 {
+					t.Errorf("got high s")
+				}
+
+				deterministic, err := priv.SignDeterministic(SHA256, message, true)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if deterministic.R().Cmp(sig.R()) != 0 || deterministic.S().Cmp(sig.S()) != 0 {
+					t.Errorf("the signature differs from the SignDeterministic one")
+				}
+
+				recovered, err := ecc.RecoverPublicKey(SHA256, message, sig, recoveryID)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !recovered.Point().Eq(pub.Point()) {
+					t.Errorf("recovered %x, want %x", recovered.Compressed(), pub.Compressed())
+				}
+
+				// the other parity recovers another key
+				other, err := ecc.RecoverPublicKey(SHA256, message, sig, recoveryID^1)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if other.Point().Eq(pub.Point()) {
+					t.Errorf("recovered the key with the wrong recovery id")
+				}
+			}
+		})
+	}
+
+	ecc := Secp256k1ECC()
+	priv, _, err := ecc.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sig, _, err := priv.SignRecoverable(SHA256, []byte("message"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		sig        Signature
+		recoveryID byte
+	}{
+		{name: "recovery id", sig: sig, recoveryID: 4},
+		{name: "zero r", sig: NewSignature(big.NewInt(0), sig.S()), recoveryID: 0},
+		{name: "s out of range", sig: NewSignature(sig.R(), ecc.Order()), recoveryID: 0},
+		{name: "r + n out of the field", sig: NewSignature(new(big.Int).Sub(ecc.Order(), big.NewInt(1)), sig.S()), recoveryID: 2},
+	} {
+		if _, err := ecc.RecoverPublicKey(SHA256, []byte("message"), tt.sig, tt.recoveryID); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: expected error %v, got %v", tt.name, ErrInvalidSignature, err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package becc
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// CurveInfo describes a named curve of the registry.
+type CurveInfo struct {
+	// Name is the canonical SEC 2 name of the curve.
+	Name string
+
+	// Aliases are other names the curve is known by, such as its NIST or
+	// ANSI X9.62 name.
+	Aliases []string
+
+	// OID is the ASN.1 object identifier of the curve.
+	OID asn1.ObjectIdentifier
+
+	// FieldSize is the size in bits of the field prime.
+	FieldSize int
+
+	// Cofactor is the cofactor h = #E(F_p) / n.
+	Cofactor *big.Int
+
+	// Security is the approximate security level in bits.
+	Security int
+
+	params func() (EllipticCurve, Point, *big.Int)
+}
+
+// ECC returns a new ECC instance for the curve.
+func (ci CurveInfo) ECC() *ECC {
+	ec, g, n := ci.params()
+
+	return &ECC{
+		name:     ci.Name,
+		ec:       ec,
+		g:        g,
+		n:        n,
+		h:        new(big.Int).Set(ci.Cofactor),
+		security: ci.Security,
+	}
+}
+
+// clone returns a copy of ci that shares no slices or big integers with it.
+func (ci CurveInfo) clone() CurveInfo {
+	ci.Aliases = slices.Clone(ci.Aliases)
+	ci.OID = slices.Clone(ci.OID)
+	ci.Cofactor = new(big.Int).Set(ci.Cofactor)
+
+	return ci
+}
+
+var ErrUnknownCurve error = errors.New("unknown curve")
+
+var registry = []CurveInfo{
+	{
+		Name:      "secp256k1",
+		Aliases:   []string{"K-256"},
+		OID:       asn1.ObjectIdentifier{1, 3, 132, 0, 10},
+		FieldSize: 256,
+		Cofactor:  Secp256k1H,
+		Security:  128,
+		params:    Secp256k1,
+	},
+	{
+		Name:      "secp256r1",
+		Aliases:   []string{"P-256", "prime256v1"},
+		OID:       asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7},
+		FieldSize: 256,
+		Cofactor:  Secp256r1H,
+		Security:  128,
+		params:    Secp256r1,
+	},
+	{
+		Name:      "secp384r1",
+		Aliases:   []string{"P-384"},
+		OID:       asn1.ObjectIdentifier{1, 3, 132, 0, 34},
+		FieldSize: 384,
+		Cofactor:  Secp384r1H,
+		Security:  192,
+		params:    Secp384r1,
+	},
+	{
+		Name:      "secp521r1",
+		Aliases:   []string{"P-521"},
+		OID:       asn1.ObjectIdentifier{1, 3, 132, 0, 35},
+		FieldSize: 521,
+		Cofactor:  Secp521r1H,
+		Security:  256,
+		params:    Secp521r1,
+	},
+	{
+		Name:      "brainpoolP256r1",
+		OID:       asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 7},
+		FieldSize: 256,
+		Cofactor:  BrainpoolP256r1H,
+		Security:  128,
+		params:    BrainpoolP256r1,
+	},
+	{
+		Name:      "brainpoolP256t1",
+		OID:       asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 8},
+		FieldSize: 256,
+		Cofactor:  BrainpoolP256t1H,
+		Security:  128,
+		params:    BrainpoolP256t1,
+	},
+	{
+		Name:      "brainpoolP384r1",
+		OID:       asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 11},
+		FieldSize: 384,
+		Cofactor:  BrainpoolP384r1H,
+		Security:  192,
+		params:    BrainpoolP384r1,
+	},
+	{
+		Name:      "brainpoolP384t1",
+		OID:       asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 12},
+		FieldSize: 384,
+		Cofactor:  BrainpoolP384t1H,
+		Security:  192,
+		params:    BrainpoolP384t1,
+	},
+	{
+		Name:      "brainpoolP512r1",
+		OID:       asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 13},
+		FieldSize: 512,
+		Cofactor:  BrainpoolP512r1H,
+		Security:  256,
+		params:    BrainpoolP512r1,
+	},
+	{
+		Name:      "brainpoolP512t1",
+		OID:       asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 14},
+		FieldSize: 512,
+		Cofactor:  BrainpoolP512t1H,
+		Security:  256,
+		params:    BrainpoolP512t1,
+	},
+	{
+		Name:      "sm2p256v1",
+		Aliases:   []string{"SM2"},
+		OID:       asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301},
+		FieldSize: 256,
+		Cofactor:  SM2H,
+		Security:  128,
+		params:    SM2,
+	},
+}
+
+// Curves returns the description of every curve in the registry. The
+// descriptions are copies, which the caller may modify.
+func Curves() []CurveInfo {
+	curves := make([]CurveInfo, len(registry))
+	for i, ci := range registry {
+		curves[i] = ci.clone()
+	}
+
+	return curves
+}
+
+func lookupCurveInfo(name string) (CurveInfo, error) {
+	for _, ci := range registry {
+		if strings.EqualFold(ci.Name, name) {
+			return ci, nil
+		}
+
+		for _, alias := range ci.Aliases {
+			if strings.EqualFold(alias, name) {
+				return ci, nil
+			}
+		}
+	}
+
+	return CurveInfo{}, fmt.Errorf("%w: %q", ErrUnknownCurve, name)
+}
+
+// LookupCurve returns a new ECC instance for the curve whose name or one of
+// its aliases matches name, ignoring case.
+func LookupCurve(name string) (*ECC, error) {
+	ci, err := lookupCurveInfo(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ci.ECC(), nil
+}
+
+// CurveByOID returns a new ECC instance for the curve identified by oid.
+func CurveByOID(oid asn1.ObjectIdentifier) (*ECC, error) {
+	for _, ci := range registry {
+		if ci.OID.Equal(oid) {
+			return ci.ECC(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: OID %s", ErrUnknownCurve, oid)
+}
+
+func mustLookupCurve(name string) *ECC {
+	ecc, err := LookupCurve(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ecc
+}
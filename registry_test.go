@@ -0,0 +1,112 @@
+package becc
+
+import (
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestLookupCurve(t *testing.T) {
+	tt := []struct {
+		name     string
+		expected string
+	}{
+		{name: "secp256k1", expected: "secp256k1"},
+		{name: "K-256", expected: "secp256k1"},
+		{name: "secp256r1", expected: "secp256r1"},
+		{name: "P-256", expected: "secp256r1"},
+		{name: "prime256v1", expected: "secp256r1"},
+		{name: "p-256", expected: "secp256r1"},
+		{name: "secp384r1", expected: "secp384r1"},
+		{name: "P-384", expected: "secp384r1"},
+		{name: "SECP521R1", expected: "secp521r1"},
+		{name: "P-521", expected: "secp521r1"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ecc, err := LookupCurve(tc.name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ecc.Name() != tc.expected {
+				t.Errorf("got %s, expected %s", ecc.Name(), tc.expected)
+			}
+		})
+	}
+
+	if _, err := LookupCurve("P-192"); !errors.Is(err, ErrUnknownCurve) {
+		t.Errorf("got %v, expected ErrUnknownCurve", err)
+	}
+}
+
+func TestCurveByOID(t *testing.T) {
+	tt := []struct {
+		oid      asn1.ObjectIdentifier
+		expected string
+	}{
+		{oid: asn1.ObjectIdentifier{1, 3, 132, 0, 10}, expected: "secp256k1"},
+		{oid: asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, expected: "secp256r1"},
+		{oid: asn1.ObjectIdentifier{1, 3, 132, 0, 34}, expected: "secp384r1"},
+		{oid: asn1.ObjectIdentifier{1, 3, 132, 0, 35}, expected: "secp521r1"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.oid.String(), func(t *testing.T) {
+			ecc, err := CurveByOID(tc.oid)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if ecc.Name() != tc.expected {
+				t.Errorf("got %s, expected %s", ecc.Name(), tc.expected)
+			}
+		})
+	}
+
+	if _, err := CurveByOID(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 1}); !errors.Is(err, ErrUnknownCurve) {
+		t.Errorf("got %v, expected ErrUnknownCurve", err)
+	}
+}
+
+func TestCurvesInfo(t *testing.T) {
+	for _, ci := range Curves() {
+		t.Run(ci.Name, func(t *testing.T) {
+			ecc := ci.ECC()
+
+			if got := ecc.ec.m.BitLen(); got != ci.FieldSize {
+				t.Errorf("field size: got %d, expected %d", got, ci.FieldSize)
+			}
+
+			if ecc.h.Cmp(ci.Cofactor) != 0 {
+				t.Errorf("cofactor: got %d, expected %d", ecc.h, ci.Cofactor)
+			}
+
+			if ecc.Security() != ci.Security {
+				t.Errorf("security: got %d, expected %d", ecc.Security(), ci.Security)
+			}
+
+			if !ecc.ec.IsOnCurve(ecc.g) {
+				t.Errorf("generator not in curve")
+			}
+		})
+	}
+}
+
+func TestCurvesCopy(t *testing.T) {
+	curves := Curves()
+	curves[0].Cofactor.SetInt64(7)
+	curves[0].Aliases[0] = "changed"
+	curves[0].OID[0] = 9
+
+	ci := Curves()[0]
+	if ci.Cofactor.Cmp(big.NewInt(1)) != 0 || ci.Aliases[0] == "changed" || ci.OID[0] == 9 {
+		t.Errorf("modifying the result of Curves changed the registry: %+v", ci)
+	}
+
+	if ecc := ci.ECC(); ecc.Cofactor().Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("got cofactor %d, expected 1", ecc.Cofactor())
+	}
+}
@@ -0,0 +1,188 @@
+package becc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// SchnorrSignature is a BIP-340 Schnorr signature: the x-coordinate r of the
+// nonce point R, which has an even y, and s = k + e·d mod n.
+type SchnorrSignature struct {
+	r *big.Int
+	s *big.Int
+}
+
+func NewSchnorrSignature(r, s *big.Int) SchnorrSignature {
+	return SchnorrSignature{
+		r: r,
+		s: s,
+	}
+}
+
+// ParseSchnorrSignature parses the 64-byte encoding r || s of a signature.
+// The ranges of r and s are checked by VerifySchnorr.
+func ParseSchnorrSignature(b []byte) (SchnorrSignature, error) {
+	if len(b) != 64 {
+		return SchnorrSignature{}, errors.New("invalid signature format: invalid length")
+	}
+
+	return SchnorrSignature{
+		r: new(big.Int).SetBytes(b[:32]),
+		s: new(big.Int).SetBytes(b[32:]),
+	}, nil
+}
+
+func (sig SchnorrSignature) R() *big.Int {
+	return new(big.Int).Set(sig.r)
+}
+
+func (sig SchnorrSignature) S() *big.Int {
+	return new(big.Int).Set(sig.s)
+}
+
+// Bytes returns the 64-byte encoding r || s of the signature.
+func (sig SchnorrSignature) Bytes() []byte {
+	b := make([]byte, 64)
+	sig.r.FillBytes(b[:32])
+	sig.s.FillBytes(b[32:])
+
+	return b
+}
+
+// SignSchnorr signs the message with BIP-340, where the public key is the
+// x-coordinate of the key with an even y (XOnly). auxRand is the 32 bytes of
+// auxiliary randomness mixed into the nonce; if it is nil, fresh random bytes
+// are used. BIP-340 is only defined for secp256k1.
+func (priv PrivateKey) SignSchnorr(message, auxRand []byte) (SchnorrSignature, error) {
+	e := priv.ecc
+	if err := e.checkBIP340(); err != nil {
+		return SchnorrSignature{}, err
+	}
+
+	if auxRand == nil {
+		auxRand = make([]byte, 32)
+		if _, err := rand.Read(auxRand); err != nil {
+			return SchnorrSignature{}, err
+		}
+	}
+
+	if len(auxRand) != 32 {
+		return SchnorrSignature{}, fmt.Errorf("%w: the auxiliary randomness must be 32 bytes", ErrInvalidParameters)
+	}
+
+	p := e.g.ScalarMul(priv.d)
+	d := evenYScalar(priv.d, p, e.n)
+	px := p.x.n.FillBytes(make([]byte, 32))
+
+	t := TaggedHash("BIP0340/aux", auxRand)
+	for i, b := range d.FillBytes(make([]byte, 32)) {
+		t[i] ^= b
+	}
+
+	k := new(big.Int).SetBytes(TaggedHash("BIP0340/nonce", t, px, message))
+	k.Mod(k, e.n)
+	if k.Sign() == 0 {
+		return SchnorrSignature{}, errors.New("nonce is zero")
+	}
+
+	r := e.g.ScalarMul(k)
+	k = evenYScalar(k, r, e.n)
+	rx := r.x.n.FillBytes(make([]byte, 32))
+
+	c := bip340Challenge(rx, px, message, e.n)
+
+	s := new(big.Int).Mul(c, d)
+	s.Add(s, k).Mod(s, e.n)
+
+	sig := SchnorrSignature{r: new(big.Int).Set(r.x.n), s: s}
+	if !priv.PublicKey().VerifySchnorr(message, sig) {
+		return SchnorrSignature{}, errors.New("signature verification failed")
+	}
+
+	return sig, nil
+}
+
+// VerifySchnorr reports whether sig is a valid BIP-340 signature of the
+// message for the x-only public key of pub: only the x-coordinate of the key
+// is used, so pub and -pub accept the same signatures.
+func (pub PublicKey) VerifySchnorr(message []byte, sig SchnorrSignature) bool {
+	e := pub.ecc
+	if e.checkBIP340() != nil || sig.r == nil || sig.s == nil {
+		return false
+	}
+
+	if !e.inField(sig.r) || sig.s.Sign() < 0 || sig.s.Cmp(e.n) >= 0 {
+		return false
+	}
+
+	px := pub.XOnly()
+	c := bip340Challenge(sig.r.FillBytes(make([]byte, 32)), px, message, e.n)
+
+	// R = s·G - e·P, with P the point of x with an even y
+	p := pub.p
+	if p.y.n.Bit(0) == 1 {
+		p = p.Neg()
+	}
+
+	r := e.g.ScalarMul(sig.s).Add(p.ScalarMul(c).Neg())
+
+	return !r.IsInfinity() && r.y.n.Bit(0) == 0 && r.x.n.Cmp(sig.r) == 0
+}
+
+// XOnly returns the 32-byte x-only encoding of the public key of BIP-340.
+func (pub PublicKey) XOnly() []byte {
+	return pub.p.x.n.FillBytes(make([]byte, pub.ecc.CoordinateSize()))
+}
+
+// NewPublicKeyXOnly decodes a BIP-340 x-only public key: the point with the
+// x-coordinate b and an even y.
+func (e *ECC) NewPublicKeyXOnly(b []byte) (PublicKey, error) {
+	if len(b) != e.CoordinateSize() {
+		return PublicKey{}, errors.New("invalid key format: invalid length")
+	}
+
+	return e.NewPublicKeyCompressed(slices.Concat([]byte{2}, b))
+}
+
+// TaggedHash returns the tagged hash of BIP-340:
+// SHA-256(SHA-256(tag) || SHA-256(tag) || msg).
+func TaggedHash(tag string, msg ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msg {
+		h.Write(m)
+	}
+
+	return h.Sum(nil)
+}
+
+func (e *ECC) checkBIP340() error {
+	if e.name != "secp256k1" {
+		return fmt.Errorf("%w: BIP-340 is only defined for secp256k1", ErrUnsupportedCurve)
+	}
+
+	return e.checkNamedCurve()
+}
+
+func bip340Challenge(rx, px, message []byte, n *big.Int) *big.Int {
+	c := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", rx, px, message))
+
+	return c.Mod(c, n)
+}
+
+// evenYScalar returns k if k·G = p has an even y, and n - k otherwise, so
+// that the result times G has an even y.
+func evenYScalar(k *big.Int, p Point, n *big.Int) *big.Int {
+	if p.y.n.Bit(0) == 0 {
+		return new(big.Int).Set(k)
+	}
+
+	return new(big.Int).Sub(n, k)
+}
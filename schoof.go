@@ -0,0 +1,714 @@
+package becc
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+)
+
+// naivePointCountLimit is the largest field modulus for which Order counts
+// the points one x-coordinate at a time instead of using Schoof's algorithm.
+const naivePointCountLimit = 1 << 16
+
+// schoofMaxPrime is the largest prime l for which Order computes t mod l
+// with Schoof's algorithm on curves other than j = 0 and 1728: the larger ones
+// use Elkies' procedure.
+const schoofMaxPrime = 7
+
+// pointCountMaxPrime is the largest prime l for which Order computes t mod l.
+const pointCountMaxPrime = 1000
+
+// searchCandidateLimit is the number of traces left by the Hasse bound below
+// which Order stops computing traces and searches the order with baby-step
+// giant-step, which takes about 2^21 point additions at the limit.
+const searchCandidateLimit = 1 << 40
+
+// schoofCandidateLimit is the largest number of candidate orders that are
+// tested on points one by one.
+const schoofCandidateLimit = 64
+
+// schoofTestPoints is the number of points used to tell the candidate orders
+// apart.
+const schoofTestPoints = 8
+
+// ErrPointCount is returned by Order when no point count is consistent with
+// the traces it computed.
+var ErrPointCount error = errors.New("cannot count the points of the curve")
+
+// Order returns the number of points #E(F_p) of the curve, including the
+// point at infinity. The modulus must be a prime greater than 3, or
+// ErrInvalidParameters is returned.
+//
+// Curves with j-invariant 0 (a = 0, like secp256k1) or 1728 (b = 0) have
+// complex multiplication, and their trace is found from a representation of
+// p as x^2 + 3y^2 or x^2 + y^2, which takes milliseconds at any size.
+//
+// Other curves use the Schoof-Elkies-Atkin algorithm (SEA): the trace
+// t = p + 1 - #E is computed modulo small primes l from the action of the
+// Frobenius endomorphism on the l-torsion, and combined with the CRT until
+// the Hasse bound |t| <= 2 sqrt(p) leaves few enough candidates to find the
+// right one with baby-step giant-step. For l <= 7 the whole l-torsion is
+// used, as in Schoof's algorithm. For the larger Elkies primes, those for
+// which the modular polynomial Φ_l(X, j) has a root in F_p, the Frobenius
+// acts on a subgroup of order l, whose points are the roots of a polynomial
+// of degree (l - 1)/2 instead of (l^2 - 1)/2. The Atkin primes, for which
+// Φ_l(X, j) has no root, are skipped. This takes about half a minute for
+// 256-bit fields, and tens of minutes for 384-bit fields and above.
+func (ec EllipticCurve) Order() (*big.Int, error) {
+	return ec.order(newModularPolynomials(ec.m), nil)
+}
+
+// order implements Order, taking the canonical modular polynomials modulo p
+// from modular. After computing t mod l it calls reject, if not nil, with l
+// and #E mod l, and gives up returning a nil count if reject returns true, so
+// that curves with a small factor in their order can be discarded early.
+func (ec EllipticCurve) order(modular *modularPolynomials, reject func(l, pointCountMod int64) bool) (*big.Int, error) {
+	p := ec.m
+	if p.Cmp(big.NewInt(3)) <= 0 || !p.ProbablyPrime(20) {
+		return nil, fmt.Errorf("%w: field modulus is not a prime greater than 3", ErrInvalidParameters)
+	}
+
+	if p.Cmp(big.NewInt(naivePointCountLimit)) <= 0 {
+		return ec.countPointsNaive(), nil
+	}
+
+	if candidates := ec.complexMultiplicationCandidates(); candidates != nil {
+		if order := ec.matchOrder(candidates); order != nil {
+			return order, nil
+		}
+	}
+
+	pPlus1 := new(big.Int).Add(p, bi1)
+
+	// |t| <= tMax = floor(2 sqrt(p))
+	tMax := new(big.Int).Sqrt(new(big.Int).Mul(p, bi4))
+
+	t, modulus := big.NewInt(0), big.NewInt(1)
+
+	// the formulas of Elkies' procedure need j != 0, 1728
+	elkies := ec.a.n.Sign() != 0 && ec.b.n.Sign() != 0
+
+	for _, l := range pointCountPrimes(p, elkies) {
+		var (
+			tl  int64
+			err error
+		)
+		switch {
+		case l == 2:
+			tl = ec.traceMod2()
+		case l <= schoofMaxPrime || !elkies:
+			if tl, err = ec.traceMod(l); err != nil {
+				return nil, err
+			}
+		default:
+			var ok bool
+			if tl, ok = ec.traceModElkies(modular.get(l)); !ok {
+				continue
+			}
+		}
+
+		bl := big.NewInt(l)
+		if reject != nil {
+			// #E = p + 1 - t
+			pointCountMod := new(big.Int).Add(pPlus1, big.NewInt(-tl))
+			if reject(l, pointCountMod.Mod(pointCountMod, bl).Int64()) {
+				return nil, nil
+			}
+		}
+
+		// t = t + modulus * ((tl - t) / modulus mod l)
+		k := new(big.Int).Sub(big.NewInt(tl), t)
+		k.Mul(k, new(big.Int).ModInverse(modulus, bl)).Mod(k, bl)
+		t.Add(t, k.Mul(k, modulus))
+		modulus.Mul(modulus, bl)
+
+		// smallest candidate above -tMax
+		first := new(big.Int).Add(t, tMax)
+		first.Mod(first, modulus).Sub(first, tMax)
+
+		if first.Cmp(tMax) > 0 {
+			return nil, fmt.Errorf("%w: no trace within the Hasse bound", ErrPointCount)
+		}
+
+		count := new(big.Int).Sub(tMax, first)
+		count.Div(count, modulus).Add(count, bi1)
+
+		if count.Cmp(bi1) == 0 {
+			return first.Sub(pPlus1, first), nil
+		}
+
+		if count.Cmp(big.NewInt(searchCandidateLimit)) > 0 {
+			continue
+		}
+
+		if order := ec.searchOrder(first, modulus, count.Int64()); order != nil {
+			return order, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: not enough primes", ErrPointCount)
+}
+
+// pointCountPrimes returns the primes l <= pointCountMaxPrime other than p
+// for which order computes t mod l: in increasing order, or when elkies is
+// true, the ones above schoofMaxPrime in increasing order of the estimated
+// cost of traceModElkies, the computation of the canonical modular
+// polynomial followed by the exponentiations modulo Φ_l(X, j), of degree
+// l + 1. The primes with s = 6, whose modular polynomials take the longest,
+// come well after the others of the same size.
+func pointCountPrimes(p *big.Int, elkies bool) []int64 {
+	var primes []int64
+	for l := int64(2); l <= pointCountMaxPrime; l++ {
+		if big.NewInt(l).ProbablyPrime(0) && p.Cmp(big.NewInt(l)) != 0 {
+			primes = append(primes, l)
+		}
+	}
+
+	if elkies {
+		cost := func(l int64) float64 {
+			return modularCost(l) + 3*float64(l*l*int64(p.BitLen()))
+		}
+
+		i, _ := slices.BinarySearch(primes, schoofMaxPrime+1)
+		slices.SortStableFunc(primes[i:], func(l1, l2 int64) int {
+			return cmp.Compare(cost(l1), cost(l2))
+		})
+	}
+
+	return primes
+}
+
+// complexMultiplicationCandidates returns the possible values of #E when
+// j = 0 or j = 1728, or nil for other curves. The Frobenius of these curves is
+// an element π of Z[ω] or Z[i] with norm p, unique up to the units and
+// conjugation, so t = π + π' takes at most six values:
+//
+//   - j = 0 and p = x^2 + 3y^2: t is ±2x, ±(x + 3y) or ±(x - 3y)
+//   - j = 1728 and p = x^2 + y^2: t is ±2x or ±2y
+//
+// When p has no such representation the curve is supersingular and t = 0.
+func (ec EllipticCurve) complexMultiplicationCandidates() []*big.Int {
+	p := ec.m
+
+	var d int64
+	switch {
+	case ec.a.n.Sign() == 0:
+		d = 3
+	case ec.b.n.Sign() == 0:
+		d = 1
+	default:
+		return nil
+	}
+
+	pPlus1 := new(big.Int).Add(p, bi1)
+
+	x, y := cornacchia(d, p)
+	if x == nil {
+		return []*big.Int{pPlus1}
+	}
+
+	var traces []*big.Int
+	if d == 3 {
+		y3 := new(big.Int).Mul(y, big.NewInt(3))
+		traces = []*big.Int{
+			new(big.Int).Lsh(x, 1),
+			new(big.Int).Add(x, y3),
+			new(big.Int).Sub(x, y3),
+		}
+	} else {
+		traces = []*big.Int{new(big.Int).Lsh(x, 1), new(big.Int).Lsh(y, 1)}
+	}
+
+	candidates := []*big.Int{}
+	for _, t := range traces {
+		candidates = append(candidates,
+			new(big.Int).Sub(pPlus1, t),
+			new(big.Int).Add(pPlus1, t),
+		)
+	}
+
+	return candidates
+}
+
+// cornacchia returns x, y >= 0 with x^2 + d·y^2 = p for a prime p, or nil if
+// there are none.
+func cornacchia(d int64, p *big.Int) (*big.Int, *big.Int) {
+	bd := big.NewInt(d)
+
+	r := new(big.Int).ModSqrt(new(big.Int).Mod(new(big.Int).Neg(bd), p), p)
+	if r == nil {
+		return nil, nil
+	}
+
+	if half := new(big.Int).Rsh(p, 1); r.Cmp(half) > 0 {
+		r.Sub(p, r)
+	}
+
+	// Euclid's algorithm on p and r until the remainder is below sqrt(p)
+	a, b := new(big.Int).Set(p), r
+	for new(big.Int).Mul(b, b).Cmp(p) >= 0 {
+		a, b = b, new(big.Int).Mod(a, b)
+	}
+
+	rest := new(big.Int).Sub(p, new(big.Int).Mul(b, b))
+	if new(big.Int).Mod(rest, bd).Sign() != 0 {
+		return nil, nil
+	}
+
+	rest.Div(rest, bd)
+	y := new(big.Int).Sqrt(rest)
+	if new(big.Int).Mul(y, y).Cmp(rest) != 0 {
+		return nil, nil
+	}
+
+	return b, y
+}
+
+// searchOrder returns #E = p + 1 - t for the only trace t = first + k·modulus
+// with 0 <= k < count consistent with the points of the curve, or nil if
+// the points do not tell the candidates apart. For a point P, the k with
+// (p + 1 - first)·P = k·(modulus·P) are found with baby-step giant-step in
+// about 2 sqrt(count/2) additions, since the baby steps ±j·R share their x,
+// and the few that are left are tested on other points with matchOrder.
+func (ec EllipticCurve) searchOrder(first, modulus *big.Int, count int64) *big.Int {
+	n0 := new(big.Int).Add(ec.m, bi1)
+	n0.Sub(n0, first)
+
+	steps := int64(math.Sqrt(float64(count)/2)) + 1
+	stride := 2*steps + 1
+
+	tested := 0
+	for x := big.NewInt(0); x.Cmp(ec.m) < 0 && tested < schoofTestPoints; x.Add(x, bi1) {
+		ys := ec.Y(x)
+		if len(ys) == 0 {
+			continue
+		}
+		tested++
+
+		pt := affinePoint{x: new(big.Int).Set(x), y: ys[0]}
+		q, r := ec.mulAffine(pt, n0), ec.mulAffine(pt, modulus)
+		if r.inf {
+			continue
+		}
+
+		// baby steps: j·R for 1 <= j <= steps, by the low bits of their x
+		baby := make(map[uint64]int64, steps)
+		jR := r
+		for j := int64(1); j <= steps; j++ {
+			if key := jR.x.Uint64(); baby[key] == 0 {
+				baby[key] = j
+			}
+			jR = ec.addAffine(jR, r)
+		}
+
+		// giant steps: Q - i·stride·R = ±j·R gives k = i·stride ± j
+		var candidates []*big.Int
+		giant := ec.mulAffine(r, big.NewInt(-stride))
+		g := q
+		for i := int64(0); i*stride < count+stride; i++ {
+			var ks []int64
+			if g.inf {
+				ks = []int64{i * stride}
+			} else if j, ok := baby[g.x.Uint64()]; ok {
+				switch jR := ec.mulAffine(r, big.NewInt(j)); {
+				case jR.x.Cmp(g.x) != 0:
+				case jR.y.Cmp(g.y) == 0:
+					ks = []int64{i*stride + j}
+				default:
+					ks = []int64{i*stride - j}
+				}
+			}
+
+			for _, k := range ks {
+				if k >= 0 && k < count {
+					n := new(big.Int).Mul(big.NewInt(k), modulus)
+					candidates = append(candidates, n.Sub(n0, n))
+				}
+			}
+
+			if len(candidates) > schoofCandidateLimit {
+				break
+			}
+
+			g = ec.addAffine(g, giant)
+		}
+
+		// P has a small order: try another point
+		if len(candidates) > schoofCandidateLimit {
+			continue
+		}
+
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		return ec.matchOrder(candidates)
+	}
+
+	return nil
+}
+
+// affinePoint is a point of the curve with big.Int coordinates, or the point
+// at infinity. searchOrder uses it instead of Point, whose additions cost
+// several times as much.
+type affinePoint struct {
+	x, y *big.Int
+	inf  bool
+}
+
+func (ec EllipticCurve) addAffine(p1, p2 affinePoint) affinePoint {
+	if p1.inf {
+		return p2
+	}
+
+	if p2.inf {
+		return p1
+	}
+
+	p := ec.m
+	var slope *big.Int
+	if p1.x.Cmp(p2.x) == 0 {
+		if p1.y.Cmp(p2.y) != 0 || p1.y.Sign() == 0 {
+			return affinePoint{inf: true}
+		}
+
+		// (3x^2 + a) / 2y
+		slope = new(big.Int).Mul(p1.x, p1.x)
+		slope.Mul(slope, big.NewInt(3)).Add(slope, ec.a.n)
+		slope.Mul(slope, new(big.Int).ModInverse(new(big.Int).Lsh(p1.y, 1), p))
+	} else {
+		dx := new(big.Int).Sub(p2.x, p1.x)
+		slope = new(big.Int).Sub(p2.y, p1.y)
+		slope.Mul(slope, dx.ModInverse(dx.Mod(dx, p), p))
+	}
+	slope.Mod(slope, p)
+
+	x := new(big.Int).Mul(slope, slope)
+	x.Sub(x, p1.x).Sub(x, p2.x).Mod(x, p)
+
+	y := new(big.Int).Sub(p1.x, x)
+	y.Mul(y, slope).Sub(y, p1.y).Mod(y, p)
+
+	return affinePoint{x: x, y: y}
+}
+
+func (ec EllipticCurve) mulAffine(pt affinePoint, k *big.Int) affinePoint {
+	if k.Sign() < 0 {
+		k = new(big.Int).Neg(k)
+		if !pt.inf {
+			pt = affinePoint{x: pt.x, y: new(big.Int).Sub(ec.m, pt.y)}
+		}
+	}
+
+	result := affinePoint{inf: true}
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = ec.addAffine(result, result)
+		if k.Bit(i) == 1 {
+			result = ec.addAffine(result, pt)
+		}
+	}
+
+	return result
+}
+
+// matchOrder returns the only candidate n with n·P = ∞ for a few points P of
+// the curve, or nil if the points do not tell the candidates apart.
+func (ec EllipticCurve) matchOrder(candidates []*big.Int) *big.Int {
+	tested := 0
+	for x := big.NewInt(0); x.Cmp(ec.m) < 0 && tested < schoofTestPoints; x.Add(x, bi1) {
+		ys := ec.Y(x)
+		if len(ys) == 0 {
+			continue
+		}
+		tested++
+
+		pt := ec.NewPoint(x, ys[0])
+		remaining := candidates[:0]
+		for _, n := range candidates {
+			if pt.ScalarMul(n).IsInfinity() {
+				remaining = append(remaining, n)
+			}
+		}
+		candidates = remaining
+
+		if len(candidates) == 1 {
+			return candidates[0]
+		}
+	}
+
+	return nil
+}
+
+// traceMod2 returns t mod 2, which is 0 exactly when x^3 + ax + b has a root
+// in F_p, that is when the curve has a point of order 2.
+func (ec EllipticCurve) traceMod2() int64 {
+	f := polyField{p: ec.m}
+	ring := newPolyQuotientRing(f, ec.polynomial(f))
+
+	x := f.newPoly(bi0, bi1)
+	xp := ring.exp(x, ec.m)
+
+	if f.gcd(f.sub(xp, x), ring.h).isOne() {
+		return 1
+	}
+
+	return 0
+}
+
+// polynomial returns x^3 + ax + b.
+func (ec EllipticCurve) polynomial(f polyField) poly {
+	return f.newPoly(ec.b.n, ec.a.n, bi0, bi1)
+}
+
+// traceMod returns t mod l for an odd prime l != p.
+func (ec EllipticCurve) traceMod(l int64) (int64, error) {
+	f := polyField{p: ec.m}
+	h := ec.divisionPolynomial(f, l)
+
+	for {
+		t, factor, err := ec.traceModFactor(f, h, l)
+		if err != nil || factor == nil {
+			return t, err
+		}
+
+		// the Frobenius acts on the points with x a root of any factor of
+		// the division polynomial with the same trace: keep the smaller one
+		other, _ := f.divMod(h, factor)
+		if other.degree() < factor.degree() {
+			factor = other
+		}
+		h = f.monic(factor)
+	}
+}
+
+// ringPoint is a point (x, y·y0) of the curve over F_p[x, y]/(h(x), y^2 -
+// x^3 - ax - b), where h divides the l-th division polynomial, so that every
+// ringPoint stands for l-torsion points at once. Only y0 is stored.
+type ringPoint struct {
+	x, y poly
+	inf  bool
+}
+
+type schoofRing struct {
+	polyQuotientRing
+
+	a     poly
+	curve poly
+}
+
+func (ec EllipticCurve) newSchoofRing(f polyField, h poly) schoofRing {
+	ring := schoofRing{polyQuotientRing: newPolyQuotientRing(f, h)}
+	ring.a = f.constant(ec.a.n)
+	ring.curve = ring.reduce(ec.polynomial(f))
+
+	return ring
+}
+
+// traceModFactor returns t mod l, where t is the trace of the Frobenius
+// endomorphism π, found as the τ with π^2(P) + (p mod l)P = τπ(P) for P in
+// the l-torsion with x a root of h. When a non-trivial factor of h shows up
+// it returns the factor instead and the computation has to be repeated with
+// it.
+func (ec EllipticCurve) traceModFactor(f polyField, h poly, l int64) (int64, poly, error) {
+	ring := ec.newSchoofRing(f, h)
+
+	p := ec.m
+	x := ring.reduce(f.newPoly(bi0, bi1))
+
+	// π(x, y) = (x^p, y^p) = (x^p, (x^3 + ax + b)^((p-1)/2) y)
+	halfP := new(big.Int).Rsh(p, 1)
+	frob := ringPoint{x: ring.exp(x, p), y: ring.exp(ring.curve, halfP)}
+	frob2 := ringPoint{x: ring.exp(frob.x, p), y: ring.mul(ring.exp(frob.y, p), frob.y)}
+
+	pMod := new(big.Int).Mod(p, big.NewInt(l)).Int64()
+	qP, factor := ring.scalarMul(ringPoint{x: x, y: f.constant(bi1)}, pMod)
+	if factor != nil {
+		return 0, factor, nil
+	}
+
+	q, factor := ring.addPoints(frob2, qP)
+	if factor != nil {
+		return 0, factor, nil
+	}
+
+	if q.inf {
+		return 0, nil, nil
+	}
+
+	// τπ(P) = ±Q gives the trace up to its sign, told apart by y. τπ(P) is
+	// kept in Jacobian coordinates (x = X/Z^2, y0 = Y/Z^3) to add π(P) without
+	// inversions: the sum is never degenerate for 2 <= τ < l - 1.
+	tau := jacobianPoint{x: frob.x, y: frob.y, z: f.constant(bi1)}
+	for i := int64(1); i <= (l-1)/2; i++ {
+		switch i {
+		case 1:
+		case 2:
+			double, factor := ring.addPoints(frob, frob)
+			if factor != nil {
+				return 0, factor, nil
+			}
+			tau = jacobianPoint{x: double.x, y: double.y, z: f.constant(bi1)}
+		default:
+			tau = ring.addJacobian(tau, frob)
+		}
+
+		z2 := ring.mul(tau.z, tau.z)
+		if !ring.sub(tau.x, ring.mul(q.x, z2)).isZero() {
+			continue
+		}
+
+		qy := ring.mul(q.y, ring.mul(z2, tau.z))
+		dy := ring.sub(tau.y, qy)
+		if dy.isZero() {
+			return i, nil, nil
+		}
+
+		if ring.add(tau.y, qy).isZero() {
+			return l - i, nil, nil
+		}
+
+		return 0, f.gcd(dy, ring.h), nil
+	}
+
+	return 0, nil, fmt.Errorf("%w: no trace modulo %d", ErrPointCount, l)
+}
+
+// jacobianPoint is a ringPoint (X/Z^2, y·Y/Z^3) in Jacobian coordinates.
+type jacobianPoint struct {
+	x, y, z poly
+}
+
+// addJacobian returns p1 + p2 for p1 != ±p2 at every root of h.
+func (r schoofRing) addJacobian(p1 jacobianPoint, p2 ringPoint) jacobianPoint {
+	z2 := r.mul(p1.z, p1.z)
+	hh := r.sub(r.mul(p2.x, z2), p1.x)
+	rr := r.sub(r.mul(p2.y, r.mul(z2, p1.z)), p1.y)
+
+	hh2 := r.mul(hh, hh)
+	hh3 := r.mul(hh2, hh)
+	x1hh2 := r.mul(p1.x, hh2)
+
+	// r = r0 y, so r^2 = r0^2 (x^3 + ax + b)
+	x3 := r.sub(r.sub(r.mul(r.mul(rr, rr), r.curve), hh3), r.add(x1hh2, x1hh2))
+	y3 := r.sub(r.mul(rr, r.sub(x1hh2, x3)), r.mul(p1.y, hh3))
+
+	return jacobianPoint{x: x3, y: y3, z: r.mul(p1.z, hh)}
+}
+
+// addPoints returns p1 + p2, or a non-trivial factor of h if the sum is not the
+// same kind of point for every root of h.
+func (r schoofRing) addPoints(p1, p2 ringPoint) (ringPoint, poly) {
+	if p1.inf {
+		return p2, nil
+	}
+
+	if p2.inf {
+		return p1, nil
+	}
+
+	var lambda poly
+	dx := r.sub(p2.x, p1.x)
+	if dx.isZero() {
+		dy := r.sub(p2.y, p1.y)
+		if !dy.isZero() {
+			if r.add(p2.y, p1.y).isZero() {
+				return ringPoint{inf: true}, nil
+			}
+
+			// p2 = p1 for some roots of h and p2 = -p1 for the others
+			return ringPoint{}, r.gcd(dy, r.h)
+		}
+
+		// λ = (3x^2 + a) / 2y = (3x^2 + a) y / (2 y0 (x^3 + ax + b))
+		num := r.add(r.scale(r.mul(p1.x, p1.x), big.NewInt(3)), r.a)
+		inv, g := r.inverse(r.scale(r.mul(p1.y, r.curve), bi2))
+		if inv == nil {
+			return ringPoint{}, g
+		}
+		lambda = r.mul(num, inv)
+	} else {
+		inv, g := r.inverse(dx)
+		if inv == nil {
+			return ringPoint{}, g
+		}
+		lambda = r.mul(r.sub(p2.y, p1.y), inv)
+	}
+
+	// λ = λ0 y, so λ^2 = λ0^2 (x^3 + ax + b)
+	x3 := r.sub(r.sub(r.mul(r.mul(lambda, lambda), r.curve), p1.x), p2.x)
+	y3 := r.sub(r.mul(lambda, r.sub(p1.x, x3)), p1.y)
+
+	return ringPoint{x: x3, y: y3}, nil
+}
+
+func (r schoofRing) scalarMul(pt ringPoint, k int64) (ringPoint, poly) {
+	result := ringPoint{inf: true}
+	for i := 62; i >= 0; i-- {
+		var factor poly
+		if result, factor = r.addPoints(result, result); factor != nil {
+			return ringPoint{}, factor
+		}
+
+		if k>>i&1 == 1 {
+			if result, factor = r.addPoints(result, pt); factor != nil {
+				return ringPoint{}, factor
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// divisionPolynomial returns the monic l-th division polynomial ψ_l for an
+// odd l, whose roots are the x-coordinates of the points of order l.
+func (ec EllipticCurve) divisionPolynomial(f polyField, l int64) poly {
+	a, b := ec.a.n, ec.b.n
+	a2 := new(big.Int).Mul(a, a)
+	a3 := new(big.Int).Mul(a2, a)
+
+	// g_n = ψ_n for odd n and ψ_n / 2y for even n, so that g_n is in F_p[x]
+	g := make([]poly, max(l+1, 5))
+	g[0] = poly{}
+	g[1] = f.constant(bi1)
+	g[2] = f.constant(bi1)
+	g[3] = f.newPoly(new(big.Int).Neg(a2), new(big.Int).Mul(b, big.NewInt(12)), new(big.Int).Mul(a, big.NewInt(6)), bi0, big.NewInt(3))
+	g[4] = f.scale(f.newPoly(
+		new(big.Int).Sub(new(big.Int).Mul(new(big.Int).Mul(b, b), big.NewInt(-8)), a3),
+		new(big.Int).Mul(new(big.Int).Mul(a, b), big.NewInt(-4)),
+		new(big.Int).Mul(a2, big.NewInt(-5)),
+		new(big.Int).Mul(b, big.NewInt(20)),
+		new(big.Int).Mul(a, big.NewInt(5)),
+		bi0,
+		bi1,
+	), bi2)
+
+	// (2y)^4 = 16 (x^3 + ax + b)^2
+	curve := ec.polynomial(f)
+	y4 := f.scale(f.mul(curve, curve), big.NewInt(16))
+
+	cube := func(p poly) poly { return f.mul(f.mul(p, p), p) }
+	for n := int64(5); n <= l; n++ {
+		m := n / 2
+		if n%2 == 1 {
+			// ψ_2m+1 = ψ_m+2 ψ_m^3 - ψ_m-1 ψ_m+1^3
+			t1 := f.mul(g[m+2], cube(g[m]))
+			t2 := f.mul(g[m-1], cube(g[m+1]))
+			if m%2 == 0 {
+				t1 = f.mul(t1, y4)
+			} else {
+				t2 = f.mul(t2, y4)
+			}
+			g[n] = f.sub(t1, t2)
+		} else {
+			// ψ_2m = ψ_m (ψ_m+2 ψ_m-1^2 - ψ_m-2 ψ_m+1^2) / 2y
+			t1 := f.mul(g[m+2], f.mul(g[m-1], g[m-1]))
+			t2 := f.mul(g[m-2], f.mul(g[m+1], g[m+1]))
+			g[n] = f.mul(g[m], f.sub(t1, t2))
+		}
+	}
+
+	return f.monic(g[l])
+}
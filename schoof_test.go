@@ -0,0 +1,177 @@
+package becc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestEllipticCurveOrder(t *testing.T) {
+	tt := []struct {
+		a, b, p  int64
+		expected int64
+	}{
+		// counted point by point
+		{a: 1, b: 6, p: 97, expected: 116},
+		{a: 1, b: 7, p: 853, expected: 853},
+		{a: 2, b: 20, p: 10007, expected: 10084},
+		// Schoof, checked against the baby-step giant-step count of the toy
+		// package and against counting point by point
+		{a: 1, b: 6, p: 1000003, expected: 999505},
+		{a: 5, b: 0, p: 1000003, expected: 1000004},
+		{a: 3, b: 7, p: 2147483647, expected: 2147552868},
+		{a: -3, b: 5, p: 2147483629, expected: 2147510261},
+		{a: 0, b: 7, p: 1000000007, expected: 1000000008},
+		// complex multiplication, checked against counting point by point
+		{a: 0, b: 5, p: 1000003, expected: 999007},
+		{a: 7, b: 0, p: 1000033, expected: 1000850},
+		{a: 1, b: 0, p: 1000003, expected: 1000004},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("y^2 = x^3 + %dx + %d mod %d", tc.a, tc.b, tc.p), func(t *testing.T) {
+			ec, err := NewEllipticCurve(big.NewInt(tc.a), big.NewInt(tc.b), big.NewInt(tc.p))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := ec.Order()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Cmp(big.NewInt(tc.expected)) != 0 {
+				t.Errorf("got %s, expected %d", got, tc.expected)
+			}
+		})
+	}
+
+	t.Run("composite modulus", func(t *testing.T) {
+		ec, err := NewEllipticCurve(big.NewInt(2), big.NewInt(3), big.NewInt(65537*3))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := ec.Order(); !errors.Is(err, ErrInvalidParameters) {
+			t.Errorf("got %v, expected ErrInvalidParameters", err)
+		}
+	})
+}
+
+func TestOrderBuiltInCurves(t *testing.T) {
+	// SEA takes tens of minutes for the fields of 384 bits and above: their
+	// traces modulo a few Elkies primes are checked by
+	// TestTraceModElkiesBuiltInCurves
+	for _, c := range Curves() {
+		ecc := c.ECC()
+		ec := ecc.Curve()
+		if ec.A().Sign() != 0 && ec.B().Sign() != 0 && ec.P().BitLen() > 256 {
+			continue
+		}
+
+		t.Run(c.Name, func(t *testing.T) {
+			got, err := ec.Order()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if expected := new(big.Int).Mul(ecc.Order(), ecc.Cofactor()); got.Cmp(expected) != 0 {
+				t.Errorf("got %x, expected %x", got, expected)
+			}
+		})
+	}
+}
+
+func TestTraceModBuiltInCurves(t *testing.T) {
+	for _, c := range Curves() {
+		t.Run(c.Name, func(t *testing.T) {
+			ecc := c.ECC()
+			ec := ecc.Curve()
+
+			// t = p + 1 - h·n
+			trace := new(big.Int).Add(ec.P(), bi1)
+			trace.Sub(trace, new(big.Int).Mul(ecc.Order(), ecc.Cofactor()))
+
+			if got, expected := ec.traceMod2(), int64(trace.Bit(0)); got != expected {
+				t.Errorf("t mod 2: got %d, expected %d", got, expected)
+			}
+
+			for _, l := range []int64{3, 5, 7} {
+				expected := new(big.Int).Mod(trace, big.NewInt(l)).Int64()
+				got, err := ec.traceMod(l)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if got != expected {
+					t.Errorf("t mod %d: got %d, expected %d", l, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestDivisionPolynomial(t *testing.T) {
+	ec, err := NewEllipticCurve(big.NewInt(1), big.NewInt(6), big.NewInt(97))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := polyField{p: ec.m}
+	for _, l := range []int64{3, 5, 7, 29} {
+		psi := ec.divisionPolynomial(f, l)
+
+		if psi.degree() != int(l*l-1)/2 {
+			t.Errorf("ψ_%d: got degree %d, expected %d", l, psi.degree(), (l*l-1)/2)
+		}
+
+		// x is a root of ψ_l exactly when the points with that x have order l
+		for x := range int64(97) {
+			ys := ec.Y(big.NewInt(x))
+			if len(ys) == 0 {
+				continue
+			}
+
+			p := ec.NewPoint(big.NewInt(x), ys[0])
+			isRoot := f.evaluate(psi, big.NewInt(x)).Sign() == 0
+			if hasOrderL := p.ScalarMul(big.NewInt(l)).IsInfinity(); isRoot != hasOrderL {
+				t.Errorf("ψ_%d(%d) = 0 is %v, but %d·P = ∞ is %v", l, x, isRoot, l, hasOrderL)
+			}
+		}
+	}
+}
+
+func TestPolyQuotientRing(t *testing.T) {
+	p, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	f := polyField{p: p}
+	rnd := rand.New(rand.NewSource(1))
+
+	randomPoly := func(n int) poly {
+		c := make([]*big.Int, n)
+		for i := range c {
+			c[i] = new(big.Int).Rand(rnd, p)
+		}
+		return f.newPoly(c...)
+	}
+
+	for _, d := range []int{1, 4, 17, 60} {
+		ring := newPolyQuotientRing(f, randomPoly(d+1))
+		a, b := randomPoly(d), randomPoly(d)
+
+		_, expected := f.divMod(f.mul(a, b), ring.h)
+		if got := ring.mul(a, b); !got.equal(expected) {
+			t.Errorf("degree %d: reduction does not match long division", d)
+		}
+
+		inv, g := ring.inverse(a)
+		if !g.isOne() {
+			t.Fatalf("degree %d: not invertible", d)
+		}
+
+		if !ring.mul(a, inv).isOne() {
+			t.Errorf("degree %d: a·a^-1 != 1", d)
+		}
+	}
+}
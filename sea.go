@@ -0,0 +1,335 @@
+package becc
+
+import (
+	"math/big"
+)
+
+// modularPolynomials holds the canonical modular polynomials modulo p computed
+// so far, which are shared by every curve over F_p.
+type modularPolynomials struct {
+	f           polyField
+	polynomials map[int64]modularPolynomial
+}
+
+func newModularPolynomials(p *big.Int) *modularPolynomials {
+	return &modularPolynomials{f: polyField{p: p}, polynomials: map[int64]modularPolynomial{}}
+}
+
+func (m *modularPolynomials) get(l int64) modularPolynomial {
+	phi, ok := m.polynomials[l]
+	if !ok {
+		phi = canonicalModularPolynomial(m.f, l)
+		m.polynomials[l] = phi
+	}
+
+	return phi
+}
+
+// traceModElkies returns t mod l for an Elkies prime l, one for which
+// Φ_l(X, j) has a root in F_p. The root gives the curve E' of an isogeny of
+// degree l defined over F_p, whose kernel is a subgroup C of order l of the
+// l-torsion that the Frobenius π maps to itself, so π(P) = λP on C and
+// t = λ + p/λ mod l. The polynomial with the x-coordinates of C as roots has
+// degree (l - 1)/2 instead of the (l^2 - 1)/2 of the division polynomial
+// used by Schoof's algorithm.
+//
+// It returns false for Atkin primes, when Φ_l(X, j) has no root, and in the
+// rare cases where the formulas for E' break down.
+func (ec EllipticCurve) traceModElkies(phi modularPolynomial) (int64, bool) {
+	f := polyField{p: ec.m}
+	j := ec.jInvariant()
+
+	// the roots of Φ_l(X, j) in F_p are those of gcd(X^p - X, Φ_l(X, j))
+	phiJ := phi.atJ(f, j)
+	ring := newPolyQuotientRing(f, phiJ)
+	x := f.newPoly(bi0, bi1)
+	roots := f.gcd(f.sub(ring.exp(x, f.p), x), phiJ)
+	if roots.degree() < 1 {
+		return 0, false
+	}
+
+	h := ec.kernelPolynomial(phi, j, f.root(roots))
+	if h == nil {
+		return 0, false
+	}
+
+	lambda, ok := ec.eigenvalue(f, h, phi.l)
+	if !ok {
+		return 0, false
+	}
+
+	// t = λ + p/λ mod l
+	bl := big.NewInt(phi.l)
+	t := new(big.Int).ModInverse(big.NewInt(lambda), bl)
+	t.Mul(t, ec.m).Add(t, big.NewInt(lambda))
+
+	return t.Mod(t, bl).Int64(), true
+}
+
+// jInvariant returns j = 1728 · 4a^3 / (4a^3 + 27b^2).
+func (ec EllipticCurve) jInvariant() *big.Int {
+	f := polyField{p: ec.m}
+	a3 := new(big.Int).Exp(ec.a.n, big.NewInt(3), ec.m)
+	a3.Lsh(a3, 2)
+	d := new(big.Int).Mul(ec.b.n, ec.b.n)
+	d.Mul(d, bi27).Add(d, a3)
+
+	return f.product(big.NewInt(1728), a3, f.inverse(d))
+}
+
+// kernelPolynomial returns the monic polynomial of degree (l - 1)/2 whose
+// roots are the x-coordinates of the kernel of the isogeny given by the root
+// g of Φ_l(X, j), or nil when the formulas break down.
+//
+// With D = q d/dq and the normalization E4 = -a/3, E6 = -b/2 of the
+// Eisenstein series, Dj = -j E6/E4, and the derivatives of Φ_l(f, j) = 0
+// give those of f at τ. They give E4(lτ) and E6(lτ), hence the isogenous
+// curve E': y^2 = x^3 + a'x + b' with a' = -3 l^4 E4(lτ) and
+// b' = -2 l^6 E6(lτ), and the sum p1 of the x-coordinates of half the kernel
+// (Müller's thesis, chapter 6). The power sums of the x-coordinates follow
+// from comparing the expansions of the Weierstrass ℘ functions of E and E',
+// and Newton's identities give the polynomial.
+func (ec EllipticCurve) kernelPolynomial(phi modularPolynomial, j, g *big.Int) poly {
+	f := polyField{p: ec.m}
+	l, s := big.NewInt(phi.l), big.NewInt(phi.s)
+	inv := f.inverse
+
+	e4 := f.product(ec.a.n, inv(big.NewInt(-3)))
+	e6 := f.product(ec.b.n, inv(big.NewInt(-2)))
+	delta := f.product(f.sum(f.product(e4, e4, e4), f.product(big.NewInt(-1), e6, e6)), inv(big.NewInt(1728)))
+	dj := f.product(big.NewInt(-1), j, e6, inv(e4))
+
+	phiX := phi.partial(f, g, j, 1, 0)
+	phiJ := phi.partial(f, g, j, 0, 1)
+	if phiX.Sign() == 0 || phiJ.Sign() == 0 {
+		return nil
+	}
+
+	// Df = -Φ_J Dj / Φ_X and F = Df/f
+	df := f.product(big.NewInt(-1), phiJ, dj, inv(phiX))
+	ff := f.product(df, inv(g))
+
+	// D^2 f without the terms of E2, which cancel out
+	d2j := f.product(j, f.sum(
+		f.product(big.NewInt(2), e6, e6, inv(f.product(big.NewInt(3), e4, e4))),
+		f.product(e4, inv(big.NewInt(2))),
+	))
+	d2f := f.product(big.NewInt(-1), inv(phiX), f.sum(
+		f.product(phi.partial(f, g, j, 2, 0), df, df),
+		f.product(big.NewInt(2), phi.partial(f, g, j, 1, 1), df, dj),
+		f.product(phi.partial(f, g, j, 0, 2), dj, dj),
+		f.product(phiJ, d2j),
+	))
+
+	// l^2 E4(lτ) = E4 + 144 F^2 (1/s^2 + 1/s) - 144 D^2f / (s f)
+	sInv := inv(s)
+	e4l := f.product(inv(f.product(l, l)), f.sum(
+		e4,
+		f.product(big.NewInt(144), ff, ff, f.sum(f.product(sInv, sInv), sInv)),
+		f.product(big.NewInt(-144), d2f, sInv, inv(g)),
+	))
+
+	// Δ(lτ) = f^(12/s) Δ / l^12, and j(lτ) = E4(lτ)^3 / Δ(lτ)
+	deltaL := f.product(new(big.Int).Exp(g, big.NewInt(12/phi.s), f.p), delta, inv(new(big.Int).Exp(l, big.NewInt(12), f.p)))
+	jl := f.product(e4l, e4l, e4l, inv(deltaL))
+	if jl.Sign() == 0 {
+		return nil
+	}
+
+	// l^s/f is the root of Φ_l(X, j(lτ)) at lτ, with D(l^s/f) = -(l^s/f) F,
+	// which gives D(j(lτ)) = -l j(lτ) E6(lτ)/E4(lτ)
+	gl := f.product(new(big.Int).Exp(l, s, f.p), inv(g))
+	phiJl := phi.partial(f, gl, jl, 0, 1)
+	if phiJl.Sign() == 0 {
+		return nil
+	}
+	djl := f.product(phi.partial(f, gl, jl, 1, 0), gl, ff, inv(phiJl))
+	e6l := f.product(big.NewInt(-1), djl, e4l, inv(f.product(l, jl)))
+
+	aL := f.product(big.NewInt(-3), new(big.Int).Exp(l, big.NewInt(4), f.p), e4l)
+	bL := f.product(big.NewInt(-2), new(big.Int).Exp(l, big.NewInt(6), f.p), e6l)
+
+	// p1 = 6 l F / s
+	p1 := f.product(big.NewInt(6), l, ff, sInv)
+
+	return ec.kernelFromIsogenous(f, int((phi.l-1)/2), aL, bL, p1)
+}
+
+// kernelFromIsogenous returns the kernel polynomial of degree d of the
+// isogeny to y^2 = x^3 + aL x + bL whose kernel has p1 as the sum of the
+// x-coordinates of half its points. With ℘(z) = 1/z^2 + Σ c_k z^(2k) on E
+// and E', and the polynomials Q_0 = x and
+// Q_(n+1) = (4x^3 + 4ax + 4b) d^2Q_n/dx^2 + (6x^2 + 2a) dQ_n/dx, the kernel
+// points satisfy
+//
+//	Σ Q_n(x_i) = (2n)! (c'_n - c_n) / 2
+//
+// for n >= 1, which gives the power sums of the x_i one after the other.
+func (ec EllipticCurve) kernelFromIsogenous(f polyField, d int, aL, bL, p1 *big.Int) poly {
+	c := weierstrassCoefficients(f, ec.a.n, ec.b.n, d)
+	cL := weierstrassCoefficients(f, aL, bL, d)
+
+	powerSums := []*big.Int{big.NewInt(int64(d)), p1}
+
+	// 4x^3 + 4ax + 4b and 6x^2 + 2a
+	cubic := f.scale(ec.polynomial(f), bi4)
+	quadratic := f.newPoly(new(big.Int).Lsh(ec.a.n, 1), bi0, big.NewInt(6))
+
+	q := f.newPoly(bi0, bi1)
+	factorial := big.NewInt(1)
+	for n := 1; n < d; n++ {
+		q = f.add(f.mul(cubic, f.derivative(q, 2)), f.mul(quadratic, f.derivative(q, 1)))
+		factorial.Mul(factorial, big.NewInt(int64((2*n-1)*2*n))).Mod(factorial, f.p)
+
+		sum := f.product(factorial, f.sum(cL[n], new(big.Int).Neg(c[n])), f.inverse(bi2))
+		for k := 0; k <= n; k++ {
+			sum.Sub(sum, new(big.Int).Mul(q[k], powerSums[k]))
+		}
+
+		powerSums = append(powerSums, f.product(sum, f.inverse(q[n+1])))
+	}
+
+	// Newton's identities: r e_r = Σ (-1)^(i-1) e_(r-i) s_i
+	e := []*big.Int{big.NewInt(1)}
+	for r := 1; r <= d; r++ {
+		sum := new(big.Int)
+		for i := 1; i <= r; i++ {
+			term := new(big.Int).Mul(e[r-i], powerSums[i])
+			if i%2 == 0 {
+				term.Neg(term)
+			}
+			sum.Add(sum, term)
+		}
+
+		e = append(e, f.product(sum, f.inverse(big.NewInt(int64(r)))))
+	}
+
+	h := make([]*big.Int, d+1)
+	for r, er := range e {
+		if r%2 == 1 {
+			er = new(big.Int).Neg(er)
+		}
+		h[d-r] = er
+	}
+
+	return f.newPoly(h...)
+}
+
+// weierstrassCoefficients returns c_1, ..., c_n of ℘(z) = 1/z^2 + Σ c_k z^(2k)
+// for y^2 = x^3 + ax + b, at the same index: c_1 = -a/5, c_2 = -b/7 and
+// c_k = 3 / ((k - 2)(2k + 3)) Σ c_h c_(k-1-h) for 1 <= h <= k - 2.
+func weierstrassCoefficients(f polyField, a, b *big.Int, n int) []*big.Int {
+	c := []*big.Int{nil, f.product(a, f.inverse(big.NewInt(-5))), f.product(b, f.inverse(big.NewInt(-7)))}
+	for k := 3; k <= n; k++ {
+		sum := new(big.Int)
+		for h := 1; h <= k-2; h++ {
+			sum.Add(sum, new(big.Int).Mul(c[h], c[k-1-h]))
+		}
+
+		c = append(c, f.product(big.NewInt(3), sum, f.inverse(big.NewInt(int64((k-2)*(2*k+3))))))
+	}
+
+	return c
+}
+
+// eigenvalue returns the λ with π(P) = λP for the points P with x a root of
+// h, found by comparing π(P) with P, 2P, 3P, ... like traceModFactor does.
+// It returns false if no λ is found, which means that the kernel polynomial
+// is wrong.
+func (ec EllipticCurve) eigenvalue(f polyField, h poly, l int64) (int64, bool) {
+	ring := ec.newSchoofRing(f, h)
+
+	x := ring.reduce(f.newPoly(bi0, bi1))
+	halfP := new(big.Int).Rsh(f.p, 1)
+	frob := ringPoint{x: ring.exp(x, f.p), y: ring.exp(ring.curve, halfP)}
+	pt := ringPoint{x: x, y: f.constant(bi1)}
+
+	lambdaP := jacobianPoint{x: pt.x, y: pt.y, z: f.constant(bi1)}
+	for i := int64(1); i <= (l-1)/2; i++ {
+		switch i {
+		case 1:
+		case 2:
+			double, factor := ring.addPoints(pt, pt)
+			if factor != nil {
+				return 0, false
+			}
+			lambdaP = jacobianPoint{x: double.x, y: double.y, z: f.constant(bi1)}
+		default:
+			lambdaP = ring.addJacobian(lambdaP, pt)
+		}
+
+		z2 := ring.mul(lambdaP.z, lambdaP.z)
+		if !ring.sub(lambdaP.x, ring.mul(frob.x, z2)).isZero() {
+			continue
+		}
+
+		fy := ring.mul(frob.y, ring.mul(z2, lambdaP.z))
+		switch {
+		case ring.sub(lambdaP.y, fy).isZero():
+			return i, true
+		case ring.add(lambdaP.y, fy).isZero():
+			return l - i, true
+		default:
+			return 0, false
+		}
+	}
+
+	return 0, false
+}
+
+// root returns a root in F_p of a, a product of distinct linear factors. It
+// splits a with gcd((x + δ)^((p-1)/2) - 1, a) for δ = 0, 1, ..., which
+// separates the roots r with r + δ a square from the others, until a single
+// factor is left.
+func (f polyField) root(a poly) *big.Int {
+	a = f.monic(a)
+	half := new(big.Int).Rsh(f.p, 1)
+
+	for delta := int64(0); a.degree() > 1; delta++ {
+		ring := newPolyQuotientRing(f, a)
+		power := ring.exp(f.newPoly(big.NewInt(delta), bi1), half)
+
+		d := f.gcd(f.sub(power, f.constant(bi1)), a)
+		if d.degree() < 1 || d.degree() == a.degree() {
+			continue
+		}
+
+		if 2*d.degree() > a.degree() {
+			d, _ = f.divMod(a, d)
+		}
+		a = f.monic(d)
+	}
+
+	return new(big.Int).Sub(f.p, a[0])
+}
+
+// product returns the product of the factors modulo p.
+func (f polyField) product(factors ...*big.Int) *big.Int {
+	r := big.NewInt(1)
+	for _, x := range factors {
+		r.Mul(r, x).Mod(r, f.p)
+	}
+
+	return r
+}
+
+// sum returns the sum of the terms modulo p.
+func (f polyField) sum(terms ...*big.Int) *big.Int {
+	r := new(big.Int)
+	for _, x := range terms {
+		r.Add(r, x)
+	}
+
+	return r.Mod(r, f.p)
+}
+
+// inverse returns 1/x mod p, or 0 when x = 0 mod p so that the formulas
+// using it give 0 instead of failing.
+func (f polyField) inverse(x *big.Int) *big.Int {
+	r := new(big.Int).Mod(x, f.p)
+	if r.Sign() == 0 {
+		return r
+	}
+
+	return r.ModInverse(r, f.p)
+}
@@ -0,0 +1,59 @@
+package becc
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestTraceModElkiesBuiltInCurves(t *testing.T) {
+	for _, c := range Curves() {
+		ecc := c.ECC()
+		ec := ecc.Curve()
+		if ec.A().Sign() == 0 || ec.B().Sign() == 0 {
+			continue
+		}
+
+		t.Run(c.Name, func(t *testing.T) {
+			// t = p + 1 - h·n
+			trace := new(big.Int).Add(ec.P(), bi1)
+			trace.Sub(trace, new(big.Int).Mul(ecc.Order(), ecc.Cofactor()))
+
+			modular := newModularPolynomials(ec.P())
+			elkiesPrimes := 0
+			for _, l := range []int64{11, 13, 17, 19, 23} {
+				got, ok := ec.traceModElkies(modular.get(l))
+				if !ok {
+					continue
+				}
+				elkiesPrimes++
+
+				if expected := new(big.Int).Mod(trace, big.NewInt(l)).Int64(); got != expected {
+					t.Errorf("t mod %d: got %d, expected %d", l, got, expected)
+				}
+			}
+
+			if elkiesPrimes == 0 {
+				t.Errorf("no Elkies prime")
+			}
+		})
+	}
+}
+
+func TestPolyFieldRoot(t *testing.T) {
+	f := polyField{p: big.NewInt(1000003)}
+
+	for _, roots := range [][]int64{{5}, {0, 1}, {3, 999999, 12345, 777}} {
+		t.Run(fmt.Sprint(roots), func(t *testing.T) {
+			a := f.constant(bi1)
+			for _, r := range roots {
+				a = f.mul(a, f.newPoly(big.NewInt(-r), bi1))
+			}
+
+			got := f.root(a)
+			if f.evaluate(a, got).Sign() != 0 {
+				t.Errorf("got %s, which is not a root", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+// Package shamir implements Shamir secret sharing of private keys over the
+// order n of the curve, with Feldman verifiable shares.
+//
+// Split hides the private key d in the constant term of a random polynomial
+// f of degree t - 1 and returns the shares (i, f(i)) for i = 1..count: any t
+// of them recover d with Lagrange interpolation, and fewer reveal nothing
+// about it. The commitments C_j = a_j·G to the coefficients of f let every
+// holder check its share, f(i)·G = Σ i^j·C_j, and C_0 is the public key.
+//
+// Polynomial, Commitments and LagrangeCoefficient are the building blocks of
+// Split and Combine, for protocols that share secrets among participants
+// identified by arbitrary scalars, such as the key generation of FROST.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/artilugio0/becc"
+)
+
+// MaxShares is the largest number of shares, as the index of a share is
+// encoded in one byte.
+const MaxShares int = 255
+
+var (
+	ErrInvalidShare       error = errors.New("invalid share")
+	ErrNotEnoughShares    error = errors.New("not enough shares")
+	ErrInvalidCommitments error = errors.New("invalid commitments")
+)
+
+// Share is the value f(i) of the polynomial of a split key at the index i,
+// with the threshold t of the split.
+type Share struct {
+	ecc       *becc.ECC
+	index     int
+	value     *big.Int
+	threshold int
+}
+
+// NewShare returns the share f(index) = value of a key split with the
+// threshold.
+func NewShare(ecc *becc.ECC, index int, value *big.Int, threshold int) (Share, error) {
+	if index < 1 || index > MaxShares || big.NewInt(int64(index)).Cmp(ecc.Order()) >= 0 {
+		return Share{}, fmt.Errorf("%w: the index must be between 1 and %d, and less than n", ErrInvalidShare, MaxShares)
+	}
+
+	if threshold < 1 || threshold > MaxShares {
+		return Share{}, fmt.Errorf("%w: the threshold must be between 1 and %d", ErrInvalidShare, MaxShares)
+	}
+
+	if value.Sign() < 0 || value.Cmp(ecc.Order()) >= 0 {
+		return Share{}, fmt.Errorf("%w: value out of range", ErrInvalidShare)
+	}
+
+	return Share{ecc: ecc, index: index, value: new(big.Int).Set(value), threshold: threshold}, nil
+}
+
+// ParseShare parses a share encoded by Share.Bytes for the curve ecc.
+func ParseShare(ecc *becc.ECC, b []byte) (Share, error) {
+	if len(b) != 2+ecc.ScalarSize() {
+		return Share{}, fmt.Errorf("%w: invalid length", ErrInvalidShare)
+	}
+
+	return NewShare(ecc, int(b[0]), new(big.Int).SetBytes(b[2:]), int(b[1]))
+}
+
+func (s Share) ECC() *becc.ECC {
+	return s.ecc
+}
+
+func (s Share) Index() int {
+	return s.index
+}
+
+func (s Share) Value() *big.Int {
+	return new(big.Int).Set(s.value)
+}
+
+func (s Share) Threshold() int {
+	return s.threshold
+}
+
+// Bytes returns the encoding i || t || f(i) of the share: the index and the
+// threshold in one byte each, and the value as a fixed-length big-endian byte
+// string of ECC.ScalarSize bytes.
+func (s Share) Bytes() []byte {
+	b := make([]byte, 2+s.ecc.ScalarSize())
+	b[0] = byte(s.index)
+	b[1] = byte(s.threshold)
+	s.value.FillBytes(b[2:])
+
+	return b
+}
+
+// Commitments are the Feldman commitments C_j = a_j·G to the coefficients of
+// the polynomial of a split key. C_0 is the public key.
+type Commitments struct {
+	ecc    *becc.ECC
+	points []becc.Point
+}
+
+// ParseCommitments parses commitments encoded by Commitments.Bytes for the
+// curve ecc.
+func ParseCommitments(ecc *becc.ECC, b []byte) (Commitments, error) {
+	size := ecc.CoordinateSize() + 1
+	if len(b) == 0 || len(b)%size != 0 || len(b)/size > MaxShares {
+		return Commitments{}, fmt.Errorf("%w: invalid length", ErrInvalidCommitments)
+	}
+
+	points := make([]becc.Point, len(b)/size)
+	for i := range points {
+		pub, err := ecc.NewPublicKeyCompressed(b[i*size : (i+1)*size])
+		if err != nil {
+			return Commitments{}, fmt.Errorf("%w: %w", ErrInvalidCommitments, err)
+		}
+
+		points[i] = pub.Point()
+	}
+
+	return Commitments{ecc: ecc, points: points}, nil
+}
+
+// Points returns the commitments C_0, ..., C_{t-1}.
+func (c Commitments) Points() []becc.Point {
+	return append([]becc.Point(nil), c.points...)
+}
+
+// Threshold returns the number of shares t needed to recover the key.
+func (c Commitments) Threshold() int {
+	return len(c.points)
+}
+
+// PublicKey returns the public key of the split key, C_0.
+func (c Commitments) PublicKey() (becc.PublicKey, error) {
+	return c.ecc.NewPublicKey(c.points[0])
+}
+
+// Bytes returns the concatenation of the compressed commitments.
+func (c Commitments) Bytes() []byte {
+	size := c.ecc.CoordinateSize() + 1
+
+	b := make([]byte, len(c.points)*size)
+	for i, p := range c.points {
+		b[i*size] = byte(2 + p.Y().Bit(0))
+		p.X().FillBytes(b[i*size+1 : (i+1)*size])
+	}
+
+	return b
+}
+
+// Evaluate returns f(x)·G = Σ x^j·C_j, the public key of the share of x.
+func (c Commitments) Evaluate(x *big.Int) becc.Point {
+	// Horner's method
+	result := c.points[len(c.points)-1]
+	for j := len(c.points) - 2; j >= 0; j-- {
+		result = result.ScalarMul(x).Add(c.points[j])
+	}
+
+	return result
+}
+
+// VerifyValue reports whether value is f(x) for the polynomial f committed
+// to: value·G = Σ x^j·C_j.
+func (c Commitments) VerifyValue(x, value *big.Int) bool {
+	return c.ecc.Generator().ScalarMul(value).Eq(c.Evaluate(x))
+}
+
+// Add returns the commitments to the sum of the polynomials of c and other,
+// which must have the same threshold.
+func (c Commitments) Add(other Commitments) (Commitments, error) {
+	if !other.ecc.Equal(c.ecc) || len(other.points) != len(c.points) {
+		return Commitments{}, fmt.Errorf("%w: the commitments are for different curves or thresholds", ErrInvalidCommitments)
+	}
+
+	points := make([]becc.Point, len(c.points))
+	for j := range points {
+		points[j] = c.points[j].Add(other.points[j])
+	}
+
+	return Commitments{ecc: c.ecc, points: points}, nil
+}
+
+// Verify reports whether share is a share of the private key of pub:
+// C_0 = pub and f(i)·G = Σ i^j·C_j.
+func (c Commitments) Verify(pub becc.PublicKey, share Share) bool {
+	if !share.ecc.Equal(c.ecc) || !pub.ECC().Equal(c.ecc) {
+		return false
+	}
+
+	if share.threshold != len(c.points) || !c.points[0].Eq(pub.Point()) {
+		return false
+	}
+
+	return c.VerifyValue(big.NewInt(int64(share.index)), share.value)
+}
+
+// Polynomial is a secret polynomial f(x) = a_0 + a_1·x + ... + a_(t-1)·x^(t-1)
+// over the scalars modulo n, whose constant term a_0 is the shared secret.
+type Polynomial struct {
+	ecc          *becc.ECC
+	coefficients []*big.Int
+}
+
+// NewPolynomial returns the polynomial with the given coefficients, from a_0
+// up, which must be scalars in [0, n-1].
+func NewPolynomial(ecc *becc.ECC, coefficients []*big.Int) (Polynomial, error) {
+	if len(coefficients) < 1 || len(coefficients) > MaxShares {
+		return Polynomial{}, fmt.Errorf("%w: a polynomial has between 1 and %d coefficients", becc.ErrInvalidParameters, MaxShares)
+	}
+
+	copied := make([]*big.Int, len(coefficients))
+	for j, a := range coefficients {
+		if a.Sign() < 0 || a.Cmp(ecc.Order()) >= 0 {
+			return Polynomial{}, fmt.Errorf("%w: coefficient out of range", becc.ErrInvalidParameters)
+		}
+
+		copied[j] = new(big.Int).Set(a)
+	}
+
+	return Polynomial{ecc: ecc, coefficients: copied}, nil
+}
+
+// RandomPolynomial returns a polynomial of degree threshold - 1 with the
+// constant term secret and the other coefficients uniformly random in
+// [1, n-1], so that their commitments are never the point at infinity.
+func RandomPolynomial(ecc *becc.ECC, secret *big.Int, threshold int) (Polynomial, error) {
+	coefficients := []*big.Int{secret}
+	for range threshold - 1 {
+		a, err := rand.Int(rand.Reader, new(big.Int).Sub(ecc.Order(), big.NewInt(1)))
+		if err != nil {
+			return Polynomial{}, err
+		}
+
+		coefficients = append(coefficients, a.Add(a, big.NewInt(1)))
+	}
+
+	return NewPolynomial(ecc, coefficients)
+}
+
+// Threshold returns the number of values t of the polynomial needed to
+// recover it.
+func (f Polynomial) Threshold() int {
+	return len(f.coefficients)
+}
+
+// Evaluate returns f(x) mod n.
+func (f Polynomial) Evaluate(x *big.Int) *big.Int {
+	n := f.ecc.Order()
+
+	// Horner's method
+	y := new(big.Int)
+	for j := len(f.coefficients) - 1; j >= 0; j-- {
+		y.Mul(y, x).Add(y, f.coefficients[j]).Mod(y, n)
+	}
+
+	return y
+}
+
+// Commitments returns the Feldman commitments C_j = a_j·G to the
+// coefficients.
+func (f Polynomial) Commitments() Commitments {
+	points := make([]becc.Point, len(f.coefficients))
+	for j, a := range f.coefficients {
+		points[j] = f.ecc.Generator().ScalarMul(a)
+	}
+
+	return Commitments{ecc: f.ecc, points: points}
+}
+
+// Split splits the private key into count shares, any threshold of which
+// recover it, and returns them with the commitments to check them.
+func Split(priv becc.PrivateKey, threshold, count int) ([]Share, Commitments, error) {
+	if threshold < 1 || count < threshold || count > MaxShares {
+		return nil, Commitments{}, fmt.Errorf("%w: the number of shares must satisfy 1 <= threshold <= shares <= %d", becc.ErrInvalidParameters, MaxShares)
+	}
+
+	ecc := priv.ECC()
+	n := ecc.Order()
+
+	if big.NewInt(int64(count)).Cmp(n) >= 0 {
+		return nil, Commitments{}, fmt.Errorf("%w: the number of shares must be less than n", becc.ErrInvalidParameters)
+	}
+
+	f, err := RandomPolynomial(ecc, priv.Int(), threshold)
+	if err != nil {
+		return nil, Commitments{}, err
+	}
+
+	shares := make([]Share, count)
+	for i := range shares {
+		shares[i] = Share{ecc: ecc, index: i + 1, value: f.Evaluate(big.NewInt(int64(i + 1))), threshold: threshold}
+	}
+
+	return shares, f.Commitments(), nil
+}
+
+// Combine recovers the private key from at least threshold shares of it. It
+// cannot detect a corrupted share: check them first with Commitments.Verify.
+func Combine(shares []Share) (becc.PrivateKey, error) {
+	if len(shares) == 0 {
+		return becc.PrivateKey{}, ErrNotEnoughShares
+	}
+
+	ecc := shares[0].ecc
+	threshold := shares[0].threshold
+
+	xs := make([]*big.Int, len(shares))
+	seen := map[int]bool{}
+	for i, s := range shares {
+		if s.ecc == nil || !s.ecc.Equal(ecc) || s.threshold != threshold {
+			return becc.PrivateKey{}, fmt.Errorf("%w: the shares belong to different splits", ErrInvalidShare)
+		}
+
+		if seen[s.index] {
+			return becc.PrivateKey{}, fmt.Errorf("%w: duplicate index %d", ErrInvalidShare, s.index)
+		}
+		seen[s.index] = true
+
+		xs[i] = big.NewInt(int64(s.index))
+	}
+
+	if len(shares) < threshold {
+		return becc.PrivateKey{}, fmt.Errorf("%w: %d shares, the threshold is %d", ErrNotEnoughShares, len(shares), threshold)
+	}
+
+	// d = f(0) = Σ λ_i·f(i)
+	n := ecc.Order()
+	d := new(big.Int)
+	for i, s := range shares {
+		lambda := LagrangeCoefficient(n, xs, xs[i])
+		d.Add(d, lambda.Mul(lambda, s.value)).Mod(d, n)
+	}
+
+	return ecc.NewPrivateKey(d)
+}
+
+// LagrangeCoefficient returns the Lagrange coefficient at 0 of x_i for the
+// distinct points xs, modulo n: Π x_j / (x_j - x_i) for j != i, so that
+// f(0) = Σ λ_i·f(x_i) for any polynomial f of degree less than len(xs).
+func LagrangeCoefficient(n *big.Int, xs []*big.Int, xi *big.Int) *big.Int {
+	num, den := big.NewInt(1), big.NewInt(1)
+	for _, xj := range xs {
+		if xj.Cmp(xi) == 0 {
+			continue
+		}
+
+		num.Mul(num, xj).Mod(num, n)
+		den.Mul(den, new(big.Int).Sub(xj, xi)).Mod(den, n)
+	}
+
+	return num.Mul(num, new(big.Int).ModInverse(den, n)).Mod(num, n)
+}
@@ -0,0 +1,341 @@
+package shamir
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/artilugio0/becc"
+)
+
+func TestSplitCombine(t *testing.T) {
+	tests := []struct {
+		curve     string
+		threshold int
+		count     int
+	}{
+		{curve: "secp256k1", threshold: 3, count: 5},
+		{curve: "secp256r1", threshold: 2, count: 3},
+		{curve: "secp521r1", threshold: 4, count: 4},
+		{curve: "brainpoolP256r1", threshold: 1, count: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.curve, func(t *testing.T) {
+			ecc, err := becc.LookupCurve(tt.curve)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			priv, pub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			shares, commitments, err := Split(priv, tt.threshold, tt.count)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(shares) != tt.count || commitments.Threshold() != tt.threshold {
+				t.Fatalf("got %d shares and threshold %d", len(shares), commitments.Threshold())
+			}
+
+			commitmentsKey, err := commitments.PublicKey()
+			if err != nil || !commitmentsKey.Point().Eq(pub.Point()) {
+				t.Errorf("the first commitment is not the public key: %v", err)
+			}
+
+			for _, share := range shares {
+				if !commitments.Verify(pub, share) {
+					t.Errorf("share %d is not valid", share.Index())
+				}
+			}
+
+			// every window of threshold consecutive shares recovers the key
+			for i := 0; i+tt.threshold <= tt.count; i++ {
+				got, err := Combine(shares[i : i+tt.threshold])
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if got.Int().Cmp(priv.Int()) != 0 {
+					t.Errorf("shares %d to %d: got private key %x", i+1, i+tt.threshold, got.Bytes())
+				}
+			}
+
+			// all the shares also recover the key
+			got, err := Combine(shares)
+			if err != nil || got.Int().Cmp(priv.Int()) != 0 {
+				t.Errorf("all shares: got private key %x, error %v", got.Bytes(), err)
+			}
+
+			if tt.threshold > 1 {
+				if _, err := Combine(shares[:tt.threshold-1]); !errors.Is(err, ErrNotEnoughShares) {
+					t.Errorf("expected error %v, got %v", ErrNotEnoughShares, err)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	ecc := becc.Secp256k1ECC()
+
+	priv, pub, err := ecc.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, commitments, err := Split(priv, 3, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsedCommitments, err := ParseCommitments(ecc, commitments.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed := make([]Share, len(shares))
+	for i, share := range shares {
+		b := share.Bytes()
+		if len(b) != 34 {
+			t.Fatalf("got a share of %d bytes", len(b))
+		}
+
+		if parsed[i], err = ParseShare(ecc, b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if parsed[i].Index() != share.Index() || parsed[i].Threshold() != 3 || parsed[i].Value().Cmp(share.Value()) != 0 {
+			t.Errorf("got share %d, threshold %d, value %x", parsed[i].Index(), parsed[i].Threshold(), parsed[i].Value())
+		}
+
+		if !parsedCommitments.Verify(pub, parsed[i]) {
+			t.Errorf("parsed share %d is not valid", i+1)
+		}
+	}
+
+	invalid := []struct {
+		name string
+		b    []byte
+	}{
+		{name: "short", b: make([]byte, 33)},
+		{name: "zero index", b: append([]byte{0, 3}, make([]byte, 32)...)},
+		{name: "zero threshold", b: append([]byte{1, 0}, make([]byte, 32)...)},
+		{name: "value out of range", b: append([]byte{1, 3}, ecc.Order().FillBytes(make([]byte, 32))...)},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseShare(ecc, tt.b); !errors.Is(err, ErrInvalidShare) {
+				t.Errorf("expected error %v, got %v", ErrInvalidShare, err)
+			}
+		})
+	}
+
+	if _, err := ParseCommitments(ecc, commitments.Bytes()[1:]); !errors.Is(err, ErrInvalidCommitments) {
+		t.Errorf("expected error %v, got %v", ErrInvalidCommitments, err)
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	ecc := becc.Secp256r1ECC()
+
+	priv, pub, err := ecc.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, commitments, err := Split(priv, 2, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, otherPub, err := ecc.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tampered, err := NewShare(ecc, 2, new(big.Int).Add(shares[1].Value(), big.NewInt(1)), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wrongIndex, err := NewShare(ecc, 3, shares[1].Value(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wrongThreshold, err := NewShare(ecc, 2, shares[1].Value(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		pub   becc.PublicKey
+		share Share
+	}{
+		{name: "other public key", pub: otherPub, share: shares[1]},
+		{name: "tampered value", pub: pub, share: tampered},
+		{name: "wrong index", pub: pub, share: wrongIndex},
+		{name: "wrong threshold", pub: pub, share: wrongThreshold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if commitments.Verify(tt.pub, tt.share) {
+				t.Errorf("the share should not be valid")
+			}
+		})
+	}
+
+	// a corrupted share goes undetected by Combine
+	got, err := Combine([]Share{shares[0], tampered})
+	if err == nil && got.Int().Cmp(priv.Int()) == 0 {
+		t.Errorf("a tampered share recovered the private key")
+	}
+
+	if _, err := Combine([]Share{shares[0], shares[0]}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("duplicate shares: expected error %v, got %v", ErrInvalidShare, err)
+	}
+
+	if _, err := Combine([]Share{shares[0], wrongThreshold}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("mixed splits: expected error %v, got %v", ErrInvalidShare, err)
+	}
+}
+
+func TestSplitInvalidParameters(t *testing.T) {
+	priv, _, err := becc.Secp256k1ECC().GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		threshold int
+		count     int
+	}{
+		{threshold: 0, count: 3},
+		{threshold: 4, count: 3},
+		{threshold: 2, count: MaxShares + 1},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := Split(priv, tt.threshold, tt.count); !errors.Is(err, becc.ErrInvalidParameters) {
+			t.Errorf("threshold %d of %d: expected error %v, got %v", tt.threshold, tt.count, becc.ErrInvalidParameters, err)
+		}
+	}
+}
+
+func TestPolynomialSum(t *testing.T) {
+	ecc := becc.Secp256k1ECC()
+
+	f, err := NewPolynomial(ecc, []*big.Int{big.NewInt(7), big.NewInt(3), big.NewInt(5)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g, err := RandomPolynomial(ecc, big.NewInt(11), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// f(2) = 7 + 3·2 + 5·4
+	if got := f.Evaluate(big.NewInt(2)); got.Cmp(big.NewInt(33)) != 0 {
+		t.Errorf("got f(2) = %s, expected 33", got)
+	}
+
+	sum, err := f.Commitments().Add(g.Commitments())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the values of f + g at 10, 20 and 30 verify against the sum of the
+	// commitments and interpolate to f(0) + g(0) = 18
+	xs := []*big.Int{big.NewInt(10), big.NewInt(20), big.NewInt(30)}
+	secret := new(big.Int)
+	for _, x := range xs {
+		y := new(big.Int).Add(f.Evaluate(x), g.Evaluate(x))
+		y.Mod(y, ecc.Order())
+
+		if !sum.VerifyValue(x, y) {
+			t.Errorf("the value at %s does not match the commitments", x)
+		}
+
+		secret.Add(secret, y.Mul(y, LagrangeCoefficient(ecc.Order(), xs, x)))
+	}
+
+	if secret.Mod(secret, ecc.Order()).Cmp(big.NewInt(18)) != 0 {
+		t.Errorf("got secret %s, expected 18", secret)
+	}
+
+	if _, err := f.Commitments().Add(Commitments{ecc: ecc, points: f.Commitments().Points()[:2]}); !errors.Is(err, ErrInvalidCommitments) {
+		t.Errorf("expected error %v, got %v", ErrInvalidCommitments, err)
+	}
+
+	if _, err := NewPolynomial(ecc, []*big.Int{ecc.Order()}); !errors.Is(err, becc.ErrInvalidParameters) {
+		t.Errorf("expected error %v, got %v", becc.ErrInvalidParameters, err)
+	}
+}
+
+func TestCustomCurves(t *testing.T) {
+	// curves loaded from domain parameters have no name
+	custom := func(name string) *becc.ECC {
+		named, err := becc.LookupCurve(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ecc, err := becc.NewECC(named.Curve(), named.Generator(), named.Order(), named.Cofactor())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return ecc
+	}
+
+	p256, brainpool := custom("secp256r1"), custom("brainpoolP256r1")
+
+	priv, pub, err := p256.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, commitments, err := Split(priv, 2, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, err := Combine(shares[:2]); err != nil || got.Int().Cmp(priv.Int()) != 0 {
+		t.Errorf("got private key %x, error %v", got.Bytes(), err)
+	}
+
+	other, err := NewShare(brainpool, shares[1].Index(), new(big.Int).Mod(shares[1].Value(), brainpool.Order()), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if commitments.Verify(pub, other) {
+		t.Errorf("a share of another curve is valid")
+	}
+
+	if _, err := Combine([]Share{shares[0], other}); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("expected error %v, got %v", ErrInvalidShare, err)
+	}
+
+	otherPriv, _, err := brainpool.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, otherCommitments, err := Split(otherPriv, 2, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := commitments.Add(otherCommitments); !errors.Is(err, ErrInvalidCommitments) {
+		t.Errorf("expected error %v, got %v", ErrInvalidCommitments, err)
+	}
+}
@@ -0,0 +1,200 @@
+package toy
+
+import "math"
+
+// Order returns the number of points of the curve, including the point at
+// infinity, with Mestre's baby-step giant-step method: the order of each
+// point P is found among the multiples n with n·P = ∞ inside the Hasse
+// interval [m + 1 - 2√m, m + 1 + 2√m], and the least common multiple of the
+// orders of a few points has a single multiple in the interval, which is the
+// order of the curve. It falls back to counting the points one x-coordinate
+// at a time when the points do not pin the order down, which only happens for
+// very small fields.
+func (ec EllipticCurve) Order() int64 {
+	w := isqrt(4 * ec.m)
+	lo, hi := ec.m+1-w, ec.m+1+w
+
+	l := int64(1)
+	tested := 0
+	for x := int64(0); x < ec.m && tested < 20; x++ {
+		y, ok := ec.y(x)
+		if !ok {
+			continue
+		}
+		tested++
+
+		l = lcm(l, ec.pointOrder(ec.NewPoint(x, y), lo, hi))
+
+		if hi/l-(lo-1)/l == 1 {
+			return hi / l * l
+		}
+	}
+
+	return ec.countPoints()
+}
+
+// pointOrder returns the order of p, using baby-step giant-step to find a
+// multiple n of it in [lo, hi].
+func (ec EllipticCurve) pointOrder(p Point, lo, hi int64) int64 {
+	s := isqrt(hi-lo) + 1
+
+	// baby steps: j·P for 0 <= j < s
+	baby := map[Point]int64{}
+	jP := ec.Infinity()
+	for j := range s {
+		if _, ok := baby[jP]; !ok {
+			baby[jP] = j
+		}
+		jP = jP.Add(p)
+	}
+
+	// giant steps: (lo + i·s)P = -j·P gives n = lo + i·s + j
+	var n int64
+	giant := p.ScalarMul(s)
+	q := p.ScalarMul(lo)
+	for i := int64(0); lo+i*s <= hi; i++ {
+		if j, ok := baby[q.Neg()]; ok {
+			n = lo + i*s + j
+			break
+		}
+		q = q.Add(giant)
+	}
+
+	// remove the prime factors of n that are not needed to reach ∞
+	order := n
+	for _, f := range primeFactors(n) {
+		for order%f == 0 && p.ScalarMul(order/f).IsInfinity() {
+			order /= f
+		}
+	}
+
+	return order
+}
+
+// y returns a square root of x^3 + ax + b, or false if there is none.
+func (ec EllipticCurve) y(x int64) (int64, bool) {
+	x2 := modReduce(x*x, ec.m)
+	rhs := modReduce(modReduce(x2*x, ec.m)+modReduce(ec.a*x, ec.m)+ec.b, ec.m)
+
+	return modSqrt(rhs, ec.m)
+}
+
+// countPoints returns the number of points by adding, for every x, the
+// number of square roots of x^3 + ax + b.
+func (ec EllipticCurve) countPoints() int64 {
+	count := int64(1)
+	for x := int64(0); x < ec.m; x++ {
+		if y, ok := ec.y(x); ok {
+			count++
+			if y != 0 {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// modSqrt returns a square root of n modulo the odd prime m with the
+// Tonelli-Shanks algorithm, or false if n is not a square.
+func modSqrt(n, m int64) (int64, bool) {
+	n = modReduce(n, m)
+	if n == 0 {
+		return 0, true
+	}
+
+	if modExp(n, (m-1)/2, m) != 1 {
+		return 0, false
+	}
+
+	// m - 1 = q·2^s with q odd
+	q, s := m-1, 0
+	for q%2 == 0 {
+		q /= 2
+		s++
+	}
+
+	z := int64(2)
+	for modExp(z, (m-1)/2, m) != m-1 {
+		z++
+	}
+
+	c := modExp(z, q, m)
+	r := modExp(n, (q+1)/2, m)
+	t := modExp(n, q, m)
+	for t != 1 {
+		i, t2 := 0, t
+		for t2 != 1 {
+			t2 = modReduce(t2*t2, m)
+			i++
+		}
+
+		b := c
+		for range s - i - 1 {
+			b = modReduce(b*b, m)
+		}
+
+		r = modReduce(r*b, m)
+		c = modReduce(b*b, m)
+		t = modReduce(t*c, m)
+		s = i
+	}
+
+	return r, true
+}
+
+func modExp(n, e, m int64) int64 {
+	result := int64(1)
+	n = modReduce(n, m)
+	for e > 0 {
+		if e&1 == 1 {
+			result = modReduce(result*n, m)
+		}
+		n = modReduce(n*n, m)
+		e >>= 1
+	}
+
+	return result
+}
+
+func isqrt(n int64) int64 {
+	r := int64(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+
+	return r
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+func lcm(a, b int64) int64 {
+	return a / gcd(a, b) * b
+}
+
+func primeFactors(n int64) []int64 {
+	factors := []int64{}
+	for f := int64(2); f*f <= n; f++ {
+		if n%f == 0 {
+			factors = append(factors, f)
+			for n%f == 0 {
+				n /= f
+			}
+		}
+	}
+
+	if n > 1 {
+		factors = append(factors, n)
+	}
+
+	return factors
+}
@@ -212,3 +212,37 @@ func TestEllipticCurveIsOnCurve(t *testing.T) {
 		})
 	}
 }
+
+func TestEllipticCurveOrder(t *testing.T) {
+	tt := []struct {
+		a, b, m  int64
+		expected int64
+	}{
+		{a: 2, b: 2, m: 17, expected: 19},
+		{a: 1, b: 6, m: 11, expected: 13},
+		{a: 1, b: 6, m: 97, expected: 116},
+		{a: 1, b: 7, m: 853, expected: 853},
+		{a: 2, b: 20, m: 10007, expected: 10084},
+		{a: 1, b: 6, m: 1000003, expected: 999505},
+		{a: 3, b: 7, m: 2147483647, expected: 2147552868},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("y^2 = x^3 + %dx + %d mod %d", tc.a, tc.b, tc.m), func(t *testing.T) {
+			ec, err := NewEllipticCurve(tc.a, tc.b, tc.m)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := ec.Order(); got != tc.expected {
+				t.Errorf("got %d, expected %d", got, tc.expected)
+			}
+
+			if tc.m < 1<<16 {
+				if got := ec.countPoints(); got != tc.expected {
+					t.Errorf("point count: got %d, expected %d", got, tc.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package becc
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+var ErrInvalidVRFProof error = errors.New("invalid VRF proof")
+
+// vrfSuite is an ECVRF ciphersuite of RFC 9381 that encodes to the curve
+// with the try-and-increment method.
+type vrfSuite struct {
+	name        string
+	suiteString byte
+	hash        HashFunc
+	cLen        int
+}
+
+// vrfSuites are the ECVRF suites keyed by the name of their curve.
+//
+// RFC 9381 does not define a suite for secp256k1, and no other specification
+// does. The secp256k1 suite is specific to this package, as its name says: it
+// follows ECVRF-P256-SHA256-TAI with the suite string 0xfe used by earlier
+// secp256k1 implementations of the drafts, but those implement older versions
+// of the challenge, so its proofs do not interoperate with any of them.
+var vrfSuites = map[string]*vrfSuite{
+	"secp256r1": {name: "ECVRF-P256-SHA256-TAI", suiteString: 0x01, hash: SHA256, cLen: 16},
+	"secp256k1": {name: "NONSTANDARD-ECVRF-SECP256K1-SHA256-TAI", suiteString: 0xfe, hash: SHA256, cLen: 16},
+}
+
+// VRFSuiteName returns the name of the ECVRF suite of the curve: an RFC 9381
+// suite, or one starting with NONSTANDARD- that only this package implements.
+func (e *ECC) VRFSuiteName() (string, error) {
+	suite, err := e.vrfSuite()
+	if err != nil {
+		return "", err
+	}
+
+	return suite.name, nil
+}
+
+// VRFProofSize returns the length of the proofs of VRFProve, or 0 if the
+// curve does not have an ECVRF suite.
+func (e *ECC) VRFProofSize() int {
+	suite, err := e.vrfSuite()
+	if err != nil {
+		return 0
+	}
+
+	return e.CoordinateSize() + 1 + suite.cLen + e.ScalarSize()
+}
+
+// VRFProve returns the ECVRF proof pi of RFC 9381 for the input alpha. The
+// output of the VRF, unique for the key and alpha, is VRFProofToHash(pi).
+func (priv PrivateKey) VRFProve(alpha []byte) ([]byte, error) {
+	e := priv.ecc
+
+	suite, err := e.vrfSuite()
+	if err != nil {
+		return nil, err
+	}
+
+	pub := priv.PublicKey()
+
+	h, err := e.vrfEncodeToCurve(suite, pub, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	hString := e.pointToString(h)
+	gamma := h.ScalarMul(priv.d)
+
+	// RFC 6979 nonce for the message hString
+	hm := suite.hash()
+	hm.Write(hString)
+	nonces := newRFC6979(suite.hash, priv.d, e.n, hm.Sum(nil))
+	k := nonces.next()
+	for k.Sign() == 0 || k.Cmp(e.n) >= 0 {
+		k = nonces.next()
+	}
+
+	c := e.vrfChallenge(suite, pub.p, h, gamma, e.g.ScalarMul(k), h.ScalarMul(k))
+
+	s := new(big.Int).Mul(c, priv.d)
+	s.Add(s, k).Mod(s, e.n)
+
+	return slices.Concat(
+		e.pointToString(gamma),
+		c.FillBytes(make([]byte, suite.cLen)),
+		s.FillBytes(make([]byte, e.ScalarSize())),
+	), nil
+}
+
+// VRFVerify checks the ECVRF proof pi for the input alpha and returns the
+// output beta of the VRF. It returns ErrInvalidVRFProof if the proof is not
+// valid.
+func (pub PublicKey) VRFVerify(alpha, pi []byte) ([]byte, error) {
+	e := pub.ecc
+
+	suite, err := e.vrfSuite()
+	if err != nil {
+		return nil, err
+	}
+
+	gamma, c, s, err := e.vrfDecodeProof(suite, pi)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := e.vrfEncodeToCurve(suite, pub, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// U = s·B - c·Y, V = s·H - c·Gamma
+	u := e.g.ScalarMul(s).Add(pub.p.ScalarMul(c).Neg())
+	v := h.ScalarMul(s).Add(gamma.ScalarMul(c).Neg())
+	if u.IsInfinity() || v.IsInfinity() {
+		return nil, ErrInvalidVRFProof
+	}
+
+	expected := e.vrfChallenge(suite, pub.p, h, gamma, u, v)
+	if subtle.ConstantTimeCompare(expected.Bytes(), c.Bytes()) != 1 {
+		return nil, ErrInvalidVRFProof
+	}
+
+	return e.vrfProofToHash(suite, gamma), nil
+}
+
+// VRFProofToHash returns the output beta of the VRF for the proof pi, without
+// verifying it: use it only for proofs checked with VRFVerify or made with
+// VRFProve.
+func (e *ECC) VRFProofToHash(pi []byte) ([]byte, error) {
+	suite, err := e.vrfSuite()
+	if err != nil {
+		return nil, err
+	}
+
+	gamma, _, _, err := e.vrfDecodeProof(suite, pi)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.vrfProofToHash(suite, gamma), nil
+}
+
+func (e *ECC) vrfSuite() (*vrfSuite, error) {
+	suite, ok := vrfSuites[e.name]
+	if !ok {
+		return nil, fmt.Errorf("%w: no ECVRF suite for %q", ErrUnsupportedCurve, e.name)
+	}
+
+	if err := e.checkNamedCurve(); err != nil {
+		return nil, err
+	}
+
+	return suite, nil
+}
+
+// vrfEncodeToCurve hashes alpha to a point with the try-and-increment method
+// of RFC 9381, section 5.4.1.1: the first hash of the counter that is the
+// x-coordinate of a point gives the point with that x and an even y.
+func (e *ECC) vrfEncodeToCurve(suite *vrfSuite, pub PublicKey, alpha []byte) (Point, error) {
+	pkString := e.pointToString(pub.p)
+
+	for ctr := range 256 {
+		h := suite.hash()
+		h.Write([]byte{suite.suiteString, 0x01})
+		h.Write(pkString)
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), 0x00})
+
+		x := new(big.Int).SetBytes(h.Sum(nil))
+		if !e.inField(x) {
+			continue
+		}
+
+		ys := e.ec.Y(x)
+		if len(ys) == 0 {
+			continue
+		}
+
+		y := ys[0]
+		if y.Bit(0) == 1 {
+			y = ys[len(ys)-1]
+		}
+
+		p := e.ec.NewPoint(x, y).ScalarMul(e.h)
+		if !p.IsInfinity() {
+			return p, nil
+		}
+	}
+
+	return Point{}, fmt.Errorf("%w: no point found for the VRF input", ErrInvalidParameters)
+}
+
+// vrfChallenge returns the challenge c of RFC 9381, section 5.4.3: the first
+// cLen bytes of the hash of the points.
+func (e *ECC) vrfChallenge(suite *vrfSuite, points ...Point) *big.Int {
+	h := suite.hash()
+	h.Write([]byte{suite.suiteString, 0x02})
+	for _, p := range points {
+		h.Write(e.pointToString(p))
+	}
+	h.Write([]byte{0x00})
+
+	return new(big.Int).SetBytes(h.Sum(nil)[:suite.cLen])
+}
+
+func (e *ECC) vrfProofToHash(suite *vrfSuite, gamma Point) []byte {
+	h := suite.hash()
+	h.Write([]byte{suite.suiteString, 0x03})
+	h.Write(e.pointToString(gamma.ScalarMul(e.h)))
+	h.Write([]byte{0x00})
+
+	return h.Sum(nil)
+}
+
+// vrfDecodeProof splits the proof pi into Gamma, c and s.
+func (e *ECC) vrfDecodeProof(suite *vrfSuite, pi []byte) (Point, *big.Int, *big.Int, error) {
+	ptLen := e.CoordinateSize() + 1
+	if len(pi) != ptLen+suite.cLen+e.ScalarSize() {
+		return Point{}, nil, nil, fmt.Errorf("%w: invalid length", ErrInvalidVRFProof)
+	}
+
+	gamma, err := e.NewPublicKeyCompressed(pi[:ptLen])
+	if err != nil {
+		return Point{}, nil, nil, fmt.Errorf("%w: invalid Gamma: %w", ErrInvalidVRFProof, err)
+	}
+
+	c := new(big.Int).SetBytes(pi[ptLen : ptLen+suite.cLen])
+	s := new(big.Int).SetBytes(pi[ptLen+suite.cLen:])
+	if s.Cmp(e.n) >= 0 {
+		return Point{}, nil, nil, fmt.Errorf("%w: s out of range", ErrInvalidVRFProof)
+	}
+
+	return gamma.p, c, s, nil
+}
+
+// pointToString returns the compressed SEC1 encoding of a point other than ∞.
+func (e *ECC) pointToString(p Point) []byte {
+	return PublicKey{p: p, ecc: e}.Compressed()
+}
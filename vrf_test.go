@@ -0,0 +1,167 @@
+package becc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestVRFProve(t *testing.T) {
+	// RFC 9381, appendix B.1, ECVRF-P256-SHA256-TAI
+	tt := []struct {
+		sk, pk string
+		alpha  string
+		pi     string
+		beta   string
+	}{
+		{
+			sk:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+			pk:    "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+			alpha: "sample",
+			pi:    "035b5c726e8c0e2c488a107c600578ee75cb702343c153cb1eb8dec77f4b5071b4a53f0a46f018bc2c56e58d383f2305e0975972c26feea0eb122fe7893c15af376b33edf7de17c6ea056d4d82de6bc02f",
+			beta:  "a3ad7b0ef73d8fc6655053ea22f9bede8c743f08bbed3d38821f0e16474b505e",
+		},
+		{
+			sk:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+			pk:    "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+			alpha: "test",
+			pi:    "034dac60aba508ba0c01aa9be80377ebd7562c4a52d74722e0abae7dc3080ddb56c19e067b15a8a8174905b13617804534214f935b94c2287f797e393eb0816969d864f37625b443f30f1a5a33f2b3c854",
+			beta:  "a284f94ceec2ff4b3794629da7cbafa49121972671b466cab4ce170aa365f26d",
+		},
+	}
+
+	ecc := Secp256r1ECC()
+
+	for _, tc := range tt {
+		t.Run(tc.alpha, func(t *testing.T) {
+			priv, err := ecc.NewPrivateKey(bigIntHex(t, tc.sk))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if pk := hex.EncodeToString(priv.PublicKey().Compressed()); pk != tc.pk {
+				t.Fatalf("got public key %s, expected %s", pk, tc.pk)
+			}
+
+			pi, err := priv.VRFProve([]byte(tc.alpha))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(pi, hexBytes(t, tc.pi)) {
+				t.Errorf("got pi %x, expected %s", pi, tc.pi)
+			}
+
+			beta, err := priv.PublicKey().VRFVerify([]byte(tc.alpha), pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(beta, hexBytes(t, tc.beta)) {
+				t.Errorf("got beta %x, expected %s", beta, tc.beta)
+			}
+
+			beta, err = ecc.VRFProofToHash(pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(beta, hexBytes(t, tc.beta)) {
+				t.Errorf("proof to hash: got %x, expected %s", beta, tc.beta)
+			}
+		})
+	}
+}
+
+func TestVRFVerify(t *testing.T) {
+	for _, name := range []string{"secp256k1", "secp256r1"} {
+		t.Run(name, func(t *testing.T) {
+			ecc := mustLookupCurve(name)
+
+			priv, pub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			alpha := []byte("round 42")
+
+			pi, err := priv.VRFProve(alpha)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(pi) != ecc.VRFProofSize() {
+				t.Errorf("got a proof of %d bytes, expected %d", len(pi), ecc.VRFProofSize())
+			}
+
+			beta, err := pub.VRFVerify(alpha, pi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// the output is unique: proving again gives the same proof
+			again, err := priv.VRFProve(alpha)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(again, pi) {
+				t.Errorf("got a different proof for the same input")
+			}
+
+			other, err := priv.VRFProve([]byte("round 43"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if otherBeta, _ := ecc.VRFProofToHash(other); bytes.Equal(otherBeta, beta) {
+				t.Errorf("got the same output for different inputs")
+			}
+
+			_, otherPub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			tampered := bytes.Clone(pi)
+			tampered[len(tampered)-1] ^= 1
+
+			invalid := []struct {
+				name  string
+				pub   PublicKey
+				alpha []byte
+				pi    []byte
+			}{
+				{name: "other input", pub: pub, alpha: []byte("round 43"), pi: pi},
+				{name: "other key", pub: otherPub, alpha: alpha, pi: pi},
+				{name: "other proof", pub: pub, alpha: alpha, pi: other},
+				{name: "tampered", pub: pub, alpha: alpha, pi: tampered},
+				{name: "truncated", pub: pub, alpha: alpha, pi: pi[:len(pi)-1]},
+			}
+
+			for _, tc := range invalid {
+				if _, err := tc.pub.VRFVerify(tc.alpha, tc.pi); !errors.Is(err, ErrInvalidVRFProof) {
+					t.Errorf("%s: got %v, expected ErrInvalidVRFProof", tc.name, err)
+				}
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		ecc := mustLookupCurve("secp384r1")
+
+		priv, _, err := ecc.GenKeyPair()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := priv.VRFProve([]byte("abc")); !errors.Is(err, ErrUnsupportedCurve) {
+			t.Errorf("got %v, expected ErrUnsupportedCurve", err)
+		}
+
+		if ecc.VRFProofSize() != 0 {
+			t.Errorf("got proof size %d, expected 0", ecc.VRFProofSize())
+		}
+	})
+}
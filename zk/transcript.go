@@ -0,0 +1,93 @@
+package zk
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/artilugio0/becc"
+)
+
+// challengeDST is the domain separation tag of the challenges.
+const challengeDST = "BECC-ZK-V01-CHALLENGE"
+
+// Transcript is the record of a Fiat-Shamir proof: everything the prover and
+// the verifier agree on is appended to it, and the challenges are derived
+// from all of it, so that a proof is bound to its statement and context.
+//
+// Every message is written with its label, both prefixed with their length,
+// so that different sequences of messages never produce the same transcript.
+// The prover and the verifier must build their transcripts with the same
+// label and messages, in the same order.
+type Transcript struct {
+	data []byte
+}
+
+// NewTranscript returns a transcript for the protocol or application named by
+// label.
+func NewTranscript(label string) *Transcript {
+	t := &Transcript{}
+	t.AppendMessage("becc zk transcript v1", []byte(label))
+
+	return t
+}
+
+// AppendMessage appends msg to the transcript under label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	t.data = binary.BigEndian.AppendUint32(t.data, uint32(len(label)))
+	t.data = append(t.data, label...)
+	t.data = binary.BigEndian.AppendUint32(t.data, uint32(len(msg)))
+	t.data = append(t.data, msg...)
+}
+
+// AppendPoint appends the SEC 1 compressed encoding of p to the transcript
+// under label, or a single zero byte for the point at infinity.
+func (t *Transcript) AppendPoint(label string, ecc *becc.ECC, p becc.Point) {
+	t.AppendMessage(label, encodePoint(ecc, p))
+}
+
+// AppendCurve appends the domain parameters of ecc to the transcript.
+func (t *Transcript) AppendCurve(ecc *becc.ECC) {
+	ec := ecc.Curve()
+
+	t.AppendMessage("curve", []byte(ecc.Name()))
+	t.AppendMessage("p", ec.P().Bytes())
+	t.AppendMessage("a", ec.A().Bytes())
+	t.AppendMessage("b", ec.B().Bytes())
+	t.AppendPoint("G", ecc, ecc.Generator())
+	t.AppendMessage("n", ecc.Order().Bytes())
+}
+
+// ChallengeScalar returns a challenge in [0, n-1] derived from the transcript
+// so far and appends it to the transcript, so that later challenges depend on
+// it. It expands the transcript with expand_message_xmd (RFC 9380) and
+// SHA-256 to 16 bytes more than n needs before reducing it, which leaves a
+// negligible bias.
+func (t *Transcript) ChallengeScalar(label string, n *big.Int) *big.Int {
+	t.AppendMessage("challenge", []byte(label))
+
+	length := (n.BitLen()+7)/8 + 16
+	uniform, err := becc.ExpandMessageXMD(becc.SHA256, t.data, []byte(challengeDST), length)
+	if err != nil {
+		// the length is valid for any n of up to 8000 bits
+		panic(err)
+	}
+
+	c := new(big.Int).SetBytes(uniform)
+	c.Mod(c, n)
+
+	t.AppendMessage(label, c.Bytes())
+
+	return c
+}
+
+func encodePoint(ecc *becc.ECC, p becc.Point) []byte {
+	if p.IsInfinity() {
+		return []byte{0}
+	}
+
+	encoded := make([]byte, 1+ecc.CoordinateSize())
+	encoded[0] = byte(2 + p.Y().Bit(0))
+	p.X().FillBytes(encoded[1:])
+
+	return encoded
+}
@@ -0,0 +1,180 @@
+// Package zk implements non-interactive zero-knowledge proofs of discrete
+// logarithms over the curves of becc, made non-interactive with the
+// Fiat-Shamir transform over a Transcript:
+//
+//   - Schnorr proofs of knowledge of the private key of a public key P = x·G
+//     (RFC 8235), which prove ownership of a key without signing any data
+//   - Chaum-Pedersen proofs that two points P = x·G and Q = x·H have the same
+//     discrete logarithm (DLEQ) with respect to the bases G and H
+//
+// Both proofs are a challenge c and a response s, encoded as two scalars of
+// ECC.ScalarSize bytes.
+package zk
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/artilugio0/becc"
+)
+
+var ErrInvalidProof error = errors.New("invalid proof")
+
+// Proof is a proof of the form (c, s), where c is the Fiat-Shamir challenge
+// and s = k + c·x mod n the response for a random nonce k.
+type Proof struct {
+	c, s *big.Int
+	size int
+}
+
+// ParseProof parses a proof encoded by Proof.Bytes for the curve ecc.
+func ParseProof(ecc *becc.ECC, b []byte) (Proof, error) {
+	size := ecc.ScalarSize()
+	if len(b) != 2*size {
+		return Proof{}, fmt.Errorf("%w: invalid length", ErrInvalidProof)
+	}
+
+	c := new(big.Int).SetBytes(b[:size])
+	s := new(big.Int).SetBytes(b[size:])
+	if c.Cmp(ecc.Order()) >= 0 || s.Cmp(ecc.Order()) >= 0 {
+		return Proof{}, fmt.Errorf("%w: scalar out of range", ErrInvalidProof)
+	}
+
+	return Proof{c: c, s: s, size: size}, nil
+}
+
+// Bytes returns the encoding c || s of the proof, with each scalar as a
+// fixed-length big-endian byte string.
+func (p Proof) Bytes() []byte {
+	b := make([]byte, 2*p.size)
+	p.c.FillBytes(b[:p.size])
+	p.s.FillBytes(b[p.size:])
+
+	return b
+}
+
+// ProveKnowledge returns a Schnorr proof that the prover knows the private
+// key of priv.PublicKey(). The statement (the curve and the public key) is
+// appended to t before the challenge is derived, so t only needs to hold the
+// context the proof is bound to.
+func ProveKnowledge(t *Transcript, priv becc.PrivateKey) (Proof, error) {
+	ecc := priv.ECC()
+	g := ecc.Generator()
+
+	k, err := randomNonce(ecc)
+	if err != nil {
+		return Proof{}, err
+	}
+
+	appendKnowledgeStatement(t, ecc, priv.PublicKey().Point())
+	t.AppendPoint("R", ecc, g.ScalarMul(k))
+
+	c := t.ChallengeScalar("c", ecc.Order())
+
+	return Proof{c: c, s: response(ecc, k, c, priv.Int()), size: ecc.ScalarSize()}, nil
+}
+
+// VerifyKnowledge reports whether proof proves knowledge of the private key
+// of pub, for a transcript with the same context as the prover's.
+func VerifyKnowledge(t *Transcript, pub becc.PublicKey, proof Proof) bool {
+	ecc := pub.ECC()
+	if !inRange(ecc, proof) {
+		return false
+	}
+
+	// R = s·G - c·P
+	r := ecc.Generator().ScalarMul(proof.s).Add(pub.Point().ScalarMul(proof.c).Neg())
+
+	appendKnowledgeStatement(t, ecc, pub.Point())
+	t.AppendPoint("R", ecc, r)
+
+	return t.ChallengeScalar("c", ecc.Order()).Cmp(proof.c) == 0
+}
+
+func appendKnowledgeStatement(t *Transcript, ecc *becc.ECC, p becc.Point) {
+	t.AppendMessage("protocol", []byte("schnorr"))
+	t.AppendCurve(ecc)
+	t.AppendPoint("P", ecc, p)
+}
+
+// ProveDLEQ returns Q = x·H for the private key x of priv and a
+// Chaum-Pedersen proof that log_G(P) = log_H(Q), where P is the public key of
+// priv. H must be a valid point of the curve, and nobody should know log_G(H)
+// for the proof to say anything about Q.
+func ProveDLEQ(t *Transcript, priv becc.PrivateKey, h becc.Point) (becc.Point, Proof, error) {
+	ecc := priv.ECC()
+	if err := ecc.ValidatePublicKey(h); err != nil {
+		return becc.Point{}, Proof{}, fmt.Errorf("invalid base: %w", err)
+	}
+
+	k, err := randomNonce(ecc)
+	if err != nil {
+		return becc.Point{}, Proof{}, err
+	}
+
+	q := h.ScalarMul(priv.Int())
+
+	appendDLEQStatement(t, ecc, priv.PublicKey().Point(), h, q)
+	t.AppendPoint("A1", ecc, ecc.Generator().ScalarMul(k))
+	t.AppendPoint("A2", ecc, h.ScalarMul(k))
+
+	c := t.ChallengeScalar("c", ecc.Order())
+
+	return q, Proof{c: c, s: response(ecc, k, c, priv.Int()), size: ecc.ScalarSize()}, nil
+}
+
+// VerifyDLEQ reports whether proof proves that log_G(P) = log_H(Q) for the
+// public key P of pub, for a transcript with the same context as the
+// prover's.
+func VerifyDLEQ(t *Transcript, pub becc.PublicKey, h, q becc.Point, proof Proof) bool {
+	ecc := pub.ECC()
+	if !inRange(ecc, proof) || ecc.ValidatePublicKey(h) != nil || ecc.ValidatePublicKey(q) != nil {
+		return false
+	}
+
+	// A1 = s·G - c·P, A2 = s·H - c·Q
+	a1 := ecc.Generator().ScalarMul(proof.s).Add(pub.Point().ScalarMul(proof.c).Neg())
+	a2 := h.ScalarMul(proof.s).Add(q.ScalarMul(proof.c).Neg())
+
+	appendDLEQStatement(t, ecc, pub.Point(), h, q)
+	t.AppendPoint("A1", ecc, a1)
+	t.AppendPoint("A2", ecc, a2)
+
+	return t.ChallengeScalar("c", ecc.Order()).Cmp(proof.c) == 0
+}
+
+func appendDLEQStatement(t *Transcript, ecc *becc.ECC, p, h, q becc.Point) {
+	t.AppendMessage("protocol", []byte("chaum-pedersen"))
+	t.AppendCurve(ecc)
+	t.AppendPoint("H", ecc, h)
+	t.AppendPoint("P", ecc, p)
+	t.AppendPoint("Q", ecc, q)
+}
+
+// randomNonce returns a uniformly random scalar in [1, n-1].
+func randomNonce(ecc *becc.ECC) (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(ecc.Order(), big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// response returns s = k + c·x mod n.
+func response(ecc *becc.ECC, k, c, x *big.Int) *big.Int {
+	s := new(big.Int).Mul(c, x)
+	s.Add(s, k).Mod(s, ecc.Order())
+
+	return s
+}
+
+func inRange(ecc *becc.ECC, proof Proof) bool {
+	n := ecc.Order()
+
+	return proof.c != nil && proof.s != nil &&
+		proof.c.Sign() >= 0 && proof.c.Cmp(n) < 0 &&
+		proof.s.Sign() >= 0 && proof.s.Cmp(n) < 0
+}
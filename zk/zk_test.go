@@ -0,0 +1,161 @@
+package zk
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/artilugio0/becc"
+)
+
+func TestKnowledgeProof(t *testing.T) {
+	for _, ecc := range []*becc.ECC{becc.Secp256k1ECC(), becc.Secp384r1ECC(), becc.BrainpoolP256r1ECC()} {
+		t.Run(ecc.Name(), func(t *testing.T) {
+			priv, pub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			proof, err := ProveKnowledge(newTranscript("session 1"), priv)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !VerifyKnowledge(newTranscript("session 1"), pub, proof) {
+				t.Errorf("valid proof rejected")
+			}
+
+			parsed, err := ParseProof(ecc, proof.Bytes())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !VerifyKnowledge(newTranscript("session 1"), pub, parsed) {
+				t.Errorf("parsed proof rejected")
+			}
+
+			// the proof is bound to its context and its public key
+			if VerifyKnowledge(newTranscript("session 2"), pub, proof) {
+				t.Errorf("proof accepted in another context")
+			}
+
+			_, otherPub, err := ecc.GenKeyPair()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if VerifyKnowledge(newTranscript("session 1"), otherPub, proof) {
+				t.Errorf("proof accepted for another public key")
+			}
+
+			tampered := Proof{c: proof.c, s: new(big.Int).Add(proof.s, big.NewInt(1)), size: proof.size}
+			if VerifyKnowledge(newTranscript("session 1"), pub, tampered) {
+				t.Errorf("tampered proof accepted")
+			}
+		})
+	}
+}
+
+func TestDLEQProof(t *testing.T) {
+	ecc := becc.Secp256r1ECC()
+
+	h, err := ecc.HashToCurve([]byte("second base"), []byte("BECC-ZK-TEST"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	priv, pub, err := ecc.GenKeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	q, proof, err := ProveDLEQ(newTranscript("dleq"), priv, h)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !q.Eq(h.ScalarMul(priv.Int())) {
+		t.Errorf("got Q = %v, expected x·H", q)
+	}
+
+	if !VerifyDLEQ(newTranscript("dleq"), pub, h, q, proof) {
+		t.Errorf("valid proof rejected")
+	}
+
+	// Q with another discrete logarithm
+	otherQ := q.Add(h)
+	if VerifyDLEQ(newTranscript("dleq"), pub, h, otherQ, proof) {
+		t.Errorf("proof accepted for (x+1)·H")
+	}
+
+	// another base
+	if VerifyDLEQ(newTranscript("dleq"), pub, ecc.Generator(), q, proof) {
+		t.Errorf("proof accepted for another base")
+	}
+
+	// a knowledge proof is not a DLEQ proof
+	knowledge, err := ProveKnowledge(newTranscript("dleq"), priv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if VerifyDLEQ(newTranscript("dleq"), pub, h, q, knowledge) {
+		t.Errorf("knowledge proof accepted as a DLEQ proof")
+	}
+
+	if _, _, err := ProveDLEQ(newTranscript("dleq"), priv, ecc.Curve().Infinity()); !errors.Is(err, becc.ErrInvalidPublicKey) {
+		t.Errorf("got %v, expected ErrInvalidPublicKey", err)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	n := becc.Secp256k1ECC().Order()
+
+	challenge := func(messages ...string) *big.Int {
+		tr := NewTranscript("test")
+		for i := 0; i < len(messages); i += 2 {
+			tr.AppendMessage(messages[i], []byte(messages[i+1]))
+		}
+
+		return tr.ChallengeScalar("c", n)
+	}
+
+	if challenge("a", "bc").Cmp(challenge("a", "bc")) != 0 {
+		t.Errorf("the challenge is not deterministic")
+	}
+
+	// the labels and messages are length-prefixed
+	if challenge("a", "bc").Cmp(challenge("ab", "c")) == 0 {
+		t.Errorf("different messages give the same challenge")
+	}
+
+	if challenge("a", "b", "c", "d").Cmp(challenge("a", "bcd")) == 0 {
+		t.Errorf("different messages give the same challenge")
+	}
+
+	// each challenge is appended to the transcript
+	tr := NewTranscript("test")
+	if tr.ChallengeScalar("c", n).Cmp(tr.ChallengeScalar("c", n)) == 0 {
+		t.Errorf("consecutive challenges are equal")
+	}
+}
+
+func TestParseProof(t *testing.T) {
+	ecc := becc.Secp256k1ECC()
+
+	if _, err := ParseProof(ecc, make([]byte, 63)); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("got %v, expected ErrInvalidProof", err)
+	}
+
+	outOfRange := append(ecc.Order().FillBytes(make([]byte, 32)), make([]byte, 32)...)
+	if _, err := ParseProof(ecc, outOfRange); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("got %v, expected ErrInvalidProof", err)
+	}
+}
+
+func newTranscript(context string) *Transcript {
+	t := NewTranscript("becc zk test")
+	t.AppendMessage("context", []byte(context))
+
+	return t
+}